@@ -48,10 +48,13 @@ func AddRoundCmd(traceID string, log *zap.SugaredLogger, cfg algod.Config, couch
 		return errors.Wrap(err, "convert raw bytes to block data")
 	}
 
-	blockCore.AddBlock(ctx, newBlock)
+	docID, _, err := blockCore.AddBlock(ctx, newBlock)
 	if err != nil {
 		return errors.Wrap(err, "can't add new block")
 	}
+	if err := blockCore.CommitBlock(ctx, docID); err != nil {
+		return errors.Wrap(err, "can't commit new block")
+	}
 
 	//if err := schema.Migrate(ctx, db); err != nil {
 	//	return errors.Wrap(err, "migrate couchdb database")
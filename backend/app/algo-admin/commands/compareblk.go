@@ -2,11 +2,9 @@ package commands
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	algod2 "github.com/kevguy/algosearch/backend/business/core/algod"
-	indexerApp "github.com/kevguy/algosearch/backend/business/core/indexer"
+	"github.com/kevguy/algosearch/backend/app/algosearch/blocksynchronizer"
 	"github.com/kevguy/algosearch/backend/foundation/algod"
 	"github.com/kevguy/algosearch/backend/foundation/indexer"
 	"github.com/nsf/jsondiff"
@@ -15,70 +13,51 @@ import (
 	"time"
 )
 
-// CompareBlockBetweenAlgodAndIndexer retrieves information about the block for the specified round and prints it out in JSON format.
+// CompareBlockBetweenAlgodAndIndexer retrieves the block for the specified round from both
+// the algod and the Indexer block sources and prints the differences between them. The
+// proposer and block hash are left out, the Indexer doesn't know about them.
 func CompareBlockBetweenAlgodAndIndexer(traceID string, log *zap.SugaredLogger, algodCfg algod.Config, indexerCfg indexer.Config, blockNum uint64) error {
 	algodClient, err := algod.Open(algodCfg)
 	if err != nil {
 		return errors.Wrap(err, "connect to Algorand Node")
 	}
 
-	algodCore := algod2.NewCore(log, algodClient)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	algodBlock, err := algodCore.GetRound(ctx, traceID, blockNum)
-	//rawBlock, err := app.GetRoundInRawBytes(ctx, client, blockNum)
-	if err != nil {
-		return errors.Wrap(err, "getting current round from Algorand Node")
-	}
-
-	algodBlockBytes, err := json.Marshal(algodBlock)
-	if err != nil {
-		return errors.Wrap(err, "marshaling JSON")
-	}
-
 	indexerClient, err := indexer.Open(indexerCfg)
 	if err != nil {
 		return errors.Wrap(err, "connect to Indexer")
 	}
 
-	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	indexerCore := indexerApp.NewCore(log, indexerClient)
+	log.Infow("compare-round-algod-indexer", "traceid", traceID, "round", blockNum)
 
-	indexerBlock, err := indexerCore.GetRound(ctx, traceID, blockNum)
-	//rawBlock, err := app.GetRoundInRawBytes(ctx, client, blockNum)
-	if err != nil {
-		return errors.Wrap(err, "getting current round from Indexer")
+	sources := []blocksynchronizer.BlockSource{
+		blocksynchronizer.NewAlgodSource(log, algodClient),
+		blocksynchronizer.NewIndexerSource(log, indexerClient),
 	}
 
-	fmt.Println("Algorand")
-	fmt.Println("Genesish Hash")
-	fmt.Println(algodBlock.Transactions[0].GenesisHash)
-	fmt.Println(base64.StdEncoding.EncodeToString(algodBlock.Transactions[0].GenesisHash))
-	fmt.Println("Indexer")
-	fmt.Println("Genesish Hash")
-	fmt.Println(indexerBlock.Transactions[0].GenesisHash)
-	fmt.Println(base64.StdEncoding.EncodeToString(indexerBlock.Transactions[0].GenesisHash))
-
-	fmt.Println(indexerBlock.Transactions[0].Id)
-
-	indexerBlockBytes, err := json.Marshal(indexerBlock)
-	if err != nil {
-		return errors.Wrap(err, "marshaling JSON")
+	var blocksBytes [][]byte
+	for _, source := range sources {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		sourceBlock, err := source.Block(ctx, blockNum)
+		cancel()
+		if err != nil {
+			return errors.Wrapf(err, "getting round %d from %s", blockNum, source.Name())
+		}
+
+		blockBytes, err := json.Marshal(sourceBlock.Block)
+		if err != nil {
+			return errors.Wrap(err, "marshaling JSON")
+		}
+		blocksBytes = append(blocksBytes, blockBytes)
 	}
 
 	diffOpts := jsondiff.DefaultConsoleOptions()
-	//res, diff := jsondiff.Compare(algodBlockBytes, indexerBlockBytes, &diffOpts)
-	res, diff := jsondiff.Compare(indexerBlockBytes, algodBlockBytes, &diffOpts)
+	res, diff := jsondiff.Compare(blocksBytes[1], blocksBytes[0], &diffOpts)
 
 	if res != jsondiff.FullMatch {
-		fmt.Println("Come on come on")
 		fmt.Printf("%s\n", diff)
-		return errors.Wrapf(nil, "the expected result is not equal to what we have: %s", diff)
+		return errors.Errorf("round %d differs between %s and %s", blockNum, sources[0].Name(), sources[1].Name())
 	}
 
+	fmt.Printf("Round %d is the same on %s and %s\n", blockNum, sources[0].Name(), sources[1].Name())
 	return nil
 }
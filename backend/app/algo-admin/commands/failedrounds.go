@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kevguy/algosearch/backend/app/algosearch/blocksynchronizer"
+	"github.com/kevguy/algosearch/backend/foundation/algod"
+	"github.com/kevguy/algosearch/backend/foundation/couchdb"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// GetFailedRoundsCmd prints every round the block synchronizer recorded as failed.
+func GetFailedRoundsCmd(log *zap.SugaredLogger, cfg algod.Config, couchCfg couchdb.Config, dbName string) error {
+
+	client, err := algod.Open(cfg)
+	if err != nil {
+		return errors.Wrap(err, "connect to Algorand Node")
+	}
+
+	db, err := couchdb.Open(couchCfg)
+	if err != nil {
+		return errors.Wrap(err, "connect to couchdb database")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer db.Close(ctx)
+	defer cancel()
+
+	in := blocksynchronizer.NewIngester(log, blocksynchronizer.DefaultRetryPolicy, blocksynchronizer.NewAlgodSource(log, client), client, db, dbName)
+
+	failedRounds, err := in.GetFailedRounds(ctx)
+	if err != nil {
+		return errors.Wrap(err, "can't get failed rounds")
+	}
+
+	fmt.Printf("%d failed round(s)\n", len(failedRounds))
+	for _, fr := range failedRounds {
+		fmt.Printf("\t- Round %d: %d attempt(s), last failed at %s: %s\n", fr.Round, fr.Attempts, fr.LastFailedAt.Format(time.RFC3339), fr.Error)
+	}
+
+	return nil
+}
+
+// RedriveFailedRoundsCmd ingests the rounds recorded as failed again. Only the
+// given round is re-driven when round is not nil.
+func RedriveFailedRoundsCmd(log *zap.SugaredLogger, cfg algod.Config, couchCfg couchdb.Config, dbName string, round *uint64) error {
+
+	client, err := algod.Open(cfg)
+	if err != nil {
+		return errors.Wrap(err, "connect to Algorand Node")
+	}
+
+	db, err := couchdb.Open(couchCfg)
+	if err != nil {
+		return errors.Wrap(err, "connect to couchdb database")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Second)
+	defer db.Close(ctx)
+	defer cancel()
+
+	in := blocksynchronizer.NewIngester(log, blocksynchronizer.DefaultRetryPolicy, blocksynchronizer.NewAlgodSource(log, client), client, db, dbName)
+
+	var rounds []uint64
+	if round != nil {
+		rounds = append(rounds, *round)
+	} else {
+		failedRounds, err := in.GetFailedRounds(ctx)
+		if err != nil {
+			return errors.Wrap(err, "can't get failed rounds")
+		}
+		for _, fr := range failedRounds {
+			rounds = append(rounds, fr.Round)
+		}
+	}
+
+	var failures int
+	for _, r := range rounds {
+		if err := in.RedriveFailedRound(ctx, r); err != nil {
+			log.Errorw("redrive", "round", r, "ERROR", err)
+			failures++
+			continue
+		}
+		fmt.Printf("Re-drove round %d\n", r)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d round(s) failed again", failures, len(rounds))
+	}
+
+	return nil
+}
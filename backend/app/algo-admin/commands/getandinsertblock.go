@@ -16,6 +16,9 @@ import (
 	"time"
 )
 
+// GetAndInsertBlockCmd retrieves the rounds fromBlock to toBlock and saves them to CouchDB,
+// overwriting the rounds already stored. Rounds that fail are reported once every round
+// has been tried.
 func GetAndInsertBlockCmd(log *zap.SugaredLogger, cfg algod.Config, couchCfg couchdb.Config, dbName string, fromBlock uint64, toBlock uint64) error {
 
 	client, err := algod.Open(cfg)
@@ -40,6 +43,7 @@ func GetAndInsertBlockCmd(log *zap.SugaredLogger, cfg algod.Config, couchCfg cou
 	assetCore := asset.NewCore(log, db, dbName)
 	appCore := application.NewCore(log, db, dbName)
 
+	var failed []uint64
 	for i := fromBlock; i <= toBlock; i++ {
 		if err := blocksynchronizer.GetAndInsertBlockData(
 			log,
@@ -51,11 +55,15 @@ func GetAndInsertBlockCmd(log *zap.SugaredLogger, cfg algod.Config, couchCfg cou
 			&appCore,
 			&algodCore,
 			i); err != nil {
-			//return err
-			log.Errorf("Failed to add Block Number %d\n", i)
+			log.Errorw("get-and-insert-blocks", "status", "failed to add round", "round", i, "ERROR", err)
+			failed = append(failed, i)
+			continue
 		}
 		log.Infof("Added Block Number %d\n", i)
 	}
 
+	if len(failed) > 0 {
+		return errors.Errorf("%d of %d rounds could not be added: %v", len(failed), toBlock-fromBlock+1, failed)
+	}
 	return nil
 }
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/algod"
+	"github.com/kevguy/algosearch/backend/app/algosearch/blocksynchronizer"
+	"github.com/kevguy/algosearch/backend/business/core/syncstate"
+	algodfd "github.com/kevguy/algosearch/backend/foundation/algod"
+	"github.com/kevguy/algosearch/backend/foundation/couchdb"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// ImportBlocksCmd ingests the msgpack block files found in dir through the same path
+// the block synchronizer uses. The accounts, assets and applications the rounds touch
+// are looked up on algod unless offline is set. When the imported rounds follow the
+// last synced round, the last synced round is moved past them.
+func ImportBlocksCmd(log *zap.SugaredLogger, cfg algodfd.Config, couchCfg couchdb.Config, dbName string, dir string, offline bool) error {
+
+	rounds, err := blocksynchronizer.ArchiveRounds(dir)
+	if err != nil {
+		return err
+	}
+	if len(rounds) == 0 {
+		fmt.Printf("No block files found in %s\n", dir)
+		return nil
+	}
+
+	var client *algod.Client
+	if !offline {
+		client, err = algodfd.Open(cfg)
+		if err != nil {
+			return errors.Wrap(err, "connect to Algorand Node")
+		}
+	}
+
+	db, err := couchdb.Open(couchCfg)
+	if err != nil {
+		return errors.Wrap(err, "connect to couchdb database")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 600000*time.Second)
+	defer db.Close(ctx)
+	defer cancel()
+
+	// A file that can't be read or converted won't get better on a retry.
+	retry := blocksynchronizer.RetryPolicy{Attempts: 1}
+	in := blocksynchronizer.NewIngester(log, retry, blocksynchronizer.NewArchiveSource(dir), client, db, dbName)
+	syncStateCore := syncstate.NewCore(log, db, dbName)
+
+	state, found, err := syncStateCore.GetSyncState(ctx)
+	if err != nil {
+		return errors.Wrap(err, "can't get sync state")
+	}
+	found = found && !state.NothingSynced
+	var next uint64
+	if found {
+		next = state.LastSyncedRound + 1
+	}
+	contiguous := true
+
+	var failures int
+	for _, round := range rounds {
+		if _, err := in.IngestRound(ctx, round); err != nil {
+			log.Errorw("import-blocks", "round", round, "ERROR", err)
+			failures++
+			contiguous = false
+			continue
+		}
+
+		switch {
+		case round < next:
+		case round == next && contiguous:
+			next++
+		default:
+			contiguous = false
+		}
+	}
+
+	if next > 0 && (!found || next-1 > state.LastSyncedRound) {
+		if err := syncStateCore.SetLastSyncedRound(ctx, next-1); err != nil {
+			return errors.Wrap(err, "can't record last synced round")
+		}
+		fmt.Printf("Last synced round is now %d\n", next-1)
+	}
+
+	fmt.Printf("Imported %d of %d round(s) from %s\n", len(rounds)-failures, len(rounds), dir)
+	if failures > 0 {
+		return fmt.Errorf("%d round(s) could not be imported", failures)
+	}
+
+	return nil
+}
@@ -3,7 +3,9 @@ package commands
 import (
 	"context"
 	"fmt"
+	"github.com/kevguy/algosearch/backend/business/data/dbschema"
 	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
 	"github.com/kevguy/algosearch/backend/foundation/couchdb"
 	"github.com/pkg/errors"
 	"time"
@@ -12,8 +14,10 @@ import (
 // ErrHelp provides context that help was given.
 var ErrHelp = errors.New("provided help")
 
-// Migrate creates the schema in the database.
-func Migrate(cfg couchdb.Config, dbName string) error {
+// Migrate creates the database if it doesn't exist and brings its design
+// documents up to date. Unless inPlace is set, changed views are built before
+// they replace the old ones so queries aren't blocked while they are indexed.
+func Migrate(cfg couchdb.Config, dbName string, inPlace bool) error {
 	db, err := couchdb.Open(cfg)
 	if err != nil {
 		return fmt.Errorf("connect database: %w", err)
@@ -23,7 +27,58 @@ func Migrate(cfg couchdb.Config, dbName string) error {
 	defer db.Close(ctx)
 	defer cancel()
 
-	if err := schema.Migrate(ctx, db, dbName); err != nil {
+	if err := schema.Migrate(ctx, db, dbName, inPlace); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+
+	fmt.Println("migrations complete")
+	return nil
+}
+
+// MigrateStatus prints how the design documents of the database compare to the
+// ones of this release. With dryRun, it also prints what Migrate would do with
+// each of them.
+func MigrateStatus(cfg couchdb.Config, dbName string, dryRun bool) error {
+	db, err := couchdb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer db.Close(ctx)
+	defer cancel()
+
+	statuses, err := schema.Status(ctx, db, dbName)
+	if err != nil {
+		return fmt.Errorf("database status: %w", err)
+	}
+
+	for _, status := range statuses {
+		line := fmt.Sprintf("%-28s stored: v%d  wanted: v%d  %s", status.ID, status.StoredVersion, status.Version, status.State)
+		if status.Staging {
+			line += "  (staging in progress)"
+		}
+		if dryRun {
+			line += "  -> " + status.Action()
+		}
+		fmt.Println(line)
+	}
+
+	return nil
+}
+
+// MigratePostgres creates the schema in the PostgreSQL database.
+func MigratePostgres(cfg database.Config) error {
+	db, err := database.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Second)
+	defer cancel()
+
+	if err := dbschema.Migrate(ctx, db); err != nil {
 		return fmt.Errorf("migrate database: %w", err)
 	}
 
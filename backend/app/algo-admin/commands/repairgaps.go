@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kevguy/algosearch/backend/app/algosearch/blocksynchronizer"
+	"github.com/kevguy/algosearch/backend/business/core/account"
+	algod2 "github.com/kevguy/algosearch/backend/business/core/algod"
+	"github.com/kevguy/algosearch/backend/business/core/application"
+	"github.com/kevguy/algosearch/backend/business/core/asset"
+	"github.com/kevguy/algosearch/backend/business/core/block"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	"github.com/kevguy/algosearch/backend/foundation/algod"
+	"github.com/kevguy/algosearch/backend/foundation/couchdb"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// RepairGapsCmd finds the rounds missing below the last synced round and fetches
+// them from algod again.
+func RepairGapsCmd(log *zap.SugaredLogger, cfg algod.Config, couchCfg couchdb.Config, dbName string) error {
+
+	client, err := algod.Open(cfg)
+	if err != nil {
+		return errors.Wrap(err, "connect to Algorand Node")
+	}
+
+	algodCore := algod2.NewCore(log, client)
+
+	db, err := couchdb.Open(couchCfg)
+	if err != nil {
+		return errors.Wrap(err, "connect to couchdb database")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 600000*time.Second)
+	defer db.Close(ctx)
+	defer cancel()
+
+	blockCore := block.NewCore(log, db, dbName)
+	transactionCore := transaction.NewCore(log, db, dbName)
+	accountCore := account.NewCore(log, db, dbName)
+	assetCore := asset.NewCore(log, db, dbName)
+	appCore := application.NewCore(log, db, dbName)
+
+	gaps, err := blockCore.GetRoundGaps(ctx)
+	if err != nil {
+		return errors.Wrap(err, "can't scan for gaps")
+	}
+	if len(gaps) == 0 {
+		fmt.Println("No gaps found")
+		return nil
+	}
+
+	var repaired, failures uint64
+	for _, gap := range gaps {
+		fmt.Printf("Repairing rounds %d to %d\n", gap.From, gap.To)
+		for i := gap.From; i <= gap.To; i++ {
+			if err := blocksynchronizer.GetAndInsertBlockData(
+				log,
+				client,
+				&blockCore,
+				&transactionCore,
+				&accountCore,
+				&assetCore,
+				&appCore,
+				&algodCore,
+				i); err != nil {
+				log.Errorf("Failed to add Block Number %d\n", i)
+				failures++
+				continue
+			}
+			repaired++
+		}
+	}
+
+	fmt.Printf("Repaired %d round(s) in %d gap(s)\n", repaired, len(gaps))
+	if failures > 0 {
+		return fmt.Errorf("%d round(s) could not be repaired", failures)
+	}
+
+	return nil
+}
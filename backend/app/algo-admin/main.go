@@ -3,8 +3,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"github.com/kevguy/algosearch/backend/app/algo-admin/commands"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
 	"github.com/kevguy/algosearch/backend/foundation/algod"
 	"github.com/kevguy/algosearch/backend/foundation/couchdb"
 	"github.com/kevguy/algosearch/backend/foundation/indexer"
@@ -70,6 +72,15 @@ func run(log *zap.SugaredLogger) error {
 			Host       string `conf:"default:localhost:5984"`
 			Name	   string `conf:"default:algo_global"`
 		}
+		DB struct {
+			User         string `conf:"default:postgres"`
+			Password     string `conf:"default:postgres,mask"`
+			Host         string `conf:"default:localhost"`
+			Name         string `conf:"default:postgres"`
+			MaxIdleConns int    `conf:"default:0"`
+			MaxOpenConns int    `conf:"default:0"`
+			DisableTLS   bool   `conf:"default:true"`
+		}
 		Algorand struct {
 			AlgodProtocol	string `conf:"default:http,env:ALGOD_PROTOCOL"`
 			AlgodAddr		string `conf:"default:localhost:4001,env:ALGOD_ADDR"`
@@ -132,8 +143,17 @@ func run(log *zap.SugaredLogger) error {
 		IndexerToken: cfg.Algorand.IndexerToken,
 	}
 
+	dbConfig := database.Config{
+		User:         cfg.DB.User,
+		Password:     cfg.DB.Password,
+		Host:         cfg.DB.Host,
+		Name:         cfg.DB.Name,
+		MaxIdleConns: cfg.DB.MaxIdleConns,
+		MaxOpenConns: cfg.DB.MaxOpenConns,
+		DisableTLS:   cfg.DB.DisableTLS,
+	}
 
-	return processCommands(cfg.Args, log, couchConfig, cfg.CouchDB.Name, algorandConfig, indexerConfig)
+	return processCommands(cfg.Args, log, couchConfig, cfg.CouchDB.Name, dbConfig, algorandConfig, indexerConfig)
 }
 
 // processCommands handles the execution of the commands specified on
@@ -142,6 +162,7 @@ func processCommands(args conf.Args,
 	log *zap.SugaredLogger,
 	couchConfig couchdb.Config,
 	dbName string,
+	dbConfig database.Config,
 	algorandConfig algod.Config,
 	indexerConfig indexer.Config) error {
 
@@ -346,11 +367,81 @@ func processCommands(args conf.Args,
 			return fmt.Errorf("get transactions data from db %w", err)
 		}
 
+	case "get-failed-rounds":
+		if err := commands.GetFailedRoundsCmd(log, algorandConfig, couchConfig, dbName); err != nil {
+			return fmt.Errorf("get failed rounds: %w", err)
+		}
+
+	case "redrive-failed-rounds":
+		var round *uint64
+		if numStr := args.Num(1); numStr != "" {
+			num, err := strconv.ParseUint(numStr, 10, 64)
+			if err != nil {
+				return fmt.Errorf("num arg format wrong: %w", err)
+			}
+			round = &num
+		}
+		if err := commands.RedriveFailedRoundsCmd(log, algorandConfig, couchConfig, dbName, round); err != nil {
+			return fmt.Errorf("redrive failed rounds: %w", err)
+		}
+
+	case "repair-gaps":
+		if err := commands.RepairGapsCmd(log, algorandConfig, couchConfig, dbName); err != nil {
+			return fmt.Errorf("repair gaps: %w", err)
+		}
+
+	case "import-blocks":
+		fs := flag.NewFlagSet("import-blocks", flag.ContinueOnError)
+		dir := fs.String("dir", "", "directory holding the msgpack block files")
+		offline := fs.Bool("offline", false, "don't look up accounts, assets and applications on algod")
+		if err := fs.Parse(args[1:]); err != nil {
+			return fmt.Errorf("import-blocks args format wrong: %w", err)
+		}
+		if *dir == "" {
+			return errors.New("import-blocks needs --dir")
+		}
+		if err := commands.ImportBlocksCmd(log, algorandConfig, couchConfig, dbName, *dir, *offline); err != nil {
+			return fmt.Errorf("import blocks: %w", err)
+		}
+
+	case "export-blocks":
+		fs := flag.NewFlagSet("export-blocks", flag.ContinueOnError)
+		from := fs.Uint64("from", 0, "first round to export")
+		to := fs.Uint64("to", 0, "last round to export")
+		dir := fs.String("dir", "", "directory to write the msgpack block files to")
+		if err := fs.Parse(args[1:]); err != nil {
+			return fmt.Errorf("export-blocks args format wrong: %w", err)
+		}
+		if *dir == "" {
+			return errors.New("export-blocks needs --dir")
+		}
+		if err := commands.ExportBlocksCmd(log, algorandConfig, *from, *to, *dir); err != nil {
+			return fmt.Errorf("export blocks: %w", err)
+		}
+
 	case "migrate":
-		if err := commands.Migrate(couchConfig, dbName); err != nil {
+		fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+		status := fs.Bool("status", false, "print the state of the design documents and exit")
+		dryRun := fs.Bool("dry-run", false, "print what would be migrated without changing anything")
+		inPlace := fs.Bool("in-place", false, "update changed views in place instead of building them first")
+		if err := fs.Parse(args[1:]); err != nil {
+			return fmt.Errorf("migrate args format wrong: %w", err)
+		}
+		if *status || *dryRun {
+			if err := commands.MigrateStatus(couchConfig, dbName, *dryRun); err != nil {
+				return fmt.Errorf("migration status: %w", err)
+			}
+			return nil
+		}
+		if err := commands.Migrate(couchConfig, dbName, *inPlace); err != nil {
 			return fmt.Errorf("migrating database: %w", err)
 		}
 
+	case "migrate-postgres":
+		if err := commands.MigratePostgres(dbConfig); err != nil {
+			return fmt.Errorf("migrating postgres database: %w", err)
+		}
+
 	default:
 		fmt.Println("get-txn-info-from-db: get general information about the transactions from the database")
 		fmt.Println("add-current-round: add the current round to the database")
@@ -359,7 +450,13 @@ func processCommands(args conf.Args,
 		fmt.Println("get-round: get a round and print it nicely")
 		fmt.Println("get-round-from-db: get a round from the database")
 		fmt.Println("get-last-synced-round-num: get the round number of the last block synced to the database")
-		fmt.Println("migrate: create the schema in the CouchDB database")
+		fmt.Println("get-failed-rounds: list the rounds the block synchronizer failed to ingest")
+		fmt.Println("redrive-failed-rounds: ingest the failed rounds again, or only the given round")
+		fmt.Println("repair-gaps: fetch the rounds missing below the last synced round again")
+		fmt.Println("import-blocks: ingest the msgpack block files in --dir, add --offline to skip algod lookups")
+		fmt.Println("export-blocks: write rounds --from to --to from algod as msgpack block files to --dir")
+		fmt.Println("migrate: create or update the schema in the CouchDB database [--status] [--dry-run] [--in-place]")
+		fmt.Println("migrate-postgres: create the schema in the PostgreSQL database")
 		fmt.Println("provide a command to get more help.")
 		return commands.ErrHelp
 	}
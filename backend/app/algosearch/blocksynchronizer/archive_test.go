@@ -0,0 +1,45 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestArchiveSource(t *testing.T) {
+	t.Log("Given the need to read rounds from a directory of block files.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the directory holds block files and other files.", testID)
+		{
+			dir := t.TempDir()
+			for _, name := range []string{ArchiveFileName(12), ArchiveFileName(3), ArchiveFileName(100), "README.md", "7.msgpack.tmp"} {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte{}, 0644); err != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to write %s : %v.", failed, testID, name, err)
+				}
+			}
+
+			rounds, err := ArchiveRounds(dir)
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to list the rounds : %v.", failed, testID, err)
+			}
+			if want := []uint64{3, 12, 100}; !reflect.DeepEqual(rounds, want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list the rounds in ascending order : got %v, want %v.", failed, testID, rounds, want)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould list the rounds in ascending order.", success, testID)
+
+			current, err := NewArchiveSource(dir).CurrentRound(context.Background())
+			if err != nil || current != 100 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould report the highest round as current : got %d, %v.", failed, testID, current, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould report the highest round as current.", success, testID)
+
+			if _, err := NewArchiveSource(dir).Block(context.Background(), 4); err == nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould fail to read a round missing from the archive.", failed, testID)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould fail to read a round missing from the archive.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// roundTracker records rounds that complete out of order and keeps track of
+// the highest round below which every round has completed.
+type roundTracker struct {
+	from uint64
+	next uint64
+	done map[uint64]bool
+}
+
+// newRoundTracker constructs a roundTracker expecting from to be the first
+// round to complete.
+func newRoundTracker(from uint64) *roundTracker {
+	return &roundTracker{
+		from: from,
+		next: from,
+		done: make(map[uint64]bool),
+	}
+}
+
+// complete marks round as completed. It returns the highest round of the
+// unbroken run starting at the first expected round, and false if that run
+// is still empty.
+func (t *roundTracker) complete(round uint64) (uint64, bool) {
+	if round >= t.next {
+		t.done[round] = true
+	}
+	for t.done[t.next] {
+		delete(t.done, t.next)
+		t.next++
+	}
+	return t.committed()
+}
+
+// committed returns the highest round of the unbroken run of completed
+// rounds, and false if no round has completed yet.
+func (t *roundTracker) committed() (uint64, bool) {
+	if t.next == t.from {
+		return 0, false
+	}
+	return t.next - 1, true
+}
+
+// catchUp backfills rounds in batches starting at from until the synchronizer
+// is within CatchupThreshold rounds of the tip, then hands control back to
+// tip-following.
+func (p *BlockSynchronizer) catchUp(ctx context.Context, from uint64, tip uint64) {
+	p.log.Infow("blocksynchronizer", "status", "catch-up started", "from", from, "tip", tip)
+
+	for tip >= from && tip-from >= p.cfg.CatchupThreshold {
+		to := from + p.cfg.CatchupBatchSize - 1
+		if to > tip {
+			to = tip
+		}
+
+		// Every round before the one that fails, if any, is handled.
+		failedRound := from
+		last, ok, err := p.backfill(ctx, from, to)
+		if ok {
+			failedRound = last + 1
+			if err := p.setLastSyncedRound(ctx, last); err != nil {
+				p.log.Errorw("blocksynchronizer", "status", "can't record last synced round", "ERROR", err)
+				return
+			}
+			from = last + 1
+		}
+		if err != nil {
+			p.log.Errorw("blocksynchronizer", "status", "catch-up batch failed", "round", failedRound, "to", to, "ERROR", err)
+			if ctx.Err() == nil {
+				p.reportError(err)
+			}
+			return
+		}
+		p.log.Infow("blocksynchronizer", "status", "catch-up batch done", "last synced round", last, "tip", tip)
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		currentRoundNum, err := p.source.CurrentRound(ctx)
+		if err != nil {
+			p.log.Errorw("blocksynchronizer", "status", "get current round num", "ERROR", err)
+			p.reportError(err)
+			return
+		}
+		tip = p.target(currentRoundNum)
+	}
+
+	p.log.Infow("blocksynchronizer", "status", "catch-up finished", "next round", from, "tip", tip)
+}
+
+// backfill ingests rounds from..to using a bounded pool of workers. Rounds left
+// pending are completed in round order as the unbroken run of ingested rounds
+// grows, those that can't be completed are recorded as failed. It returns the
+// highest round below which every round in the range was persisted or recorded
+// as failed, and false if not even the first round was. Scheduling stops at
+// the first round that could neither be ingested nor recorded as failed, and
+// its error is returned.
+func (p *BlockSynchronizer) backfill(ctx context.Context, from uint64, to uint64) (uint64, bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		round   uint64
+		payload WsMessage
+		pending *pendingRound
+		err     error
+	}
+
+	rounds := make(chan uint64)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	wg.Add(p.cfg.CatchupWorkers)
+	for i := 0; i < p.cfg.CatchupWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for round := range rounds {
+				payload, pending, err := p.ingestRound(ctx, round)
+				results <- result{round: round, payload: payload, pending: pending, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(rounds)
+		for round := from; round <= to; round++ {
+			select {
+			case rounds <- round:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	tracker := newRoundTracker(from)
+	pending := make(map[uint64]result)
+	completeFrom := from
+	complete := func(last uint64, ok bool) {
+		for ; ok && completeFrom <= last; completeFrom++ {
+			res, found := pending[completeFrom]
+			if !found {
+				continue
+			}
+			delete(pending, completeFrom)
+			if err := p.completeRound(ctx, *res.pending, &res.payload); err != nil {
+				p.log.Errorw("blocksynchronizer", "status", "can't complete round", "round", res.round, "ERROR", err)
+				if ctx.Err() == nil {
+					p.reportError(err)
+				}
+				p.recordFailure(ctx, res.round, err)
+			}
+		}
+	}
+
+	var firstErr error
+	var failedRound uint64
+	for res := range results {
+		if res.err != nil {
+			if p.recordFailure(ctx, res.round, res.err) {
+				complete(tracker.complete(res.round))
+				continue
+			}
+			if firstErr == nil || res.round < failedRound {
+				firstErr = res.err
+				failedRound = res.round
+			}
+			cancel()
+			continue
+		}
+		if res.pending != nil {
+			pending[res.round] = res
+		}
+		complete(tracker.complete(res.round))
+	}
+
+	last, ok := tracker.committed()
+	if firstErr != nil {
+		return last, ok, fmt.Errorf("ingesting round %d: %w", failedRound, firstErr)
+	}
+	return last, ok, nil
+}
@@ -0,0 +1,61 @@
+package blocksynchronizer
+
+import (
+	"testing"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestRoundTracker(t *testing.T) {
+	t.Log("Given the need to commit rounds that complete out of order.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen rounds 10 to 14 complete out of order.", testID)
+		{
+			tracker := newRoundTracker(10)
+
+			if _, ok := tracker.committed(); ok {
+				t.Fatalf("\t\t%s\tTest %d:\tShould have nothing committed before any round completes.", failed, testID)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould have nothing committed before any round completes.", success, testID)
+
+			steps := []struct {
+				round uint64
+				last  uint64
+				ok    bool
+			}{
+				{12, 0, false},
+				{11, 0, false},
+				{10, 12, true},
+				{14, 12, true},
+				{13, 14, true},
+			}
+			for _, step := range steps {
+				last, ok := tracker.complete(step.round)
+				if last != step.last || ok != step.ok {
+					t.Fatalf("\t\t%s\tTest %d:\tShould commit up to %d (%v) after round %d : got %d (%v).", failed, testID, step.last, step.ok, step.round, last, ok)
+				}
+				t.Logf("\t\t%s\tTest %d:\tShould commit up to %d (%v) after round %d.", success, testID, step.last, step.ok, step.round)
+			}
+		}
+
+		testID = 1
+		t.Logf("\tTest %d:\tWhen a round in the middle never completes.", testID)
+		{
+			tracker := newRoundTracker(0)
+			tracker.complete(0)
+			tracker.complete(1)
+			tracker.complete(3)
+
+			last, ok := tracker.committed()
+			if !ok || last != 1 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould stop committing before the missing round : got %d (%v).", failed, testID, last, ok)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould stop committing before the missing round.", success, testID)
+		}
+	}
+}
@@ -1,105 +1,187 @@
 package blocksynchronizer
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/algorand/go-algorand-sdk/client/v2/algod"
-	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
-	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
 	"github.com/kevguy/algosearch/backend/business/core/account"
 	algod2 "github.com/kevguy/algosearch/backend/business/core/algod"
 	"github.com/kevguy/algosearch/backend/business/core/application"
 	"github.com/kevguy/algosearch/backend/business/core/asset"
 	"github.com/kevguy/algosearch/backend/business/core/block"
 	"github.com/kevguy/algosearch/backend/business/core/transaction"
-	"github.com/kevguy/algosearch/backend/foundation/couchdb"
 	"github.com/kevguy/algosearch/backend/foundation/websocket"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
-	"log"
 	"sync"
 	"time"
 )
 
+// Config contains the settings that control how the BlockSynchronizer syncs.
+type Config struct {
+	// Interval is how long to wait between checks for a new round when
+	// waiting on the block source for the next round is disabled or fails.
+	Interval time.Duration
+	// WaitForBlock makes the synchronizer long-poll the block source for the
+	// round after the last synced one, so new rounds are ingested as soon as
+	// they exist. It has no effect on sources that can't be waited on.
+	WaitForBlock bool
+	// CatchupWorkers is the number of rounds ingested concurrently while
+	// backfilling historical rounds.
+	CatchupWorkers int
+	// CatchupThreshold is how many rounds behind the tip the synchronizer
+	// has to be before it switches from tip-following to catch-up mode.
+	CatchupThreshold uint64
+	// CatchupBatchSize is the number of rounds backfilled before the last
+	// synced round is recorded and the tip is checked again.
+	CatchupBatchSize uint64
+	// EnrichWorkers is the number of concurrent lookups made on algod for the
+	// accounts, assets and applications of a round.
+	EnrichWorkers int
+	// StateDeltas makes the synchronizer update accounts, assets and
+	// applications from the state delta algod records for each round, so they
+	// are stored as of the round synced. Rounds whose delta algod no longer
+	// serves fall back to looking the accounts up as they are now.
+	StateDeltas bool
+	// Retry controls how fetching a round from the block source is retried
+	// before the round is recorded as failed.
+	Retry RetryPolicy
+	// GapScanInterval is how often stored rounds are scanned for gaps. Zero
+	// disables the scan.
+	GapScanInterval time.Duration
+	// LeaseTTL is how long the sync lease lasts without being renewed. Only the
+	// instance holding the lease syncs, the others broadcast the rounds it
+	// synced. Zero disables leader election and every instance syncs.
+	LeaseTTL time.Duration
+	// InstanceID identifies this instance as the holder of the sync lease. It
+	// defaults to the host name and process ID.
+	InstanceID string
+	// Start is the round syncing starts at when nothing has been synced yet.
+	Start StartRound
+	// RetentionRounds is how many rounds, counted back from the last synced
+	// one, are kept. Older blocks and transactions are pruned. Zero keeps
+	// every round.
+	RetentionRounds uint64
+	// PruneInterval is how often rounds outside the retention window are
+	// pruned.
+	PruneInterval time.Duration
+}
+
 // BlockSynchronizer provides the ability to retrieve block data
 // on an interval.
 type BlockSynchronizer struct {
-	log             *zap.SugaredLogger
-	wg              sync.WaitGroup
-	timer           *time.Timer
-	shutdown        chan struct{}
-	algodClient     *algod.Client
-	blockCore       *block.Core
-	transactionCore *transaction.Core
-	accountCore     *account.Core
-	assetCore       *asset.Core
-	appCore         *application.Core
-	algodCore       *algod2.Core
-	hub             *websocket.Hub
-	dbName          string
+	Ingester
+	cfg         Config
+	wg          sync.WaitGroup
+	timer       *time.Timer
+	shutdown    chan struct{}
+	ctx         context.Context
+	cancel      context.CancelFunc
+	algodClient *algod.Client
+	hub         *websocket.Hub
+	leader      leadership
+	control     control
+
+	// broadcastRound is the last round broadcast to websocket clients. Only
+	// the sync goroutine touches it.
+	broadcastRound uint64
+	broadcastSet   bool
 }
 
-// New creates a BlockSynchronizer for retrieving block data and saving it to CouchDB.
-func New(log *zap.SugaredLogger, interval time.Duration, algodClient *algod.Client, cfg couchdb.Config, hub *websocket.Hub, dbName string) (*BlockSynchronizer, error) {
+// New creates a BlockSynchronizer for retrieving block data from source and
+// saving it with the given cores.
+func New(log *zap.SugaredLogger, cfg Config, source BlockSource, algodClient *algod.Client, cores Cores, hub *websocket.Hub) (*BlockSynchronizer, error) {
+	if cfg.CatchupWorkers < 1 {
+		cfg.CatchupWorkers = 1
+	}
+	if cfg.CatchupBatchSize < 1 {
+		cfg.CatchupBatchSize = 1
+	}
+	if cfg.EnrichWorkers < 1 {
+		cfg.EnrichWorkers = DefaultEnrichWorkers
+	}
+	if cfg.InstanceID == "" {
+		cfg.InstanceID = defaultInstanceID()
+	}
+	if cfg.RetentionRounds > 0 && cfg.PruneInterval <= 0 {
+		cfg.PruneInterval = time.Hour
+	}
+
+	ingester := NewIngesterWithCores(log, cfg.Retry, source, algodClient, cores)
+	ingester.enrichWorkers = cfg.EnrichWorkers
+	ingester.stateDeltas = cfg.StateDeltas
+
+	ctx, cancel := context.WithCancel(context.Background())
 	p := BlockSynchronizer{
-		log:         log,
-		timer:       time.NewTimer(interval),
+		Ingester:    ingester,
+		cfg:         cfg,
+		timer:       time.NewTimer(cfg.Interval),
 		shutdown:    make(chan struct{}),
+		ctx:         ctx,
+		cancel:      cancel,
 		algodClient: algodClient,
 		hub:         hub,
-		dbName:      dbName,
 	}
 
-	db, err := couchdb.Open(cfg)
-	if err != nil {
-		return nil, errors.Wrap(err, "connect to couchdb database")
-	}
-
-	algodCore := algod2.NewCore(log, algodClient)
-	p.algodCore = &algodCore
-
-	blockStore := block.NewCore(log, db, dbName)
-	p.blockCore = &blockStore
+	if cfg.LeaseTTL > 0 {
+		p.campaign()
 
-	transactionStore := transaction.NewCore(log, db, dbName)
-	p.transactionCore = &transactionStore
-
-	accountStore := account.NewCore(log, db, dbName)
-	p.accountCore = &accountStore
-
-	assetStore := asset.NewCore(log, db, dbName)
-	p.assetCore = &assetStore
-
-	appStore := application.NewCore(log, db, dbName)
-	p.appCore = &appStore
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.runElection()
+		}()
+	}
 
 	p.wg.Add(1)
 	go func() {
 		defer p.wg.Done()
 		for {
-			p.timer.Reset(interval)
+			if ctx, ok := p.leaderContext(); ok {
+				if p.syncRun(ctx) {
+					continue
+				}
+			} else {
+				p.follow()
+			}
+
+			p.timer.Reset(cfg.Interval)
 			select {
 			case <-p.timer.C:
-				p.update()
 			case <-p.shutdown:
 				return
 			}
 		}
 	}()
 
+	if cfg.GapScanInterval > 0 {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.runGapScanner()
+		}()
+	}
+
+	if cfg.RetentionRounds > 0 {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.runPruner()
+		}()
+	}
+
 	return &p, nil
 }
 
 // Stop is used to shutdown the goroutine for syncing block data.
 func (p *BlockSynchronizer) Stop() {
+	p.cancel()
 	close(p.shutdown)
 	p.wg.Wait()
 }
 
 func (p *BlockSynchronizer) broadcastUpdate(newInfo WsMessage) error {
-	fmt.Println(newInfo)
 	res, err := json.Marshal(newInfo)
 	if err != nil {
 		return fmt.Errorf("marshaling new block info for broadcasting: %w", err)
@@ -108,200 +190,106 @@ func (p *BlockSynchronizer) broadcastUpdate(newInfo WsMessage) error {
 	return nil
 }
 
-// TODO: add retry
-// update pulls the block data and saves it to CouchDB.
-func (p *BlockSynchronizer) update() {
-
-	lastSyncedBlockNum, found, err := p.blockCore.GetLastSyncedRoundNumber(context.Background())
+// lastSyncedRound returns the last round recorded as synced. Databases synced
+// before the sync state was recorded fall back to the highest stored round.
+func (p *BlockSynchronizer) lastSyncedRound(ctx context.Context) (uint64, bool, error) {
+	state, found, err := p.syncStateCore.GetSyncState(ctx)
 	if err != nil {
-		p.log.Errorw("blocksynchronizer", "status", "get last synced round number", "ERROR", err)
-		//lastSyncedBlockNum = -1
+		return 0, false, err
 	}
-	// 111983 is amazing, it has a a ton of transactions
-	//lastSyncedBlockNum = 111980
-
-	currentRoundNum, err := p.algodCore.GetCurrentRoundNum(context.Background())
-	if err != nil {
-		p.log.Errorw("blocksynchronizer", "status", "get current round num", "ERROR", err)
+	if found {
+		return state.LastSyncedRound, !state.NothingSynced, nil
 	}
-	p.log.Infof("Current Round Number is %d", currentRoundNum)
-
-	p.log.Infow("Updating latest round here", "last synced round", lastSyncedBlockNum)
-
-	if (currentRoundNum - lastSyncedBlockNum) > 0 {
-		p.log.Infof("Trying to get round number: %d\n", lastSyncedBlockNum+1)
+	return p.blockCore.GetLastSyncedRoundNumber(ctx)
+}
 
-		var blockOfInterest uint64 = 0
-		if !found {
-			blockOfInterest = 0
-		} else {
-			blockOfInterest = lastSyncedBlockNum + 1
-		}
+// syncRun makes a sync run. Once the tip is reached it long-polls the block
+// source for the next round, and returns true when it did so the next run can
+// start right away instead of after the interval.
+func (p *BlockSynchronizer) syncRun(ctx context.Context) bool {
+	ctx, ok := p.startRun(ctx)
+	if !ok {
+		return false
+	}
+	defer p.endRun()
 
-		var newBlockPayload = WsMessage{
-			Block:           models.Block{},
-			TransactionList: nil,
-			AccountList:     nil,
-			AssetList:       nil,
-			AppList:         nil,
-		}
+	last, atTip := p.update(ctx)
+	return atTip && p.cfg.WaitForBlock && p.waitForRoundAfter(ctx, last)
+}
 
-		getRoundSuccessful := false
-		var rawBlock []byte
-		for !getRoundSuccessful {
-			rawBlock, err = p.algodCore.GetRoundInRawBytes(context.Background(), blockOfInterest)
-			if err != nil {
-				p.log.Errorw("blocksynchronizer", "status", "get round in raw bytes", "ERROR", err)
-				// Assuming it's not just block data not available, jump to the next round
-				lastSyncedBlockNum += 1
-			} else {
-				getRoundSuccessful = true
-				p.log.Infof("Block data for round #%d retrieved.", lastSyncedBlockNum+1)
-				//app.PrintBlockInfoFromRawBytes(rawBlock)
-			}
+// waitForRoundAfter long-polls the block source until it has a round after the
+// given one. It returns false if the source could not be waited on, in which
+// case the caller falls back to polling on the interval.
+func (p *BlockSynchronizer) waitForRoundAfter(ctx context.Context, round uint64) bool {
+	if _, err := p.source.WaitForRoundAfter(ctx, round); err != nil {
+		if ctx.Err() == nil && !errors.Is(err, ErrWaitNotSupported) {
+			p.log.Errorw("blocksynchronizer", "status", "wait for block", "round", round, "ERROR", err)
 		}
+		return false
+	}
+	return true
+}
 
-		//fmt.Printf("raw block: %v\n", rawBlock)
-		//fmt.Printf("last synced num: %d\n", lastSyncedBlockNum + 1)
-		p.log.Infof("Adding Round #%d\n", lastSyncedBlockNum+1)
+// update pulls the block data and saves it to CouchDB. When the synchronizer
+// has fallen too far behind the tip it backfills concurrently, otherwise it
+// follows the tip one round at a time and broadcasts every new round. Rounds
+// past the stop-at round are left alone. It returns the tip and true when every
+// round up to the tip has been handled. It stops early once ctx is done.
+func (p *BlockSynchronizer) update(ctx context.Context) (uint64, bool) {
+	// Only a missing sync state falls back to the start round: on any other
+	// error the saved marker could be moved backward by a catch-up from there.
+	lastSyncedBlockNum, found, err := p.lastSyncedRound(ctx)
+	if err != nil {
+		p.log.Errorw("blocksynchronizer", "status", "get last synced round number", "ERROR", err)
+		p.reportError(err)
+		return 0, false
+	}
 
-		newBlock, err := algod2.ConvertBlockRawBytes(context.Background(), rawBlock)
-		if err != nil {
-			p.log.Errorw("blocksynchronizer", "status", "convert raw bytes to block data", "ERROR", err)
-		}
-		//p.log.Infof("Block data for round %d: %v\n", newBlock.Round, newBlock)
-		p.log.Infof("Block data for round %d after processing:", newBlock.Round)
-		payloadStr, err := json.Marshal(newBlock.Block)
-		if err != nil {
-			p.log.Errorw("blocksynchronizer", "status", "marshal new block bytes to block data", "ERROR", err)
-		}
+	tip, err := p.source.CurrentRound(ctx)
+	if err != nil {
+		p.log.Errorw("blocksynchronizer", "status", "get current round num", "ERROR", err)
+		p.reportError(err)
+		return 0, false
+	}
+	currentRoundNum := p.target(tip)
+	p.log.Infow("blocksynchronizer", "current round", tip, "target round", currentRoundNum, "last synced round", lastSyncedBlockNum)
 
-		newBlockPayload.Block = newBlock.Block
+	var nextRound = p.cfg.Start.resolve(tip)
+	if found {
+		nextRound = lastSyncedBlockNum + 1
+	}
+	if nextRound > currentRoundNum {
+		// Only wait on the source when there is nothing left to sync because
+		// the tip has been reached, not the stop-at round.
+		return currentRoundNum, currentRoundNum == tip
+	}
 
-		var prettyJSON bytes.Buffer
-		err = json.Indent(&prettyJSON, payloadStr, "", "\t")
-		if err != nil {
-			log.Println("JSON parse error: ", err)
-		}
-		fmt.Println("Pretty pretty print json!!:", prettyJSON.String())
-		//indexer.PrintBlockInfoFromJsonBlock(newBlock.Block)
+	if currentRoundNum-nextRound >= p.cfg.CatchupThreshold {
+		p.catchUp(ctx, nextRound, currentRoundNum)
+		return 0, false
+	}
 
-		var response models.BlockResponse
-		err = msgpack.Decode(rawBlock, &response)
-		if err != nil {
-			p.log.Errorw("blocksynchronizer", "status", "can't parse block from msgpack format", "ERROR", err)
-		}
-		var blockInfo = response.Block
-
-		//docID, rev, err := field.BlockStore.AddBlock(ctx, newBlock)
-		fmt.Println("NEW BLOCK")
-		fmt.Println("NEW BLOCK")
-		fmt.Println("NEW BLOCK")
-		fmt.Println("NEW BLOCK")
-		fmt.Println("NEW BLOCK")
-		fmt.Println("NEW BLOCK")
-		fmt.Println("NEW BLOCK")
-		fmt.Println("NEW BLOCK")
-		fmt.Printf("%+v\n\n", newBlock)
-		blockDocID, blockDocRev, err := p.blockCore.AddBlock(context.Background(), newBlock)
+	for round := nextRound; round <= currentRoundNum; round++ {
+		newBlockPayload, err := p.IngestRound(ctx, round)
 		if err != nil {
-			p.log.Errorw("blocksynchronizer", "status", "can't add new block", "ERROR", err)
-		}
-		p.log.Infof("Added block %s with rev %s to CouchDB Block table", blockDocID, blockDocRev)
-
-		var accountList []models.Account
-		var assetList []models.Asset
-		var appList []models.Application
-		var txnIDList []string
-
-		if len(newBlock.Transactions) > 0 {
-
-			for _, txn := range newBlock.Transactions {
-				txnIDList = append(txnIDList, txn.Id)
-			}
-			newBlockPayload.TransactionList = txnIDList
-
-			_, err = p.transactionCore.AddTransactions(context.Background(), newBlock.Transactions, blockInfo)
-			if err != nil {
-				p.log.Errorw("blocksynchronizer", "status", "can't add new transaction(s)", "ERROR", err)
-			}
-			p.log.Infof("Added %d transactions with block %s to CouchDB Transaction table", len(newBlock.Transactions), newBlock.BlockHash)
-
-			for _, txn := range newBlock.Transactions {
-
-				accountIDs := algod2.ExtractAccountAddrsFromTxn(txn)
-				applicationIDs := algod2.ExtractApplicationIdsFromTxn(txn)
-				assetIDs := algod2.ExtractAssetIdsFromTxn(txn, blockInfo)
-
-				for _, acctID := range accountIDs {
-					accountInfo, err := p.algodCore.GetAccount(context.Background(), "", acctID)
-					if err != nil {
-						p.log.Errorw("blocksynchronizer", "status", "can't get account", "ERROR", err)
-					}
-					p.log.Infof("Retrieved Account info: %v", *accountInfo)
-					accountList = append(accountList, *accountInfo)
-				}
-
-				for _, appID := range applicationIDs {
-					appInfo, err := p.algodCore.GetApplication(context.Background(), "", appID)
-					if err != nil {
-						p.log.Errorw("blocksynchronizer", "status", "can't get app", "ERROR", err)
-					}
-					p.log.Infof("Retrieved Application info: %v", *appInfo)
-					appList = append(appList, *appInfo)
-				}
-
-				for _, assetID := range assetIDs {
-					assetInfo, err := p.algodCore.GetAsset(context.Background(), "", assetID)
-					if err != nil {
-						p.log.Errorw("blocksynchronizer", "status", "can't get asset", "ERROR", err)
-					}
-					p.log.Infof("Retrieved Asset info: %v", *assetInfo)
-					assetList = append(assetList, *assetInfo)
-				}
+			p.log.Errorw("blocksynchronizer", "status", "can't ingest round", "round", round, "ERROR", err)
+			if ctx.Err() == nil {
+				p.reportError(err)
 			}
-		}
-
-		if len(accountList) > 0 {
-			_, err = p.accountCore.AddAccounts(context.Background(), accountList)
-			if err != nil {
-				p.log.Errorw("blocksynchronizer", "status", "can't add/update account(s)", "ERROR", err)
+			if !p.recordFailure(ctx, round, err) {
+				return 0, false
 			}
-
-			var acctIDList []string
-			for _, acct := range accountList {
-				acctIDList = append(acctIDList, acct.Address)
+			if err := p.setLastSyncedRound(ctx, round); err != nil {
+				p.log.Errorw("blocksynchronizer", "status", "can't record last synced round", "ERROR", err)
 			}
-			newBlockPayload.AccountList = acctIDList
+			continue
 		}
 
-		if len(assetList) > 0 {
-			_, err = p.assetCore.AddAssets(context.Background(), assetList)
-			if err != nil {
-				p.log.Errorw("blocksynchronizer", "status", "can't add/update asset(s)", "ERROR", err)
-			}
-
-			var assetIDList []uint64
-			for _, asset := range assetList {
-				assetIDList = append(assetIDList, asset.Index)
-			}
-			newBlockPayload.AssetList = assetIDList
+		if err := p.setLastSyncedRound(ctx, round); err != nil {
+			p.log.Errorw("blocksynchronizer", "status", "can't record last synced round", "ERROR", err)
 		}
 
-		if len(appList) > 0 {
-			_, err = p.appCore.AddApplications(context.Background(), appList)
-			if err != nil {
-				p.log.Errorw("blocksynchronizer", "status", "can't add/update application(s)", "ERROR", err)
-			}
-
-			var appIDList []uint64
-			for _, app := range appList {
-				appIDList = append(appIDList, app.Id)
-			}
-			newBlockPayload.AppList = appIDList
-		}
-
-		speed, err := p.blockCore.GetBlockTxnSpeed(context.Background())
+		speed, err := p.blockCore.GetBlockTxnSpeed(ctx)
 		if err != nil {
 			p.log.Errorw("blocksynchronizer", "status", "can't find block txn speed", "ERROR", err)
 		}
@@ -310,19 +298,13 @@ func (p *BlockSynchronizer) update() {
 		if err = p.broadcastUpdate(newBlockPayload); err != nil {
 			p.log.Errorw("blocksynchronizer", "status", "can't broadcast block update through websocket", "ERROR", err)
 		}
-
-		//for _, transaction := range newBlock.Transactions {
-		//	fmt.Println("Got transaction")
-		//	fmt.Printf("%v\n", transaction)
-		//	transactionDocId, transactionDocRev, err := p.transactionCore.AddTransaction(context.Background(), transaction)
-		//	if err != nil {
-		//		p.log.Errorw("blocksynchronizer", "status", "can't add new transaction", "ERROR", err)
-		//	}
-		//	p.log.Infof("\t\t- Added transaction %s with rev %s to CouchDB Transaction table\n", transactionDocId, transactionDocRev)
-		//}
+		p.broadcastRound, p.broadcastSet = round, true
 	}
+
+	return currentRoundNum, currentRoundNum == tip
 }
 
+// GetAndInsertBlockData retrieves a round from algod and saves it to CouchDB.
 func GetAndInsertBlockData(
 	log *zap.SugaredLogger,
 	algodClient *algod.Client,
@@ -333,137 +315,25 @@ func GetAndInsertBlockData(
 	appCore *application.Core,
 	algodCore *algod2.Core,
 	blockNum uint64) error {
-	log.Infof("Trying to get round number: %d\n", blockNum)
-
-	getRoundSuccessful := false
-	var rawBlock []byte
-	var err error
-	for !getRoundSuccessful {
-		rawBlock, err = algodCore.GetRoundInRawBytes(context.Background(), blockNum)
-		if err != nil {
-			log.Errorw("blocksynchronizer", "status", "get round in raw bytes", "ERROR", err)
-			// Assuming it's not just block data not available, jump to the next round
-			blockNum += 1
-		} else {
-			getRoundSuccessful = true
-			log.Infof("Block data for round #%d retrieved.", blockNum)
-			//app.PrintBlockInfoFromRawBytes(rawBlock)
-		}
-	}
-
-	//fmt.Printf("raw block: %v\n", rawBlock)
-	//fmt.Printf("last synced num: %d\n", lastSyncedBlockNum + 1)
-	log.Infof("Adding Round #%d\n", blockNum)
-
-	newBlock, err := algod2.ConvertBlockRawBytes(context.Background(), rawBlock)
-	if err != nil {
-		log.Errorw("blocksynchronizer", "status", "convert raw bytes to block data", "ERROR", err)
-		return err
-	}
-	//p.log.Infof("Block data for round %d: %v\n", newBlock.Round, newBlock)
-	log.Infof("Block data for round %d after processing:", newBlock.Round)
-	payloadStr, err := json.Marshal(newBlock.Block)
-	if err != nil {
-		log.Errorw("blocksynchronizer", "status", "marshal new block bytes to block data", "ERROR", err)
-		return err
-	}
-
-	var prettyJSON bytes.Buffer
-	err = json.Indent(&prettyJSON, payloadStr, "", "\t")
-	if err != nil {
-		fmt.Println("JSON parse error: ", err)
-	}
-	fmt.Println("Pretty pretty print json!!:", prettyJSON.String())
-	//indexer.PrintBlockInfoFromJsonBlock(newBlock.Block)
 
-	var response models.BlockResponse
-	err = msgpack.Decode(rawBlock, &response)
-	if err != nil {
-		log.Errorw("blocksynchronizer", "status", "can't parse block from msgpack format", "ERROR", err)
+	in := Ingester{
+		log:             log,
+		retry:           DefaultRetryPolicy,
+		enrichWorkers:   DefaultEnrichWorkers,
+		stateDeltas:     true,
+		source:          algodSource{algodCore: algodCore},
+		blockCore:       blockCore,
+		transactionCore: transactionCore,
+		accountCore:     accountCore,
+		assetCore:       assetCore,
+		appCore:         appCore,
+		algodCore:       algodCore,
 	}
-	var blockInfo = response.Block
 
-	//docID, rev, err := field.BlockStore.AddBlock(ctx, newBlock)
-	blockDocID, blockDocRev, err := blockCore.AddBlock(context.Background(), newBlock)
-	if err != nil {
-		log.Errorw("blocksynchronizer", "status", "can't add new block", "ERROR", err)
+	if _, err := in.IngestRound(context.Background(), blockNum); err != nil {
+		log.Errorw("blocksynchronizer", "status", "can't ingest round", "round", blockNum, "ERROR", err)
 		return err
 	}
-	log.Infof("Added block %s with rev %s to CouchDB Block table", blockDocID, blockDocRev)
-
-	var accountList []models.Account
-	var assetList []models.Asset
-	var appList []models.Application
-
-	if len(newBlock.Transactions) > 0 {
-		_, err = transactionCore.AddTransactions(context.Background(), newBlock.Transactions, blockInfo)
-		if err != nil {
-			log.Errorw("blocksynchronizer", "status", "can't add new transaction(s)", "ERROR", err)
-			return err
-		}
-		log.Infof("Added %d transactions with block %s to CouchDB Transaction table", len(newBlock.Transactions), newBlock.BlockHash)
-
-		for _, txn := range newBlock.Transactions {
-
-			accountIDs := algod2.ExtractAccountAddrsFromTxn(txn)
-			applicationIDs := algod2.ExtractApplicationIdsFromTxn(txn)
-			assetIDs := algod2.ExtractAssetIdsFromTxn(txn, blockInfo)
-
-			for _, acctID := range accountIDs {
-				accountInfo, err := algodCore.GetAccount(context.Background(), "", acctID)
-				if err != nil {
-					log.Errorw("blocksynchronizer", "status", "can't get account", "ERROR", err)
-					//return err
-				}
-				log.Infof("Retrieved Account info: %v", *accountInfo)
-				accountList = append(accountList, *accountInfo)
-			}
-
-			for _, appID := range applicationIDs {
-				appInfo, err := algodCore.GetApplication(context.Background(), "", appID)
-				if err != nil {
-					log.Errorw("blocksynchronizer", "status", "can't get app", "ERROR", err)
-					//return err
-				}
-				log.Infof("Retrieved Application info: %v", *appInfo)
-				appList = append(appList, *appInfo)
-			}
-
-			for _, assetID := range assetIDs {
-				assetInfo, err := algodCore.GetAsset(context.Background(), "", assetID)
-				if err != nil {
-					log.Errorw("blocksynchronizer", "status", "can't get asset", "ERROR", err)
-					//return err
-				}
-				log.Infof("Retrieved Asset info: %v", *assetInfo)
-				assetList = append(assetList, *assetInfo)
-			}
-		}
-	}
-
-	if len(accountList) > 0 {
-		_, err = accountCore.AddAccounts(context.Background(), accountList)
-		if err != nil {
-			log.Errorw("blocksynchronizer", "status", "can't add/update account(s)", "ERROR", err)
-			//return err
-		}
-	}
-
-	if len(assetList) > 0 {
-		_, err = assetCore.AddAssets(context.Background(), assetList)
-		if err != nil {
-			log.Errorw("blocksynchronizer", "status", "can't add/update asset(s)", "ERROR", err)
-			//return err
-		}
-	}
-
-	if len(appList) > 0 {
-		_, err = appCore.AddApplications(context.Background(), appList)
-		if err != nil {
-			log.Errorw("blocksynchronizer", "status", "can't add/update application(s)", "ERROR", err)
-			//return err
-		}
-	}
 
 	return nil
 }
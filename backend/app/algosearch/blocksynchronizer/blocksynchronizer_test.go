@@ -0,0 +1,207 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/types"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	syncdb "github.com/kevguy/algosearch/backend/business/core/syncstate/db"
+	"github.com/kevguy/algosearch/backend/business/data/store/memory"
+	"github.com/kevguy/algosearch/backend/foundation/websocket"
+	"go.uber.org/zap"
+)
+
+// unreadableSyncState is a memory store whose sync state can't be read, as
+// when CouchDB fails for a moment.
+type unreadableSyncState struct {
+	*memory.Store
+}
+
+func (s unreadableSyncState) GetSyncState(ctx context.Context) (syncdb.SyncState, bool, error) {
+	return syncdb.SyncState{}, false, errors.New("sync state unavailable")
+}
+
+// fakeAlgod is an algod node serving empty blocks up to its tip. Each long-poll
+// for the round after the tip returns once the next round exists.
+type fakeAlgod struct {
+	mu    sync.Mutex
+	tip   uint64
+	waits []uint64
+}
+
+func (f *fakeAlgod) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var round uint64
+	switch {
+	case r.URL.Path == "/v2/status":
+		json.NewEncoder(w).Encode(models.NodeStatus{LastRound: f.tip})
+
+	case sscan(r.URL.Path, "/v2/status/wait-for-block-after/%d", &round):
+		f.waits = append(f.waits, round)
+		if f.tip <= round {
+			f.tip = round + 1
+		}
+		json.NewEncoder(w).Encode(models.NodeStatus{LastRound: f.tip})
+
+	case sscan(r.URL.Path, "/v2/blocks/%d", &round) && round <= f.tip:
+		block := struct {
+			Block types.Block            `codec:"block"`
+			Cert  map[string]interface{} `codec:"cert"`
+		}{
+			Block: types.Block{BlockHeader: types.BlockHeader{Round: types.Round(round)}},
+			Cert:  map[string]interface{}{},
+		}
+		w.Header().Set("Content-Type", "application/msgpack")
+		w.Write(msgpack.Encode(block))
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// sscan reports whether path matches format, scanning its values into args.
+func sscan(path string, format string, args ...interface{}) bool {
+	n, err := fmt.Sscanf(path, format, args...)
+	return err == nil && n == len(args)
+}
+
+// newAlgodSource serves f and returns a block source reading from it.
+func newAlgodSource(t *testing.T, f *fakeAlgod) BlockSource {
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	client, err := algod.MakeClient(srv.URL, "")
+	if err != nil {
+		t.Fatalf("connecting to the fake algod: %v", err)
+	}
+	return NewAlgodSource(zap.NewNop().Sugar(), client)
+}
+
+// newTestSynchronizer constructs a synchronizer syncing from source into
+// store, without starting it.
+func newTestSynchronizer(store Storer, source BlockSource) *BlockSynchronizer {
+	return &BlockSynchronizer{
+		Ingester: NewIngesterWithCores(zap.NewNop().Sugar(), DefaultRetryPolicy, source, nil, NewStoreCores(store)),
+		cfg: Config{
+			CatchupWorkers:   1,
+			CatchupThreshold: 100,
+			CatchupBatchSize: 1,
+		},
+		hub: &websocket.Hub{ExternalBroadcast: make(chan []byte, 100)},
+	}
+}
+
+func TestUpdateWithoutSyncState(t *testing.T) {
+	t.Log("Given the need to keep the last synced round when it can't be read.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen round 5 is recorded as synced and the sync state read fails.", testID)
+		{
+			ctx := context.Background()
+			store := memory.New()
+			if err := store.SetLastSyncedRound(ctx, 5); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to record round 5 : %v.", failed, testID, err)
+			}
+
+			source := staticSource{}
+			for round := uint64(0); round <= 7; round++ {
+				source[round] = SourceBlock{NewBlock: blockdb.NewBlock{Block: models.Block{Round: round}}}
+			}
+			p := newTestSynchronizer(unreadableSyncState{store}, source)
+
+			if _, atTip := p.update(ctx); atTip {
+				t.Fatalf("\t\t%s\tTest %d:\tShould not report the tip as reached.", failed, testID)
+			}
+			if _, err := p.blockCore.GetBlockByNum(ctx, 0); err == nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould not sync from the start round again.", failed, testID)
+			}
+			state, _, err := store.GetSyncState(ctx)
+			if err != nil || state.LastSyncedRound != 5 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould leave the last synced round at 5 : got %d, %v.", failed, testID, state.LastSyncedRound, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould leave the last synced round alone.", success, testID)
+		}
+	}
+}
+
+func TestWaitForBlock(t *testing.T) {
+	t.Log("Given the need to ingest new rounds as soon as algod has them.")
+	{
+		ctx := context.Background()
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the synchronizer reaches the tip.", testID)
+		{
+			store := memory.New()
+			if err := store.SetLastSyncedRound(ctx, 3); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to record round 3 : %v.", failed, testID, err)
+			}
+			node := &fakeAlgod{tip: 5}
+			p := newTestSynchronizer(store, newAlgodSource(t, node))
+			p.cfg.WaitForBlock = true
+
+			if !p.syncRun(ctx) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould wait on algod instead of sleeping.", failed, testID)
+			}
+			if _, err := p.blockCore.GetBlockByNum(ctx, 5); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould sync up to the tip first : %v.", failed, testID, err)
+			}
+			if want := []uint64{5}; !reflect.DeepEqual(node.waits, want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould long-poll for the round after the tip : got %v, want %v.", failed, testID, node.waits, want)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould long-poll algod for the round after the tip.", success, testID)
+
+			if !p.syncRun(ctx) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould wait on algod again.", failed, testID)
+			}
+			if _, err := p.blockCore.GetBlockByNum(ctx, 6); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould sync the round the long-poll returned for : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould sync the new round on the next run.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the synchronizer is catching up.", testID)
+		{
+			node := &fakeAlgod{tip: 150}
+			p := newTestSynchronizer(memory.New(), newAlgodSource(t, node))
+			p.cfg.WaitForBlock = true
+
+			if p.syncRun(ctx) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould not wait on algod.", failed, testID)
+			}
+			if len(node.waits) != 0 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould not long-poll algod : got %v.", failed, testID, node.waits)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould leave the caller to sleep until the next run.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen waiting for blocks is disabled.", testID)
+		{
+			node := &fakeAlgod{tip: 2}
+			p := newTestSynchronizer(memory.New(), newAlgodSource(t, node))
+
+			if p.syncRun(ctx) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould not wait on algod.", failed, testID)
+			}
+			if len(node.waits) != 0 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould not long-poll algod : got %v.", failed, testID, node.waits)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould leave the caller to sleep until the next run.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// rateWindow is how far back synced rounds are counted to work out the sync rate.
+const rateWindow = time.Minute
+
+// ErrNotLeader is returned when a sync run is requested from an instance that
+// doesn't hold the sync lease, and so doesn't sync.
+var ErrNotLeader = errors.New("this instance doesn't hold the sync lease")
+
+// Status describes what the synchronizer of this instance is doing.
+type Status struct {
+	Instance        string     `json:"instance"`
+	Source          string     `json:"source"`
+	Leader          bool       `json:"leader"`
+	Paused          bool       `json:"paused"`
+	LastSyncedRound *uint64    `json:"last_synced_round"`
+	Tip             uint64     `json:"tip"`
+	Lag             uint64     `json:"lag"`
+	RoundsPerSecond float64    `json:"rounds_per_second"`
+	StopAt          *uint64    `json:"stop_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	LastErrorAt     *time.Time `json:"last_error_at,omitempty"`
+}
+
+// progressSample records the last synced round at a point in time.
+type progressSample struct {
+	at    time.Time
+	round uint64
+}
+
+// control holds the operator's settings and what the synchronizer reports back.
+type control struct {
+	mu        sync.Mutex
+	paused    bool
+	stopAt    *uint64
+	restartAt *uint64
+	cancelRun context.CancelFunc
+
+	tip       uint64
+	lastErr   string
+	lastErrAt time.Time
+	progress  []progressSample
+}
+
+// Pause stops syncing right away. The round in progress is interrupted and left
+// uncommitted, so it is synced again once syncing resumes. It has no effect on
+// the rounds other instances sync.
+func (p *BlockSynchronizer) Pause() {
+	p.control.mu.Lock()
+	defer p.control.mu.Unlock()
+
+	p.control.paused = true
+	if p.control.cancelRun != nil {
+		p.control.cancelRun()
+	}
+	p.log.Infow("blocksynchronizer", "status", "paused")
+}
+
+// Resume starts syncing again on the next tick.
+func (p *BlockSynchronizer) Resume() {
+	p.control.mu.Lock()
+	defer p.control.mu.Unlock()
+
+	p.control.paused = false
+	p.log.Infow("blocksynchronizer", "status", "resumed")
+}
+
+// SetStopAt makes the synchronizer sync no further than round. A nil round
+// lifts the limit.
+func (p *BlockSynchronizer) SetStopAt(round *uint64) {
+	p.control.mu.Lock()
+	defer p.control.mu.Unlock()
+
+	if round == nil {
+		p.control.stopAt = nil
+		p.log.Infow("blocksynchronizer", "status", "stop-at round cleared")
+		return
+	}
+	stopAt := *round
+	p.control.stopAt = &stopAt
+	p.log.Infow("blocksynchronizer", "status", "stop-at round set", "round", stopAt)
+}
+
+// RestartFrom makes the synchronizer sync again from round, dropping the round
+// in progress. Rounds already stored are overwritten as they are synced again.
+// Restarting from round 0 starts over from the configured start round. It
+// returns ErrNotLeader when another instance holds the sync lease, as this one
+// would never get to restart.
+func (p *BlockSynchronizer) RestartFrom(round uint64) error {
+	if _, leader := p.leaderContext(); !leader {
+		return ErrNotLeader
+	}
+
+	p.control.mu.Lock()
+	defer p.control.mu.Unlock()
+
+	p.control.restartAt = &round
+	if p.control.cancelRun != nil {
+		p.control.cancelRun()
+	}
+	p.log.Infow("blocksynchronizer", "status", "restart requested", "round", round)
+	return nil
+}
+
+// Status reports what the synchronizer is doing. The last synced round is read
+// from CouchDB, so it is the one recorded by whichever instance syncs.
+func (p *BlockSynchronizer) Status(ctx context.Context) (Status, error) {
+	_, leader := p.leaderContext()
+
+	last, found, err := p.lastSyncedRound(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+
+	p.control.mu.Lock()
+	status := Status{
+		Instance:        p.cfg.InstanceID,
+		Source:          p.source.Name(),
+		Leader:          leader,
+		Paused:          p.control.paused,
+		Tip:             p.control.tip,
+		RoundsPerSecond: p.control.rate(),
+		LastError:       p.control.lastErr,
+	}
+	if p.control.stopAt != nil {
+		stopAt := *p.control.stopAt
+		status.StopAt = &stopAt
+	}
+	if !p.control.lastErrAt.IsZero() {
+		lastErrAt := p.control.lastErrAt
+		status.LastErrorAt = &lastErrAt
+	}
+	p.control.mu.Unlock()
+
+	if status.Tip == 0 {
+		if status.Tip, err = p.source.CurrentRound(ctx); err != nil {
+			return Status{}, err
+		}
+	}
+
+	if found {
+		status.LastSyncedRound = &last
+		if status.Tip > last {
+			status.Lag = status.Tip - last
+		}
+	} else {
+		status.Lag = status.Tip + 1
+	}
+
+	return status, nil
+}
+
+// startRun prepares a sync run under ctx. It applies a pending restart and
+// returns false when the synchronizer is paused. The context returned is
+// cancelled by Pause and RestartFrom, and must be released with endRun.
+func (p *BlockSynchronizer) startRun(ctx context.Context) (context.Context, bool) {
+	p.control.mu.Lock()
+	restartAt := p.control.restartAt
+	p.control.mu.Unlock()
+
+	// The sync state is written without holding mu, so the status and the
+	// other controls don't wait on the database.
+	if restartAt != nil {
+		round := *restartAt
+		var err error
+		if round == 0 {
+			err = p.syncStateCore.ResetSyncState(ctx)
+		} else {
+			err = p.syncStateCore.SetLastSyncedRound(ctx, round-1)
+		}
+		if err != nil {
+			p.log.Errorw("blocksynchronizer", "status", "can't restart", "round", round, "ERROR", err)
+			p.reportError(err)
+			return nil, false
+		}
+		p.broadcastSet = false
+		p.log.Infow("blocksynchronizer", "status", "restarted", "round", round)
+	}
+
+	p.control.mu.Lock()
+	defer p.control.mu.Unlock()
+
+	if restartAt != nil {
+		// A restart requested in the meantime is applied on the next run.
+		if p.control.restartAt == restartAt {
+			p.control.restartAt = nil
+		}
+		p.control.progress = nil
+	}
+
+	if p.control.paused {
+		return nil, false
+	}
+
+	ctx, p.control.cancelRun = context.WithCancel(ctx)
+	return ctx, true
+}
+
+// endRun releases the context of the current sync run.
+func (p *BlockSynchronizer) endRun() {
+	p.control.mu.Lock()
+	defer p.control.mu.Unlock()
+
+	if p.control.cancelRun != nil {
+		p.control.cancelRun()
+		p.control.cancelRun = nil
+	}
+}
+
+// target caps tip to the stop-at round, if one is set, and records tip.
+func (p *BlockSynchronizer) target(tip uint64) uint64 {
+	p.control.mu.Lock()
+	defer p.control.mu.Unlock()
+
+	p.control.tip = tip
+	if p.control.stopAt != nil && *p.control.stopAt < tip {
+		return *p.control.stopAt
+	}
+	return tip
+}
+
+// setLastSyncedRound records round as the last synced round and counts it
+// towards the sync rate.
+func (p *BlockSynchronizer) setLastSyncedRound(ctx context.Context, round uint64) error {
+	if err := p.syncStateCore.SetLastSyncedRound(ctx, round); err != nil {
+		if ctx.Err() == nil {
+			p.reportError(err)
+		}
+		return err
+	}
+
+	p.control.mu.Lock()
+	defer p.control.mu.Unlock()
+
+	now := time.Now()
+	p.control.progress = append(p.control.progress, progressSample{at: now, round: round})
+	for len(p.control.progress) > 2 && now.Sub(p.control.progress[0].at) > rateWindow {
+		p.control.progress = p.control.progress[1:]
+	}
+	return nil
+}
+
+// reportError records err as the last error the synchronizer ran into.
+func (p *BlockSynchronizer) reportError(err error) {
+	p.control.mu.Lock()
+	defer p.control.mu.Unlock()
+
+	p.control.setError(err)
+}
+
+// setError records err as the last error. The caller must hold mu.
+func (c *control) setError(err error) {
+	c.lastErr = err.Error()
+	c.lastErrAt = time.Now().UTC()
+}
+
+// rate returns the rounds synced per second over the recent samples, and zero
+// when nothing was synced lately. The caller must hold mu.
+func (c *control) rate() float64 {
+	if len(c.progress) < 2 {
+		return 0
+	}
+	first, last := c.progress[0], c.progress[len(c.progress)-1]
+	if time.Since(last.at) > rateWindow {
+		return 0
+	}
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 || last.round < first.round {
+		return 0
+	}
+	return float64(last.round-first.round) / elapsed
+}
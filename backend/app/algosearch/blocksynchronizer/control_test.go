@@ -0,0 +1,132 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/data/store/memory"
+)
+
+// roundsUpTo serves rounds 0 to tip.
+func roundsUpTo(tip uint64) staticSource {
+	source := staticSource{}
+	for round := uint64(0); round <= tip; round++ {
+		source[round] = SourceBlock{NewBlock: blockdb.NewBlock{Block: models.Block{Round: round}}}
+	}
+	return source
+}
+
+// run makes a sync run the way the sync goroutine does and returns what update
+// returned, and false when no run was started.
+func run(p *BlockSynchronizer) (uint64, bool, bool) {
+	ctx, ok := p.startRun(context.Background())
+	if !ok {
+		return 0, false, false
+	}
+	defer p.endRun()
+
+	tip, atTip := p.update(ctx)
+	return tip, atTip, true
+}
+
+func TestControl(t *testing.T) {
+	t.Log("Given the need to control the synchronizer while it runs.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen syncing is paused during a run and resumed.", testID)
+		{
+			p := newTestSynchronizer(memory.New(), roundsUpTo(7))
+
+			ctx, ok := p.startRun(context.Background())
+			if !ok {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to start a run.", failed, testID)
+			}
+			p.Pause()
+			if ctx.Err() == nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould interrupt the run in progress.", failed, testID)
+			}
+			p.endRun()
+			if _, _, started := run(p); started {
+				t.Fatalf("\t\t%s\tTest %d:\tShould not start a run while paused.", failed, testID)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould stop syncing right away.", success, testID)
+
+			p.Resume()
+			if tip, atTip, started := run(p); !started || tip != 7 || !atTip {
+				t.Fatalf("\t\t%s\tTest %d:\tShould sync up to the tip once resumed : got %d, %v, %v.", failed, testID, tip, atTip, started)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould sync up to the tip once resumed.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a stop-at round is set and cleared.", testID)
+		{
+			ctx := context.Background()
+			p := newTestSynchronizer(memory.New(), roundsUpTo(7))
+
+			stopAt := uint64(3)
+			p.SetStopAt(&stopAt)
+			if tip, atTip, _ := run(p); tip != 3 || atTip {
+				t.Fatalf("\t\t%s\tTest %d:\tShould stop at round 3 without waiting on the tip : got %d, %v.", failed, testID, tip, atTip)
+			}
+			if last, _, err := p.lastSyncedRound(ctx); err != nil || last != 3 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould record round 3 as the last synced : got %d, %v.", failed, testID, last, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould sync no further than round 3.", success, testID)
+
+			p.SetStopAt(nil)
+			if tip, atTip, _ := run(p); tip != 7 || !atTip {
+				t.Fatalf("\t\t%s\tTest %d:\tShould sync up to the tip once cleared : got %d, %v.", failed, testID, tip, atTip)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould sync up to the tip once cleared.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a restart is requested after round 7 is synced.", testID)
+		{
+			ctx := context.Background()
+			p := newTestSynchronizer(memory.New(), roundsUpTo(7))
+			run(p)
+
+			if err := p.RestartFrom(5); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to restart from round 5 : %v.", failed, testID, err)
+			}
+			if _, ok := p.startRun(ctx); !ok {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to start a run.", failed, testID)
+			}
+			p.endRun()
+			if last, found, err := p.lastSyncedRound(ctx); err != nil || !found || last != 4 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould record round 4 as the last synced : got %d, %v, %v.", failed, testID, last, found, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould sync again from round 5.", success, testID)
+
+			if err := p.RestartFrom(0); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to restart from the start round : %v.", failed, testID, err)
+			}
+			if _, ok := p.startRun(ctx); !ok {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to start a run.", failed, testID)
+			}
+			p.endRun()
+			if _, found, err := p.lastSyncedRound(ctx); err != nil || found {
+				t.Fatalf("\t\t%s\tTest %d:\tShould forget the last synced round : got %v, %v.", failed, testID, found, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould start over from the start round.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen another instance holds the sync lease.", testID)
+		{
+			p := newTestSynchronizer(memory.New(), roundsUpTo(7))
+			p.cfg.LeaseTTL = time.Minute
+
+			if err := p.RestartFrom(5); !errors.Is(err, ErrNotLeader) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould refuse to restart : got %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould refuse to restart.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/go-kivik/kivik/v4"
+	algod2 "github.com/kevguy/algosearch/backend/business/core/algod"
+)
+
+// stateDelta retrieves the state delta of round from algod, retried according
+// to the retry policy.
+func (in Ingester) stateDelta(ctx context.Context, round uint64) (algod2.LedgerStateDelta, error) {
+	var delta algod2.LedgerStateDelta
+	_, err := in.retry.do(ctx, func(ctx context.Context) error {
+		var err error
+		delta, err = in.algodCore.GetLedgerStateDelta(ctx, round)
+		return err
+	})
+	return delta, err
+}
+
+// applyStateDelta applies the state delta of round to the stored accounts,
+// assets and applications and lists the ones it changed in payload. Deltas
+// have to be applied in round order.
+func (in Ingester) applyStateDelta(ctx context.Context, round uint64, delta algod2.LedgerStateDelta, payload *WsMessage) error {
+	state, err := in.loadLedgerState(ctx, delta)
+	if err != nil {
+		return fmt.Errorf("loading the state the delta applies to: %w", err)
+	}
+
+	accountList, assetList, appList := algod2.ApplyStateDelta(round, delta, state)
+	return in.saveEntities(ctx, accountList, assetList, appList, payload)
+}
+
+// loadLedgerState reads the stored accounts, assets and applications the delta
+// changes, concurrently. Those not stored yet are left out.
+func (in Ingester) loadLedgerState(ctx context.Context, delta algod2.LedgerStateDelta) (algod2.LedgerState, error) {
+	state := algod2.LedgerState{
+		Accounts: make(map[string]models.Account),
+		Assets:   make(map[uint64]models.Asset),
+		Apps:     make(map[uint64]models.Application),
+	}
+
+	var mu sync.Mutex
+	var firstErr error
+	record := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	addrs := delta.Addresses()
+	runPool(ctx, len(addrs), in.enrichWorkers, func(ctx context.Context, i int) {
+		acct, err := in.accountCore.GetAccount(ctx, addrs[i])
+		if err != nil {
+			if kivik.StatusCode(err) != http.StatusNotFound {
+				record(err)
+			}
+			return
+		}
+		mu.Lock()
+		state.Accounts[addrs[i]] = acct
+		mu.Unlock()
+	})
+
+	assetIDs := delta.AssetIDs()
+	runPool(ctx, len(assetIDs), in.enrichWorkers, func(ctx context.Context, i int) {
+		asset, err := in.assetCore.GetAsset(ctx, strconv.FormatUint(assetIDs[i], 10))
+		if err != nil {
+			if kivik.StatusCode(err) != http.StatusNotFound {
+				record(err)
+			}
+			return
+		}
+		mu.Lock()
+		state.Assets[assetIDs[i]] = asset
+		mu.Unlock()
+	})
+
+	appIDs := delta.AppIDs()
+	runPool(ctx, len(appIDs), in.enrichWorkers, func(ctx context.Context, i int) {
+		app, err := in.appCore.GetApplication(ctx, strconv.FormatUint(appIDs[i], 10))
+		if err != nil {
+			if kivik.StatusCode(err) != http.StatusNotFound {
+				record(err)
+			}
+			return
+		}
+		mu.Lock()
+		state.Apps[appIDs[i]] = app
+		mu.Unlock()
+	})
+
+	if firstErr != nil {
+		return algod2.LedgerState{}, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return algod2.LedgerState{}, err
+	}
+	return state, nil
+}
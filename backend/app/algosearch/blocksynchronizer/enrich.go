@@ -0,0 +1,150 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+	algod2 "github.com/kevguy/algosearch/backend/business/core/algod"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
+)
+
+// DefaultEnrichWorkers is the number of concurrent lookups made on algod per
+// round when no number is configured.
+const DefaultEnrichWorkers = 8
+
+// roundEntities holds the IDs of the accounts, assets and applications the
+// transactions of a round touch, each listed once in order of appearance.
+type roundEntities struct {
+	accounts []string
+	assets   []uint64
+	apps     []uint64
+}
+
+// collectEntities lists the accounts, assets and applications touched by txns
+// without duplicates. extras holds the fields of the newer transaction types for
+// each entry of txns, if any.
+func collectEntities(txns []models.Transaction, extras []txnfields.TransactionExtras, blockInfo types.Block) roundEntities {
+	var ents roundEntities
+	seenAccounts := make(map[string]bool)
+	seenAssets := make(map[uint64]bool)
+	seenApps := make(map[uint64]bool)
+
+	for i, txn := range txns {
+		accountIDs := algod2.ExtractAccountAddrsFromTxn(txn)
+		if i < len(extras) {
+			accountIDs = append(accountIDs, algod2.ExtractAccountAddrsFromExtras(extras[i])...)
+		}
+		for _, id := range accountIDs {
+			if !seenAccounts[id] {
+				seenAccounts[id] = true
+				ents.accounts = append(ents.accounts, id)
+			}
+		}
+		for _, id := range algod2.ExtractAssetIdsFromTxn(txn, blockInfo) {
+			if !seenAssets[id] {
+				seenAssets[id] = true
+				ents.assets = append(ents.assets, id)
+			}
+		}
+		for _, id := range algod2.ExtractApplicationIdsFromTxn(txn) {
+			if !seenApps[id] {
+				seenApps[id] = true
+				ents.apps = append(ents.apps, id)
+			}
+		}
+	}
+
+	return ents
+}
+
+// runPool calls fn for every index in [0, n) on at most workers goroutines and
+// waits for all calls to return. Indexes not started by the time ctx is done
+// are skipped.
+func runPool(ctx context.Context, n int, workers int, fn func(ctx context.Context, i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(ctx, i)
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// enrich looks up the accounts, assets and applications of a round on algod,
+// concurrently. Lookups that fail are logged and left out.
+func (in Ingester) enrich(ctx context.Context, ents roundEntities) ([]models.Account, []models.Asset, []models.Application) {
+	accounts := make([]*models.Account, len(ents.accounts))
+	runPool(ctx, len(ents.accounts), in.enrichWorkers, func(ctx context.Context, i int) {
+		acct, err := in.algodCore.GetAccount(ctx, "", ents.accounts[i])
+		if err != nil {
+			in.log.Errorw("blocksynchronizer", "status", "can't get account", "account", ents.accounts[i], "ERROR", err)
+			return
+		}
+		accounts[i] = acct
+	})
+
+	assets := make([]*models.Asset, len(ents.assets))
+	runPool(ctx, len(ents.assets), in.enrichWorkers, func(ctx context.Context, i int) {
+		asset, err := in.algodCore.GetAsset(ctx, "", ents.assets[i])
+		if err != nil {
+			in.log.Errorw("blocksynchronizer", "status", "can't get asset", "asset", ents.assets[i], "ERROR", err)
+			return
+		}
+		assets[i] = asset
+	})
+
+	apps := make([]*models.Application, len(ents.apps))
+	runPool(ctx, len(ents.apps), in.enrichWorkers, func(ctx context.Context, i int) {
+		app, err := in.algodCore.GetApplication(ctx, "", ents.apps[i])
+		if err != nil {
+			in.log.Errorw("blocksynchronizer", "status", "can't get app", "app", ents.apps[i], "ERROR", err)
+			return
+		}
+		apps[i] = app
+	})
+
+	var accountList []models.Account
+	for _, acct := range accounts {
+		if acct != nil {
+			accountList = append(accountList, *acct)
+		}
+	}
+	var assetList []models.Asset
+	for _, asset := range assets {
+		if asset != nil {
+			assetList = append(assetList, *asset)
+		}
+	}
+	var appList []models.Application
+	for _, app := range apps {
+		if app != nil {
+			appList = append(appList, *app)
+		}
+	}
+
+	return accountList, assetList, appList
+}
@@ -0,0 +1,97 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+)
+
+func TestCollectEntities(t *testing.T) {
+	t.Log("Given the need to look up each account, asset and application of a round once.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen many transactions touch the same entities.", testID)
+		{
+			var txns []models.Transaction
+			for i := 0; i < 5000; i++ {
+				txns = append(txns, models.Transaction{
+					Sender: "SENDER",
+					Type:   "axfer",
+					AssetTransferTransaction: models.TransactionAssetTransfer{
+						AssetId:  31566704,
+						Receiver: "RECEIVER",
+					},
+				})
+			}
+			txns = append(txns, models.Transaction{
+				Sender: "CALLER",
+				Type:   "appl",
+				ApplicationTransaction: models.TransactionApplication{
+					ApplicationId: 1200,
+					ForeignAssets: []uint64{31566704, 42},
+				},
+			})
+
+			ents := collectEntities(txns, nil, types.Block{})
+
+			if want := []string{"SENDER", "RECEIVER", "CALLER"}; !reflect.DeepEqual(ents.accounts, want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list each account once : got %v, want %v.", failed, testID, ents.accounts, want)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould list each account once.", success, testID)
+
+			if want := []uint64{31566704, 42}; !reflect.DeepEqual(ents.assets, want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list each asset once : got %v, want %v.", failed, testID, ents.assets, want)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould list each asset once.", success, testID)
+
+			if want := []uint64{1200}; !reflect.DeepEqual(ents.apps, want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list each application once : got %v, want %v.", failed, testID, ents.apps, want)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould list each application once.", success, testID)
+		}
+	}
+}
+
+func TestRunPool(t *testing.T) {
+	t.Log("Given the need to bound the number of concurrent lookups.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen running 100 calls on 4 workers.", testID)
+		{
+			var mu sync.Mutex
+			var running, peak int
+			done := make([]bool, 100)
+
+			runPool(context.Background(), len(done), 4, func(ctx context.Context, i int) {
+				mu.Lock()
+				running++
+				if running > peak {
+					peak = running
+				}
+				mu.Unlock()
+
+				done[i] = true
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+			})
+
+			for i, ok := range done {
+				if !ok {
+					t.Fatalf("\t\t%s\tTest %d:\tShould make every call : call %d missing.", failed, testID, i)
+				}
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould make every call.", success, testID)
+
+			if peak > 4 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould run at most 4 calls at once : got %d.", failed, testID, peak)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould run at most 4 calls at once.", success, testID)
+		}
+	}
+}
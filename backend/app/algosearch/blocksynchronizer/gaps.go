@@ -0,0 +1,66 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"time"
+
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+)
+
+// These expose the outcome of the latest gap scan through /debug/vars.
+var (
+	gapCount      = expvar.NewInt("sync_gaps")
+	missingRounds = expvar.NewInt("sync_missing_rounds")
+	gapRanges     = new(expvar.String)
+)
+
+func init() {
+	gapRanges.Set("[]")
+	expvar.Publish("sync_gap_ranges", gapRanges)
+}
+
+// scanGaps looks for rounds missing below the last synced round and publishes
+// what it found.
+func (p *BlockSynchronizer) scanGaps(ctx context.Context) {
+	gaps, err := p.blockCore.GetRoundGaps(ctx)
+	if err != nil {
+		p.log.Errorw("blocksynchronizer", "status", "can't scan for gaps", "ERROR", err)
+		return
+	}
+
+	var missing uint64
+	for _, gap := range gaps {
+		missing += gap.Size()
+	}
+
+	gapCount.Set(int64(len(gaps)))
+	missingRounds.Set(int64(missing))
+	if len(gaps) == 0 {
+		gaps = []blockdb.RoundGap{}
+	}
+	if b, err := json.Marshal(gaps); err == nil {
+		gapRanges.Set(string(b))
+	}
+
+	if len(gaps) > 0 {
+		p.log.Infow("blocksynchronizer", "status", "gaps found", "gaps", len(gaps), "missing rounds", missing)
+	}
+}
+
+// runGapScanner scans for gaps on the configured interval until shutdown.
+func (p *BlockSynchronizer) runGapScanner() {
+	ticker := time.NewTicker(p.cfg.GapScanInterval)
+	defer ticker.Stop()
+
+	p.scanGaps(p.ctx)
+	for {
+		select {
+		case <-ticker.C:
+			p.scanGaps(p.ctx)
+		case <-p.shutdown:
+			return
+		}
+	}
+}
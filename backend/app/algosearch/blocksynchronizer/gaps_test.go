@@ -0,0 +1,67 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/data/store/memory"
+)
+
+func TestScanGaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		rounds  []uint64
+		pending []uint64
+		gaps    string
+		missing int64
+	}{
+		{"nothing is stored", nil, nil, `[]`, 0},
+		{"rounds 1 to 6 are stored but 3 and 4", []uint64{1, 2, 5, 6}, nil, `[{"from":3,"to":4}]`, 2},
+		{"rounds 10 to 16 are stored but 11 and 15", []uint64{10, 12, 13, 14, 16}, nil, `[{"from":11,"to":11},{"from":15,"to":15}]`, 2},
+		{"rounds 1 to 3 are stored and 2 is not committed", []uint64{1, 3}, []uint64{2}, `[{"from":2,"to":2}]`, 1},
+	}
+
+	t.Log("Given the need to find the rounds missing between the synced ones.")
+	{
+		for testID, tt := range tests {
+			t.Logf("\tTest %d:\tWhen %s.", testID, tt.name)
+			{
+				ctx := context.Background()
+				store := memory.New()
+				p := newTestSynchronizer(store, staticSource{})
+
+				add := func(round uint64, commit bool) {
+					docID, _, err := p.blockCore.AddBlock(ctx, blockdb.NewBlock{
+						Block:     models.Block{Round: round},
+						BlockHash: fmt.Sprintf("HASH%d", round),
+					})
+					if err == nil && commit {
+						err = p.blockCore.CommitBlock(ctx, docID)
+					}
+					if err != nil {
+						t.Fatalf("\t\t%s\tTest %d:\tShould be able to add round %d : %v.", failed, testID, round, err)
+					}
+				}
+				for _, round := range tt.rounds {
+					add(round, true)
+				}
+				for _, round := range tt.pending {
+					add(round, false)
+				}
+
+				p.scanGaps(ctx)
+
+				if got := gapRanges.Value(); got != tt.gaps {
+					t.Fatalf("\t\t%s\tTest %d:\tShould publish the gaps %s : got %s.", failed, testID, tt.gaps, got)
+				}
+				if got := missingRounds.Value(); got != tt.missing {
+					t.Fatalf("\t\t%s\tTest %d:\tShould count %d missing rounds : got %d.", failed, testID, tt.missing, got)
+				}
+				t.Logf("\t\t%s\tTest %d:\tShould publish the gaps %s.", success, testID, tt.gaps)
+			}
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/core/account"
+	algod2 "github.com/kevguy/algosearch/backend/business/core/algod"
+	"github.com/kevguy/algosearch/backend/business/core/application"
+	"github.com/kevguy/algosearch/backend/business/core/asset"
+	"github.com/kevguy/algosearch/backend/business/core/block"
+	"github.com/kevguy/algosearch/backend/business/core/syncstate"
+	syncstatedb "github.com/kevguy/algosearch/backend/business/core/syncstate/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	"go.uber.org/zap"
+)
+
+// Ingester holds everything needed to pull a round from a block source and
+// persist it. It is safe to use from multiple goroutines.
+type Ingester struct {
+	log             *zap.SugaredLogger
+	retry           RetryPolicy
+	source          BlockSource
+	blockCore       *block.Core
+	transactionCore *transaction.Core
+	accountCore     *account.Core
+	assetCore       *asset.Core
+	appCore         *application.Core
+	algodCore       *algod2.Core
+	syncStateCore   *syncstate.Core
+	enrichWorkers   int
+	stateDeltas     bool
+}
+
+// Cores holds the cores an Ingester saves rounds with.
+type Cores struct {
+	Block       *block.Core
+	Transaction *transaction.Core
+	Account     *account.Core
+	Asset       *asset.Core
+	Application *application.Core
+	SyncState   *syncstate.Core
+}
+
+// Storer is a storage backend every core can run on.
+type Storer interface {
+	block.Storer
+	transaction.Storer
+	account.Storer
+	asset.Storer
+	application.Storer
+	syncstate.Storer
+}
+
+// NewCouchCores constructs the cores saving to the given CouchDB database.
+func NewCouchCores(log *zap.SugaredLogger, couchClient *kivik.Client, dbName string) Cores {
+	blockCore := block.NewCore(log, couchClient, dbName)
+	transactionCore := transaction.NewCore(log, couchClient, dbName)
+	accountCore := account.NewCore(log, couchClient, dbName)
+	assetCore := asset.NewCore(log, couchClient, dbName)
+	appCore := application.NewCore(log, couchClient, dbName)
+	syncStateCore := syncstate.NewCore(log, couchClient, dbName)
+
+	return Cores{
+		Block:       &blockCore,
+		Transaction: &transactionCore,
+		Account:     &accountCore,
+		Asset:       &assetCore,
+		Application: &appCore,
+		SyncState:   &syncStateCore,
+	}
+}
+
+// NewStoreCores constructs the cores saving to the given store.
+func NewStoreCores(store Storer) Cores {
+	blockCore := block.NewCoreWithStore(store)
+	transactionCore := transaction.NewCoreWithStore(store)
+	accountCore := account.NewCoreWithStore(store)
+	assetCore := asset.NewCoreWithStore(store)
+	appCore := application.NewCoreWithStore(store)
+	syncStateCore := syncstate.NewCoreWithStore(store)
+
+	return Cores{
+		Block:       &blockCore,
+		Transaction: &transactionCore,
+		Account:     &accountCore,
+		Asset:       &assetCore,
+		Application: &appCore,
+		SyncState:   &syncStateCore,
+	}
+}
+
+// NewIngester constructs an Ingester reading rounds from source and saving them
+// to the given CouchDB database. The accounts, assets and applications touched
+// by a round are looked up on algod, they are left out when algodClient is nil.
+func NewIngester(log *zap.SugaredLogger, retry RetryPolicy, source BlockSource, algodClient *algod.Client, couchClient *kivik.Client, dbName string) Ingester {
+	return NewIngesterWithCores(log, retry, source, algodClient, NewCouchCores(log, couchClient, dbName))
+}
+
+// NewIngesterWithCores constructs an Ingester reading rounds from source and
+// saving them with the given cores, whatever store they are backed by.
+func NewIngesterWithCores(log *zap.SugaredLogger, retry RetryPolicy, source BlockSource, algodClient *algod.Client, cores Cores) Ingester {
+	var algodCore *algod2.Core
+	if algodClient != nil {
+		core := algod2.NewCore(log, algodClient)
+		algodCore = &core
+	}
+
+	return Ingester{
+		log:             log,
+		retry:           retry,
+		source:          source,
+		blockCore:       cores.Block,
+		transactionCore: cores.Transaction,
+		accountCore:     cores.Account,
+		assetCore:       cores.Asset,
+		appCore:         cores.Application,
+		algodCore:       algodCore,
+		syncStateCore:   cores.SyncState,
+		enrichWorkers:   DefaultEnrichWorkers,
+	}
+}
+
+// pendingRound is a round whose block and transactions are saved, but whose
+// state delta still has to be applied before the round is committed.
+type pendingRound struct {
+	round      uint64
+	blockDocID string
+	delta      algod2.LedgerStateDelta
+}
+
+// IngestRound retrieves the given round from the block source and saves the block, its
+// transactions and the accounts, assets and applications they touch to CouchDB.
+// The round is committed, and so becomes visible, once all of it is saved.
+// Fetching the round is retried according to the retry policy. It returns the
+// websocket message describing the new round.
+func (in Ingester) IngestRound(ctx context.Context, round uint64) (WsMessage, error) {
+	payload, pending, err := in.ingestRound(ctx, round)
+	if err != nil {
+		return WsMessage{}, err
+	}
+
+	if pending != nil {
+		if err := in.completeRound(ctx, *pending, &payload); err != nil {
+			return WsMessage{}, err
+		}
+	}
+
+	return payload, nil
+}
+
+// ingestRound saves the block and transactions of the given round. When state
+// deltas are enabled it returns the round as pending, for the caller to
+// complete in round order. Otherwise it looks up and saves the accounts, assets
+// and applications the round touched and commits the round.
+func (in Ingester) ingestRound(ctx context.Context, round uint64) (WsMessage, *pendingRound, error) {
+	in.log.Infof("Trying to get round number: %d", round)
+
+	var sourceBlock SourceBlock
+	attempts, err := in.retry.do(ctx, func(ctx context.Context) error {
+		var err error
+		sourceBlock, err = in.source.Block(ctx, round)
+		if err != nil {
+			in.log.Errorw("blocksynchronizer", "status", "get round", "source", in.source.Name(), "round", round, "ERROR", err)
+		}
+		return err
+	})
+	if err != nil {
+		return WsMessage{}, nil, &roundError{Round: round, Attempts: attempts, Err: err}
+	}
+	in.log.Infof("Block data for round #%d retrieved from %s.", round, in.source.Name())
+
+	var newBlock = sourceBlock.NewBlock
+	var blockInfo = sourceBlock.Info
+
+	blockDocID, blockDocRev, err := in.blockCore.AddBlock(ctx, newBlock)
+	if err != nil {
+		return WsMessage{}, nil, fmt.Errorf("adding block %d: %w", round, err)
+	}
+	in.log.Infof("Added block %s with rev %s to CouchDB Block table", blockDocID, blockDocRev)
+
+	var payload = WsMessage{
+		Block: newBlock.Block,
+	}
+
+	if len(newBlock.Transactions) == 0 {
+		return payload, nil, in.commitRound(ctx, round, blockDocID)
+	}
+
+	for _, txn := range newBlock.Transactions {
+		payload.TransactionList = append(payload.TransactionList, txn.Id)
+	}
+
+	if _, err := in.transactionCore.AddTransactions(ctx, newBlock.Transactions, newBlock.TransactionExtras, blockInfo); err != nil {
+		return WsMessage{}, nil, fmt.Errorf("adding transactions for round %d: %w", round, err)
+	}
+	in.log.Infof("Added %d transactions with block %s to CouchDB Transaction table", len(newBlock.Transactions), newBlock.BlockHash)
+
+	if in.algodCore == nil {
+		return payload, nil, in.commitRound(ctx, round, blockDocID)
+	}
+
+	if in.stateDeltas {
+		delta, err := in.stateDelta(ctx, round)
+		if err == nil {
+			return payload, &pendingRound{round: round, blockDocID: blockDocID, delta: delta}, nil
+		}
+		in.log.Errorw("blocksynchronizer", "status", "can't get state delta, looking up accounts instead", "round", round, "ERROR", err)
+	}
+
+	accountList, assetList, appList := in.enrich(ctx, collectEntities(newBlock.Transactions, newBlock.TransactionExtras, blockInfo))
+	if err := in.saveEntities(ctx, accountList, assetList, appList, &payload); err != nil {
+		return WsMessage{}, nil, fmt.Errorf("saving the accounts, assets and applications of round %d: %w", round, err)
+	}
+
+	return payload, nil, in.commitRound(ctx, round, blockDocID)
+}
+
+// completeRound applies the state delta of a pending round and commits it.
+// Pending rounds have to be completed in round order.
+func (in Ingester) completeRound(ctx context.Context, pending pendingRound, payload *WsMessage) error {
+	if err := in.applyStateDelta(ctx, pending.round, pending.delta, payload); err != nil {
+		return fmt.Errorf("applying the state delta of round %d: %w", pending.round, err)
+	}
+	return in.commitRound(ctx, pending.round, pending.blockDocID)
+}
+
+// commitRound marks the round, whose block is stored under blockDocID, as
+// fully saved. The transactions of the round are committed first, so they are
+// visible by the time the block is.
+func (in Ingester) commitRound(ctx context.Context, round uint64, blockDocID string) error {
+	if err := in.transactionCore.CommitTransactions(ctx, round); err != nil {
+		return fmt.Errorf("committing the transactions of round %d: %w", round, err)
+	}
+	if err := in.blockCore.CommitBlock(ctx, blockDocID); err != nil {
+		return fmt.Errorf("committing round %d: %w", round, err)
+	}
+	return nil
+}
+
+// saveEntities saves the accounts, assets and applications of a round and
+// lists them in payload. Every kind is tried, the first failure is returned.
+func (in Ingester) saveEntities(ctx context.Context, accountList []models.Account, assetList []models.Asset, appList []models.Application, payload *WsMessage) error {
+	var firstErr error
+
+	if len(accountList) > 0 {
+		if _, err := in.accountCore.AddAccounts(ctx, accountList); err != nil {
+			in.log.Errorw("blocksynchronizer", "status", "can't add/update account(s)", "ERROR", err)
+			firstErr = err
+		}
+		for _, acct := range accountList {
+			payload.AccountList = append(payload.AccountList, acct.Address)
+		}
+	}
+
+	if len(assetList) > 0 {
+		if _, err := in.assetCore.AddAssets(ctx, assetList); err != nil {
+			in.log.Errorw("blocksynchronizer", "status", "can't add/update asset(s)", "ERROR", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		for _, asset := range assetList {
+			payload.AssetList = append(payload.AssetList, asset.Index)
+		}
+	}
+
+	if len(appList) > 0 {
+		if _, err := in.appCore.AddApplications(ctx, appList); err != nil {
+			in.log.Errorw("blocksynchronizer", "status", "can't add/update application(s)", "ERROR", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		for _, app := range appList {
+			payload.AppList = append(payload.AppList, app.Id)
+		}
+	}
+
+	return firstErr
+}
+
+// recordFailure records round as failed so it can be re-driven later. It
+// returns false if the round could not be recorded, or if ingesting it was
+// only interrupted because ctx is done.
+func (in Ingester) recordFailure(ctx context.Context, round uint64, failure error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err := in.syncStateCore.RecordFailedRound(ctx, round, attemptsOf(failure), failure); err != nil {
+		in.log.Errorw("blocksynchronizer", "status", "can't record failed round", "round", round, "ERROR", err)
+		return false
+	}
+	in.log.Errorw("blocksynchronizer", "status", "round recorded as failed", "round", round, "ERROR", failure)
+	return true
+}
+
+// RedriveFailedRound ingests a round recorded as failed again and stops tracking
+// it once that succeeds. Another failure is added to the round's record.
+//
+// Failed rounds are behind the rounds synced since, whose state deltas are
+// already applied, so the state delta of the round is not applied again: that
+// would roll back the assets and applications it changed, which unlike
+// accounts don't record the round they are as of. The accounts, assets and
+// applications of the round are looked up as they are now instead.
+func (in Ingester) RedriveFailedRound(ctx context.Context, round uint64) error {
+	in.stateDeltas = false
+	if _, err := in.IngestRound(ctx, round); err != nil {
+		in.recordFailure(ctx, round, err)
+		return fmt.Errorf("re-driving round %d: %w", round, err)
+	}
+
+	if err := in.syncStateCore.DeleteFailedRound(ctx, round); err != nil {
+		return fmt.Errorf("clearing failed round %d: %w", round, err)
+	}
+
+	return nil
+}
+
+// GetFailedRounds retrieves every round recorded as failed.
+func (in Ingester) GetFailedRounds(ctx context.Context) ([]syncstatedb.FailedRound, error) {
+	return in.syncStateCore.GetFailedRounds(ctx)
+}
@@ -0,0 +1,84 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/data/store/memory"
+	"go.uber.org/zap"
+)
+
+// staticSource serves a fixed set of rounds.
+type staticSource map[uint64]SourceBlock
+
+func (s staticSource) Name() string { return "static" }
+
+func (s staticSource) CurrentRound(ctx context.Context) (uint64, error) {
+	var tip uint64
+	for round := range s {
+		if round > tip {
+			tip = round
+		}
+	}
+	return tip, nil
+}
+
+func (s staticSource) WaitForRoundAfter(ctx context.Context, round uint64) (uint64, error) {
+	return 0, ErrWaitNotSupported
+}
+
+func (s staticSource) Block(ctx context.Context, round uint64) (SourceBlock, error) {
+	b, ok := s[round]
+	if !ok {
+		return SourceBlock{}, fmt.Errorf("round %d not found", round)
+	}
+	return b, nil
+}
+
+func TestIngestRound(t *testing.T) {
+	t.Log("Given the need to ingest rounds without CouchDB.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a round with two transactions is ingested.", testID)
+		{
+			ctx := context.Background()
+			txns := []models.Transaction{
+				{Id: "TXN1", Type: "pay", Sender: "SENDER", ConfirmedRound: 7},
+				{Id: "TXN2", Type: "pay", Sender: "SENDER", ConfirmedRound: 7, IntraRoundOffset: 1},
+			}
+			source := staticSource{
+				7: {NewBlock: blockdb.NewBlock{
+					Block:     models.Block{Round: 7, Transactions: txns},
+					BlockHash: "HASH7",
+				}},
+			}
+
+			cores := NewStoreCores(memory.New())
+			in := NewIngesterWithCores(zap.NewNop().Sugar(), DefaultRetryPolicy, source, nil, cores)
+
+			payload, err := in.IngestRound(ctx, 7)
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to ingest the round : %v.", failed, testID, err)
+			}
+			if len(payload.TransactionList) != 2 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list the transactions of the round : %v.", failed, testID, payload.TransactionList)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould be able to ingest the round.", success, testID)
+
+			stored, err := cores.Block.GetBlockByNum(ctx, 7)
+			if err != nil || stored.BlockHash != "HASH7" || stored.Pending {
+				t.Fatalf("\t\t%s\tTest %d:\tShould commit the block of the round : got %+v, %v.", failed, testID, stored.NewBlock, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould commit the block of the round.", success, testID)
+
+			acctTxns, err := cores.Transaction.GetTransactionsByAcct(ctx, "SENDER", "asc")
+			if err != nil || len(acctTxns) != 2 || acctTxns[0].ID != "TXN1" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould save the transactions of the round : got %d, %v.", failed, testID, len(acctTxns), err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould save the transactions of the round.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/types"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+)
+
+// maxFollowBroadcast caps how many rounds a follower broadcasts at once, so an
+// instance that was leading during a catch-up doesn't replay it to its clients.
+const maxFollowBroadcast = 10
+
+// leadership tracks whether this instance holds the sync lease. The context is
+// cancelled as soon as the lease is lost, stopping whatever the leader was doing.
+type leadership struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// defaultInstanceID identifies this process when no instance ID is configured.
+func defaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "algosearch"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// leaderContext returns a context that lives as long as this instance leads, and
+// false if it doesn't lead. Every instance leads when leader election is disabled.
+func (p *BlockSynchronizer) leaderContext() (context.Context, bool) {
+	if p.cfg.LeaseTTL <= 0 {
+		return p.ctx, true
+	}
+
+	p.leader.mu.Lock()
+	defer p.leader.mu.Unlock()
+
+	if p.leader.ctx == nil {
+		return nil, false
+	}
+	return p.leader.ctx, true
+}
+
+// campaign takes or renews the sync lease and steps up or down accordingly. Not
+// being able to reach CouchDB counts as losing the lease.
+func (p *BlockSynchronizer) campaign() {
+	lease, held, err := p.syncStateCore.AcquireLease(p.ctx, p.cfg.InstanceID, p.cfg.LeaseTTL)
+	if err != nil && p.ctx.Err() == nil {
+		p.log.Errorw("blocksynchronizer", "status", "can't acquire sync lease", "ERROR", err)
+	}
+
+	p.leader.mu.Lock()
+	defer p.leader.mu.Unlock()
+
+	switch {
+	case held && p.leader.ctx == nil:
+		p.leader.ctx, p.leader.cancel = context.WithCancel(p.ctx)
+		p.log.Infow("blocksynchronizer", "status", "took the sync lease", "instance", p.cfg.InstanceID, "expires", lease.ExpiresAt)
+	case !held && p.leader.ctx != nil:
+		p.leader.cancel()
+		p.leader.ctx, p.leader.cancel = nil, nil
+		p.log.Infow("blocksynchronizer", "status", "lost the sync lease", "instance", p.cfg.InstanceID, "holder", lease.Holder)
+	}
+}
+
+// runElection renews or tries to take the sync lease three times per lease
+// period until shutdown, then gives the lease up so another instance can take
+// over right away.
+func (p *BlockSynchronizer) runElection() {
+	ticker := time.NewTicker(p.cfg.LeaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.campaign()
+		case <-p.shutdown:
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := p.syncStateCore.ReleaseLease(ctx, p.cfg.InstanceID); err != nil {
+				p.log.Errorw("blocksynchronizer", "status", "can't release sync lease", "ERROR", err)
+			}
+			return
+		}
+	}
+}
+
+// follow broadcasts the rounds the leader synced since the last broadcast, so
+// the websocket clients of every instance hear about new rounds.
+func (p *BlockSynchronizer) follow() {
+	ctx := p.ctx
+
+	state, found, err := p.syncStateCore.GetSyncState(ctx)
+	if err != nil {
+		p.log.Errorw("blocksynchronizer", "status", "get sync state", "ERROR", err)
+		return
+	}
+	if !found || state.NothingSynced {
+		return
+	}
+
+	last := state.LastSyncedRound
+	if !p.broadcastSet || last < p.broadcastRound {
+		p.broadcastRound, p.broadcastSet = last, true
+		return
+	}
+
+	from := p.broadcastRound + 1
+	if last >= from && last-from >= maxFollowBroadcast {
+		from = last - maxFollowBroadcast + 1
+	}
+	for round := from; round <= last; round++ {
+		blk, err := p.blockCore.GetBlockByNum(ctx, round)
+		if err != nil {
+			p.log.Errorw("blocksynchronizer", "status", "can't get synced round", "round", round, "ERROR", err)
+			continue
+		}
+
+		payload := wsMessageFromBlock(blk.NewBlock)
+		speed, err := p.blockCore.GetBlockTxnSpeed(ctx)
+		if err != nil {
+			p.log.Errorw("blocksynchronizer", "status", "can't find block txn speed", "ERROR", err)
+		}
+		payload.AvgBlockTxnSpeed = speed
+
+		if err := p.broadcastUpdate(payload); err != nil {
+			p.log.Errorw("blocksynchronizer", "status", "can't broadcast block update through websocket", "ERROR", err)
+		}
+	}
+	p.broadcastRound = last
+}
+
+// wsMessageFromBlock builds the websocket message for a stored round. Unlike the
+// leader, a follower doesn't look anything up on algod, so the lists hold every
+// account, asset and application the transactions refer to, each listed once.
+func wsMessageFromBlock(newBlock blockdb.NewBlock) WsMessage {
+	ents := collectEntities(newBlock.Transactions, nil, types.Block{})
+
+	var payload = WsMessage{
+		Block:       newBlock.Block,
+		AccountList: ents.accounts,
+		AssetList:   ents.assets,
+		AppList:     ents.apps,
+	}
+	for _, txn := range newBlock.Transactions {
+		payload.TransactionList = append(payload.TransactionList, txn.Id)
+	}
+
+	return payload
+}
@@ -0,0 +1,59 @@
+package blocksynchronizer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+)
+
+func TestWsMessageFromBlock(t *testing.T) {
+	t.Log("Given the need for followers to broadcast the rounds the leader synced.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the transactions of a round touch the same entities.", testID)
+		{
+			pay := models.Transaction{
+				Id:     "PAY",
+				Sender: "SENDER",
+				PaymentTransaction: models.TransactionPayment{
+					Receiver: "RECEIVER",
+				},
+			}
+			call := models.Transaction{
+				Id:     "CALL",
+				Sender: "SENDER",
+				ApplicationTransaction: models.TransactionApplication{
+					ApplicationId: 1200,
+					ForeignApps:   []uint64{1200},
+					ForeignAssets: []uint64{42, 42},
+				},
+			}
+			newBlock := blockdb.NewBlock{
+				Block: models.Block{
+					Round:        10,
+					Transactions: []models.Transaction{pay, call, call},
+				},
+			}
+
+			payload := wsMessageFromBlock(newBlock)
+
+			if want := []string{"PAY", "CALL", "CALL"}; !reflect.DeepEqual(payload.TransactionList, want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list every transaction : got %v, want %v.", failed, testID, payload.TransactionList, want)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould list every transaction.", success, testID)
+
+			if want := []string{"SENDER", "RECEIVER"}; !reflect.DeepEqual(payload.AccountList, want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list each account once : got %v, want %v.", failed, testID, payload.AccountList, want)
+			}
+			if want := []uint64{42}; !reflect.DeepEqual(payload.AssetList, want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list each asset once : got %v, want %v.", failed, testID, payload.AssetList, want)
+			}
+			if want := []uint64{1200}; !reflect.DeepEqual(payload.AppList, want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list each application once : got %v, want %v.", failed, testID, payload.AppList, want)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould list each account, asset and application once.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"time"
+)
+
+// pruneCutoff returns the oldest round kept when the last synced round is last
+// and retention rounds are kept, and false if nothing is old enough to prune.
+func pruneCutoff(last uint64, retention uint64) (uint64, bool) {
+	if retention == 0 || last+1 <= retention {
+		return 0, false
+	}
+	return last + 1 - retention, true
+}
+
+// prune deletes the blocks and transactions older than the retention window,
+// counted back from the last synced round.
+func (p *BlockSynchronizer) prune(ctx context.Context) {
+	last, found, err := p.lastSyncedRound(ctx)
+	if err != nil {
+		p.log.Errorw("blocksynchronizer", "status", "get last synced round number", "ERROR", err)
+		return
+	}
+	if !found {
+		return
+	}
+
+	cutoff, ok := pruneCutoff(last, p.cfg.RetentionRounds)
+	if !ok {
+		return
+	}
+
+	// Transactions go first, so a block is never missing while its
+	// transactions are still around.
+	txns, err := p.transactionCore.DeleteTransactionsBefore(ctx, cutoff)
+	if err != nil {
+		p.log.Errorw("blocksynchronizer", "status", "can't prune transactions", "round", cutoff, "ERROR", err)
+		return
+	}
+	blocks, err := p.blockCore.DeleteBlocksBefore(ctx, cutoff)
+	if err != nil {
+		p.log.Errorw("blocksynchronizer", "status", "can't prune blocks", "round", cutoff, "ERROR", err)
+		return
+	}
+
+	if txns > 0 || blocks > 0 {
+		p.log.Infow("blocksynchronizer", "status", "pruned", "before round", cutoff, "blocks", blocks, "transactions", txns)
+	}
+}
+
+// runPruner prunes on the configured interval until shutdown. Only the
+// instance that syncs prunes.
+func (p *BlockSynchronizer) runPruner() {
+	ticker := time.NewTicker(p.cfg.PruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if ctx, ok := p.leaderContext(); ok {
+			p.prune(ctx)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-p.shutdown:
+			return
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetryPolicy describes how a failing call to the block source is retried.
+type RetryPolicy struct {
+	// Attempts is the maximum number of calls made, including the first one.
+	Attempts int
+	// BaseDelay is the wait before the first retry. It doubles on every retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between two retries.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used when no retry policy is configured.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:  5,
+	BaseDelay: 500 * time.Millisecond,
+	MaxDelay:  30 * time.Second,
+}
+
+// backoff returns how long to wait after the given failed attempt, counting from 1.
+func (r RetryPolicy) backoff(attempt int) time.Duration {
+	delay := r.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if r.MaxDelay > 0 && delay >= r.MaxDelay {
+			return r.MaxDelay
+		}
+	}
+	return delay
+}
+
+// do calls fn until it succeeds, the attempts run out or ctx is done, waiting
+// longer after every failure. It returns the number of calls made.
+func (r RetryPolicy) do(ctx context.Context, fn func(ctx context.Context) error) (int, error) {
+	attempts := r.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return attempt, nil
+		}
+		if attempt == attempts {
+			return attempt, err
+		}
+
+		t := time.NewTimer(r.backoff(attempt))
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return attempt, err
+		}
+	}
+}
+
+// roundError reports a round that could not be ingested and how many times
+// fetching it from the block source was attempted.
+type roundError struct {
+	Round    uint64
+	Attempts int
+	Err      error
+}
+
+// Error implements the error interface.
+func (re *roundError) Error() string {
+	return fmt.Sprintf("round %d failed after %d attempt(s): %v", re.Round, re.Attempts, re.Err)
+}
+
+// Unwrap returns the underlying error.
+func (re *roundError) Unwrap() error {
+	return re.Err
+}
+
+// attemptsOf returns the number of attempts recorded in err, defaulting to one.
+func attemptsOf(err error) int {
+	var re *roundError
+	if errors.As(err, &re) && re.Attempts > 0 {
+		return re.Attempts
+	}
+	return 1
+}
@@ -0,0 +1,26 @@
+package blocksynchronizer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Log("Given the need to wait longer after every failed attempt.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the delay starts at 1s and is capped at 5s.", testID)
+		{
+			r := RetryPolicy{Attempts: 5, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+			exp := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+			for i, want := range exp {
+				attempt := i + 1
+				if got := r.backoff(attempt); got != want {
+					t.Fatalf("\t\t%s\tTest %d:\tShould wait %v after attempt %d : got %v.", failed, testID, want, attempt, got)
+				}
+				t.Logf("\t\t%s\tTest %d:\tShould wait %v after attempt %d.", success, testID, want, attempt)
+			}
+		}
+	}
+}
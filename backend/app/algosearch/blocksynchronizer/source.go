@@ -0,0 +1,156 @@
+package blocksynchronizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/algod"
+	indexerv2 "github.com/algorand/go-algorand-sdk/client/v2/indexer"
+	"github.com/algorand/go-algorand-sdk/types"
+	algod2 "github.com/kevguy/algosearch/backend/business/core/algod"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/core/indexer"
+	"go.uber.org/zap"
+)
+
+// Names of the block sources.
+const (
+	AlgodSourceName   = "algod"
+	IndexerSourceName = "indexer"
+	ArchiveSourceName = "archive"
+)
+
+// ErrWaitNotSupported is returned by block sources that can't be long-polled for
+// the next round.
+var ErrWaitNotSupported = errors.New("waiting for the next round is not supported")
+
+// SourceBlock is a round read from a block source.
+type SourceBlock struct {
+	blockdb.NewBlock
+	// Info is the block as algod encodes it. It is empty for sources that don't
+	// serve the msgpack block.
+	Info types.Block
+}
+
+// BlockSource is where the rounds to ingest are read from.
+type BlockSource interface {
+	// Name identifies the source.
+	Name() string
+	// CurrentRound returns the latest round the source can serve.
+	CurrentRound(ctx context.Context) (uint64, error)
+	// WaitForRoundAfter blocks until the source has a round after the given one
+	// and returns its latest round. Sources that can't be waited on return
+	// ErrWaitNotSupported.
+	WaitForRoundAfter(ctx context.Context, round uint64) (uint64, error)
+	// Block returns the given round.
+	Block(ctx context.Context, round uint64) (SourceBlock, error)
+}
+
+// NewBlockSource constructs the block source with the given name. The indexer
+// client is only needed by the indexer source.
+func NewBlockSource(name string, log *zap.SugaredLogger, algodClient *algod.Client, indexerClient *indexerv2.Client) (BlockSource, error) {
+	switch name {
+	case "", AlgodSourceName:
+		return NewAlgodSource(log, algodClient), nil
+	case IndexerSourceName:
+		if indexerClient == nil {
+			return nil, errors.New("the indexer block source needs an indexer address")
+		}
+		return NewIndexerSource(log, indexerClient), nil
+	}
+	return nil, fmt.Errorf("unknown block source %q, expected %q or %q", name, AlgodSourceName, IndexerSourceName)
+}
+
+// =============================================================================
+
+// algodSource reads rounds from algod in msgpack format.
+type algodSource struct {
+	algodCore *algod2.Core
+}
+
+// NewAlgodSource constructs a block source reading rounds from algod.
+func NewAlgodSource(log *zap.SugaredLogger, algodClient *algod.Client) BlockSource {
+	algodCore := algod2.NewCore(log, algodClient)
+	return algodSource{algodCore: &algodCore}
+}
+
+// Name implements BlockSource.
+func (s algodSource) Name() string {
+	return AlgodSourceName
+}
+
+// CurrentRound implements BlockSource.
+func (s algodSource) CurrentRound(ctx context.Context) (uint64, error) {
+	return s.algodCore.GetCurrentRoundNum(ctx)
+}
+
+// WaitForRoundAfter implements BlockSource.
+func (s algodSource) WaitForRoundAfter(ctx context.Context, round uint64) (uint64, error) {
+	return s.algodCore.WaitForRoundAfter(ctx, round)
+}
+
+// Block implements BlockSource.
+func (s algodSource) Block(ctx context.Context, round uint64) (SourceBlock, error) {
+	rawBlock, err := s.algodCore.GetRoundInRawBytes(ctx, round)
+	if err != nil {
+		return SourceBlock{}, err
+	}
+
+	return blockFromRawBytes(ctx, round, rawBlock)
+}
+
+// blockFromRawBytes converts a block in the msgpack format algod serves it in.
+func blockFromRawBytes(ctx context.Context, round uint64, rawBlock []byte) (SourceBlock, error) {
+	newBlock, err := algod2.ConvertBlockRawBytes(ctx, rawBlock)
+	if err != nil {
+		return SourceBlock{}, fmt.Errorf("converting raw bytes to block data for round %d: %w", round, err)
+	}
+
+	response, err := algod2.DecodeBlockResponse(rawBlock)
+	if err != nil {
+		return SourceBlock{}, fmt.Errorf("parsing block %d from msgpack format: %w", round, err)
+	}
+
+	return SourceBlock{NewBlock: newBlock, Info: response.Block}, nil
+}
+
+// =============================================================================
+
+// indexerSource reads rounds from the Indexer.
+type indexerSource struct {
+	indexerCore *indexer.Core
+}
+
+// NewIndexerSource constructs a block source reading rounds from the Indexer.
+// The Indexer can't be waited on for the next round and its blocks carry neither
+// the proposer nor the block hash.
+func NewIndexerSource(log *zap.SugaredLogger, indexerClient *indexerv2.Client) BlockSource {
+	indexerCore := indexer.NewCore(log, indexerClient)
+	return indexerSource{indexerCore: &indexerCore}
+}
+
+// Name implements BlockSource.
+func (s indexerSource) Name() string {
+	return IndexerSourceName
+}
+
+// CurrentRound implements BlockSource.
+func (s indexerSource) CurrentRound(ctx context.Context) (uint64, error) {
+	return s.indexerCore.GetCurrentRoundNum(ctx)
+}
+
+// WaitForRoundAfter implements BlockSource.
+func (s indexerSource) WaitForRoundAfter(ctx context.Context, round uint64) (uint64, error) {
+	return 0, ErrWaitNotSupported
+}
+
+// Block implements BlockSource.
+func (s indexerSource) Block(ctx context.Context, round uint64) (SourceBlock, error) {
+	jsonBlock, err := s.indexerCore.LookupRoundInJSON(ctx, round)
+	if err != nil {
+		return SourceBlock{}, err
+	}
+
+	return SourceBlock{NewBlock: indexer.ConvertBlockJSON(jsonBlock)}, nil
+}
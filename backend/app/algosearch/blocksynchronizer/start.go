@@ -0,0 +1,67 @@
+package blocksynchronizer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StartRound is the round syncing starts at when nothing has been synced yet.
+// It is either a fixed round or a number of rounds below the tip.
+type StartRound struct {
+	Round   uint64
+	FromTip bool
+}
+
+// ParseStartRound parses a start round, given either as a round number or as
+// "tip" or "tip-N" for N rounds below the tip.
+func ParseStartRound(s string) (StartRound, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return StartRound{}, nil
+	}
+
+	if strings.HasPrefix(s, "tip") {
+		rest := strings.TrimPrefix(s, "tip")
+		if rest == "" {
+			return StartRound{FromTip: true}, nil
+		}
+		if !strings.HasPrefix(rest, "-") {
+			return StartRound{}, fmt.Errorf("invalid start round %q, expected a round, \"tip\" or \"tip-N\"", s)
+		}
+		n, err := strconv.ParseUint(strings.TrimPrefix(rest, "-"), 10, 64)
+		if err != nil {
+			return StartRound{}, fmt.Errorf("invalid start round %q: %w", s, err)
+		}
+		return StartRound{Round: n, FromTip: true}, nil
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return StartRound{}, fmt.Errorf("invalid start round %q: %w", s, err)
+	}
+	return StartRound{Round: n}, nil
+}
+
+// resolve returns the round to start at given the current tip.
+func (s StartRound) resolve(tip uint64) uint64 {
+	if !s.FromTip {
+		return s.Round
+	}
+	if s.Round > tip {
+		return 0
+	}
+	return tip - s.Round
+}
+
+// String implements the fmt.Stringer interface.
+func (s StartRound) String() string {
+	switch {
+	case !s.FromTip:
+		return strconv.FormatUint(s.Round, 10)
+	case s.Round == 0:
+		return "tip"
+	default:
+		return "tip-" + strconv.FormatUint(s.Round, 10)
+	}
+}
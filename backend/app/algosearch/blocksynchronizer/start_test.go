@@ -0,0 +1,74 @@
+package blocksynchronizer
+
+import (
+	"testing"
+)
+
+func TestStartRound(t *testing.T) {
+	t.Log("Given the need to choose the round syncing starts at.")
+	{
+		tests := []struct {
+			in    string
+			tip   uint64
+			round uint64
+		}{
+			{"", 1000, 0},
+			{"0", 1000, 0},
+			{"250", 1000, 250},
+			{"tip", 1000, 1000},
+			{"tip-100", 1000, 900},
+			{"tip-5000", 1000, 0},
+		}
+		for testID, tt := range tests {
+			t.Logf("\tTest %d:\tWhen starting at %q with the tip at %d.", testID, tt.in, tt.tip)
+			{
+				start, err := ParseStartRound(tt.in)
+				if err != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to parse the start round : %v.", failed, testID, err)
+				}
+				if got := start.resolve(tt.tip); got != tt.round {
+					t.Fatalf("\t\t%s\tTest %d:\tShould start at round %d : got %d.", failed, testID, tt.round, got)
+				}
+				t.Logf("\t\t%s\tTest %d:\tShould start at round %d.", success, testID, tt.round)
+			}
+		}
+
+		testID := len(tests)
+		t.Logf("\tTest %d:\tWhen the start round is malformed.", testID)
+		{
+			for _, in := range []string{"tip+5", "tipsy", "-3", "latest"} {
+				if _, err := ParseStartRound(in); err == nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould reject %q.", failed, testID, in)
+				}
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould reject it.", success, testID)
+		}
+	}
+}
+
+func TestPruneCutoff(t *testing.T) {
+	t.Log("Given the need to keep only the most recent rounds.")
+	{
+		tests := []struct {
+			last      uint64
+			retention uint64
+			cutoff    uint64
+			ok        bool
+		}{
+			{1000, 0, 0, false},
+			{1000, 1001, 0, false},
+			{1000, 1000, 1, true},
+			{1000, 100, 901, true},
+		}
+		for testID, tt := range tests {
+			t.Logf("\tTest %d:\tWhen keeping %d rounds up to round %d.", testID, tt.retention, tt.last)
+			{
+				cutoff, ok := pruneCutoff(tt.last, tt.retention)
+				if cutoff != tt.cutoff || ok != tt.ok {
+					t.Fatalf("\t\t%s\tTest %d:\tShould prune before round %d (%v) : got %d (%v).", failed, testID, tt.cutoff, tt.ok, cutoff, ok)
+				}
+				t.Logf("\t\t%s\tTest %d:\tShould prune before round %d (%v).", success, testID, tt.cutoff, tt.ok)
+			}
+		}
+	}
+}
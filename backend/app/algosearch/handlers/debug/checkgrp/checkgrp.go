@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"github.com/algorand/go-algorand-sdk/client/v2/algod"
 	"github.com/go-kivik/kivik/v4"
+	"github.com/jmoiron/sqlx"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
 	algodCore "github.com/kevguy/algosearch/backend/foundation/algod"
 	"github.com/kevguy/algosearch/backend/foundation/couchdb"
 	"net/http"
@@ -20,6 +22,7 @@ type Handlers struct {
 	Build string
 	Log   *zap.SugaredLogger
 	CouchClient  *kivik.Client
+	DB          *sqlx.DB
 	AlgodClient *algod.Client
 }
 
@@ -34,9 +37,19 @@ func (h Handlers) Readiness(w http.ResponseWriter, r *http.Request) {
 	statusCode := http.StatusOK
 
 	// Check CouchDB connection
-	if err := couchdb.StatusCheck(ctx, h.CouchClient); err != nil {
-		status = "db not ready"
-		statusCode = http.StatusInternalServerError
+	if h.CouchClient != nil {
+		if err := couchdb.StatusCheck(ctx, h.CouchClient); err != nil {
+			status = "db not ready"
+			statusCode = http.StatusInternalServerError
+		}
+	}
+
+	// Check PostgreSQL connection
+	if h.DB != nil {
+		if err := database.StatusCheck(ctx, h.DB); err != nil {
+			status = "db not ready"
+			statusCode = http.StatusInternalServerError
+		}
 	}
 
 	// Check Algod connection
@@ -6,18 +6,16 @@ import (
 	"context"
 	"expvar"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/v1/acctgrp"
+	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/v1/appgrp"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/v1/assetgrp"
+	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/v1/groupgrp"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/v1/ledgergrp"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/v1/roundgrp"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/v1/srchgrp"
+	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/v1/syncgrp"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/v1/transactiongrp"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/v1/wsgrp"
-	"github.com/kevguy/algosearch/backend/business/core/account"
 	algod2 "github.com/kevguy/algosearch/backend/business/core/algod"
-	"github.com/kevguy/algosearch/backend/business/core/application"
-	"github.com/kevguy/algosearch/backend/business/core/asset"
-	block2 "github.com/kevguy/algosearch/backend/business/core/block"
-	transaction2 "github.com/kevguy/algosearch/backend/business/core/transaction"
 	"github.com/kevguy/algosearch/backend/foundation/websocket"
 	"net/http"
 	"net/http/pprof"
@@ -26,6 +24,8 @@ import (
 	"github.com/algorand/go-algorand-sdk/client/v2/algod"
 	"github.com/algorand/go-algorand-sdk/client/v2/indexer"
 	"github.com/go-kivik/kivik/v4"
+	"github.com/jmoiron/sqlx"
+	"github.com/kevguy/algosearch/backend/app/algosearch/blocksynchronizer"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/apidoc/swaggergrp"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/debug/checkgrp"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers/debug/samplegrp"
@@ -58,9 +58,10 @@ type APIMuxConfig struct {
 	Auth			*auth.Auth
 	AlgodClient		*algod.Client
 	IndexerClient	*indexer.Client
-	CouchClient		*kivik.Client
+	BlockSource		blocksynchronizer.BlockSource
+	Synchronizer	*blocksynchronizer.BlockSynchronizer
+	Cores			blocksynchronizer.Cores
 	Hub    			*websocket.Hub
-	DBName 			string
 }
 
 // APIMux constructs an http.Handler with all application routes defined.
@@ -128,7 +129,9 @@ func DebugStandardLibraryMux() *http.ServeMux {
 // debug application routes for the service. This bypassing the use of the
 // DefaultServerMux. Using the DefaultServerMux would be a security risk since
 // a dependency could inject a handler into our service without us knowing it.
-func DebugMux(build string, log *zap.SugaredLogger, couchClient *kivik.Client, algodClient *algod.Client) http.Handler {
+// Only the database of the storage backend in use, couchClient or db, is
+// checked for readiness, the other one is nil.
+func DebugMux(build string, log *zap.SugaredLogger, couchClient *kivik.Client, db *sqlx.DB, algodClient *algod.Client) http.Handler {
 	mux := DebugStandardLibraryMux()
 
 	// Register debug check endpoints.
@@ -136,6 +139,7 @@ func DebugMux(build string, log *zap.SugaredLogger, couchClient *kivik.Client, a
 		Build: build,
 		Log:   log,
 		CouchClient: couchClient,
+		DB:          db,
 		AlgodClient: algodClient,
 	}
 	mux.HandleFunc("/debug/readiness", cgh.Readiness)
@@ -163,22 +167,25 @@ func v1(app *web.App, cfg APIMuxConfig) {
 	app.Handle(http.MethodGet, "", "/api/doc", sg.ServeDoc, mid.Cors("*"))
 
 	algodCore := algod2.NewCore(cfg.Log, cfg.AlgodClient)
-	blockCore := block2.NewCore(cfg.Log, cfg.CouchClient, cfg.DBName)
-	txnCore := transaction2.NewCore(cfg.Log, cfg.CouchClient, cfg.DBName)
-	acctCore := account.NewCore(cfg.Log, cfg.CouchClient, cfg.DBName)
-	assetCore := asset.NewCore(cfg.Log, cfg.CouchClient, cfg.DBName)
-	appCore := application.NewCore(cfg.Log, cfg.CouchClient, cfg.DBName)
+	blockCore := *cfg.Cores.Block
+	txnCore := *cfg.Cores.Transaction
+	acctCore := *cfg.Cores.Account
+	assetCore := *cfg.Cores.Asset
+	appCore := *cfg.Cores.Application
 
 	// Register round endpoints
 	rG := roundgrp.Handlers{
-		BlockCore: blockCore,
-		AlgodCore: algodCore,
+		BlockCore:       blockCore,
+		TransactionCore: txnCore,
+		AlgodCore:       algodCore,
 	}
 	app.Handle(http.MethodGet, version, "/algod/current-round", rG.GetCurrentRoundFromAPI, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/algod/rounds/:num", rG.GetRoundFromAPI, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/current-round", rG.GetLatestSyncedRound, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/earliest-round-num", rG.GetEarliestSyncedRound, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/rounds/:num/transactions", rG.GetRoundTransactions, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/rounds/:num", rG.GetRound, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/blocks/hash/:hash", rG.GetBlockByHash, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/rounds", rG.GetRoundsPagination, mid.Cors("*"))
 
 	// Register transaction endpoints
@@ -188,8 +195,10 @@ func v1(app *web.App, cfg APIMuxConfig) {
 	app.Handle(http.MethodGet, version, "/current-txn", tG.GetLatestSyncedTransaction, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/earliest-txn", tG.GetEarliestSyncedTransaction, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/transactions/:id", tG.GetTransaction, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/transactions/:id/inner/:path", tG.GetInnerTransaction, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/transactions/acct/:acct_id", tG.GetTransactionsByAcctID, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/transactions/acct/:acct_id/count", tG.GetTransactionsByAcctIDCount, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/transactions/type/:type", tG.GetTransactionsByType, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/transactions", tG.GetTransactionsPagination, mid.Cors("*"))
 
 	// Register account endpoints
@@ -202,8 +211,35 @@ func v1(app *web.App, cfg APIMuxConfig) {
 	app.Handle(http.MethodGet, version, "/accounts/:addr", aG.GetAccount, mid.Cors("*"))
 	app.Handle(http.MethodGet, version, "/accounts", aG.GetAccountsPagination, mid.Cors("*"))
 
-	asG := assetgrp.Handlers{AlgodCore: algodCore}
+	// Register asset endpoints
+	asG := assetgrp.Handlers{
+		AlgodCore:       algodCore,
+		AssetCore:       assetCore,
+		TransactionCore: txnCore,
+	}
 	app.Handle(http.MethodGet, version, "/algod/assets/:idx", asG.GetAssetByIDFromAPI, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/assets/count", asG.GetAssetCount, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/assets/:id/transactions/count", asG.GetTransactionsByAssetCount, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/assets/:id/transactions", asG.GetTransactionsByAsset, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/assets/:id", asG.GetAsset, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/assets", asG.GetAssetsPagination, mid.Cors("*"))
+
+	// Register application endpoints
+	apG := appgrp.Handlers{
+		ApplicationCore: appCore,
+		TransactionCore: txnCore,
+	}
+	app.Handle(http.MethodGet, version, "/applications/count", apG.GetApplicationCount, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/applications/:id/transactions/count", apG.GetTransactionsByAppCount, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/applications/:id/transactions", apG.GetTransactionsByApp, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/applications/:id", apG.GetApplication, mid.Cors("*"))
+	app.Handle(http.MethodGet, version, "/applications", apG.GetApplicationsPagination, mid.Cors("*"))
+
+	// Register atomic group endpoints
+	gG := groupgrp.Handlers{
+		TransactionCore: txnCore,
+	}
+	app.Handle(http.MethodGet, version, "/groups/:group_id", gG.GetGroup, mid.Cors("*"))
 
 	lG := ledgergrp.Handlers{
 		AlgodCore: algodCore,
@@ -219,6 +255,23 @@ func v1(app *web.App, cfg APIMuxConfig) {
 	}
 	app.Handle(http.MethodGet, version, "/search", sG.SrchKey, mid.Cors("*"))
 
+	// Register sync endpoints
+	authen := mid.Authenticate(cfg.Auth)
+	admin := mid.Authorize(auth.RoleAdmin)
+	syG := syncgrp.Handlers{
+		Ingester:     blocksynchronizer.NewIngesterWithCores(cfg.Log, blocksynchronizer.DefaultRetryPolicy, cfg.BlockSource, cfg.AlgodClient, cfg.Cores),
+		BlockCore:    blockCore,
+		Synchronizer: cfg.Synchronizer,
+	}
+	app.Handle(http.MethodGet, version, "/sync/gaps", syG.GetGaps, mid.Cors("*"), authen, admin)
+	app.Handle(http.MethodGet, version, "/sync/failed-rounds", syG.GetFailedRounds, mid.Cors("*"), authen, admin)
+	app.Handle(http.MethodPost, version, "/sync/failed-rounds/:num/redrive", syG.RedriveFailedRound, mid.Cors("*"), authen, admin)
+	app.Handle(http.MethodGet, version, "/sync/status", syG.GetStatus, mid.Cors("*"), authen, admin)
+	app.Handle(http.MethodPost, version, "/sync/pause", syG.Pause, mid.Cors("*"), authen, admin)
+	app.Handle(http.MethodPost, version, "/sync/resume", syG.Resume, mid.Cors("*"), authen, admin)
+	app.Handle(http.MethodPut, version, "/sync/stop-at", syG.SetStopAt, mid.Cors("*"), authen, admin)
+	app.Handle(http.MethodPost, version, "/sync/restart-from/:num", syG.RestartFrom, mid.Cors("*"), authen, admin)
+
 	// Register websocket endpoints
 	wsG := wsgrp.Handlers{
 		Hub: cfg.Hub,
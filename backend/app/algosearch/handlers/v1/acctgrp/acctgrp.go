@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/kevguy/algosearch/backend/business/core/account"
 	"github.com/kevguy/algosearch/backend/business/core/account/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
 	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
@@ -63,6 +64,10 @@ func (h Handlers) GetEarliestSyncedAccountAddr(ctx context.Context, w http.Respo
 	return web.Respond(ctx, w, accountData, http.StatusOK)
 }
 
+// GetAccountsPagination pages through the accounts with the page and
+// latest_acct query parameters, or, without page, reads them from the optional
+// cursor parameter, a next or prev cursor of an earlier response. Both answer
+// with the cursors to the pages before and after.
 func (h Handlers) GetAccountsPagination(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 
 	// limit
@@ -71,21 +76,27 @@ func (h Handlers) GetAccountsPagination(ctx context.Context, w http.ResponseWrit
 		return v1web.NewRequestError(fmt.Errorf("missing query parameter: limit"), http.StatusBadRequest)
 	}
 	limit, err := strconv.Atoi(limitQueries[0])
-	if err != nil {
+	if err != nil || limit < 1 {
 		return v1web.NewRequestError(fmt.Errorf("invalid 'limit' format: %s", limitQueries[0]), http.StatusBadRequest)
 	}
 
-	// latest_acct
-	latestAcctQueries := web.Query(r, "latest_acct")
-	if len(latestAcctQueries) == 0 {
-		return v1web.NewRequestError(fmt.Errorf("missing query parameter: latest_acct"), http.StatusBadRequest)
+	// order
+	var order string
+	orderQueries := web.Query(r, "order")
+	if len(orderQueries) == 0 {
+		//return validate.NewRequestError(fmt.Errorf("missing query parameter: sort"), http.StatusBadRequest)
+		order = "desc"
+	} else {
+		order = orderQueries[0]
+	}
+	if order != "asc" && order != "desc" {
+		return v1web.NewRequestError(fmt.Errorf("invalid 'sort' format: %s", orderQueries[0]), http.StatusBadRequest)
 	}
-	latestAcctID := latestAcctQueries[0]
 
 	// page
 	pageQueries := web.Query(r, "page")
 	if len(pageQueries) == 0 {
-		return v1web.NewRequestError(fmt.Errorf("missing query parameter: page"), http.StatusBadRequest)
+		return h.getAccountsByCursor(ctx, w, r, order, int64(limit))
 	}
 	page, err := strconv.Atoi(pageQueries[0])
 	if err != nil {
@@ -93,34 +104,63 @@ func (h Handlers) GetAccountsPagination(ctx context.Context, w http.ResponseWrit
 		return v1web.NewRequestError(fmt.Errorf("invalid 'page' format: %s", pageQueries[0]), http.StatusBadRequest)
 	}
 
-	// order
-	var order string
-	orderQueries := web.Query(r, "order")
-	if len(orderQueries) == 0 {
-		//return validate.NewRequestError(fmt.Errorf("missing query parameter: sort"), http.StatusBadRequest)
-		order = "desc"
-	} else {
-		order = orderQueries[0]
-	}
-	if order != "asc" && order != "desc" {
-		return v1web.NewRequestError(fmt.Errorf("invalid 'sort' format: %s", orderQueries[0]), http.StatusBadRequest)
+	// latest_acct
+	latestAcctQueries := web.Query(r, "latest_acct")
+	if len(latestAcctQueries) == 0 {
+		return v1web.NewRequestError(fmt.Errorf("missing query parameter: latest_acct"), http.StatusBadRequest)
 	}
+	latestAcctID := latestAcctQueries[0]
 
 	result, numOfPages, numOfAccts, err := h.AcctCore.GetAccountsPagination(ctx, latestAcctID, order, int64(page), int64(limit))
 	if err != nil {
 		return fmt.Errorf("error fetching pagination results: %w", err)
 	}
 
+	var links cursor.Links
+	if len(result) > 0 {
+		links = cursor.Around(result[0].Position(), result[len(result)-1].Position(), page > 1, int64(page) < numOfPages)
+	}
+
 	type Payload struct {
 		NumOfPages	int64 			`json:"num_of_pages"`
 		NumOfAccts	int64 			`json:"num_of_accts"`
 		Items 		[]db.Account 	`json:"items"`
+		cursor.Links
 	}
 
 	return web.Respond(ctx, w, Payload{
 		NumOfPages: numOfPages,
 		NumOfAccts:  numOfAccts,
 		Items:      result,
+		Links:      links,
+	}, http.StatusOK)
+}
+
+// getAccountsByCursor responds with up to limit accounts read from the cursor
+// query parameter, or from the start of the list without it, along with the
+// cursors to the pages before and after.
+func (h Handlers) getAccountsByCursor(ctx context.Context, w http.ResponseWriter, r *http.Request, order string, limit int64) error {
+	var cur *cursor.Cursor
+	if cursorQueries := web.Query(r, "cursor"); len(cursorQueries) > 0 {
+		var err error
+		if cur, err = cursor.Decode(cursorQueries[0]); err != nil {
+			return v1web.NewRequestError(err, http.StatusBadRequest)
+		}
+	}
+
+	result, links, err := h.AcctCore.GetAccountsByCursor(ctx, cur, order, limit)
+	if err != nil {
+		return fmt.Errorf("error fetching cursor results: %w", err)
+	}
+
+	type Payload struct {
+		Items []db.Account `json:"items"`
+		cursor.Links
+	}
+
+	return web.Respond(ctx, w, Payload{
+		Items: result,
+		Links: links,
 	}, http.StatusOK)
 }
 
@@ -0,0 +1,151 @@
+// Package appgrp maintains the group of handlers for application access.
+package appgrp
+
+import (
+	"context"
+	"fmt"
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/core/application"
+	"github.com/kevguy/algosearch/backend/business/core/application/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	txndb "github.com/kevguy/algosearch/backend/business/core/transaction/db"
+	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"net/http"
+	"strconv"
+)
+
+type Handlers struct {
+	ApplicationCore application.Core
+	TransactionCore transaction.Core
+}
+
+// GetApplication retrieves an application from CouchDB based on the
+// application ID (id), along with its global state decoded.
+func (h Handlers) GetApplication(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	id := web.Param(r, "id")
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid id format: %s", id), http.StatusBadRequest)
+	}
+
+	appData, err := h.ApplicationCore.GetApplication(ctx, id)
+	if err != nil {
+		if kivik.StatusCode(err) == http.StatusNotFound {
+			return v1web.NewRequestError(fmt.Errorf("application %s not found", id), http.StatusNotFound)
+		}
+		return fmt.Errorf("unable to get application %s: %w", id, err)
+	}
+
+	type Payload struct {
+		models.Application
+		DecodedGlobalState []application.StateValue `json:"decoded_global_state"`
+	}
+
+	return web.Respond(ctx, w, Payload{
+		Application:        appData,
+		DecodedGlobalState: application.DecodeState(appData.Params.GlobalState),
+	}, http.StatusOK)
+}
+
+// GetApplicationsPagination pages through the applications with the page,
+// limit, order and latest_app query parameters.
+func (h Handlers) GetApplicationsPagination(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	limit, order, page, err := v1web.PageQueries(r)
+	if err != nil {
+		return err
+	}
+
+	// latest_app
+	latestAppQueries := web.Query(r, "latest_app")
+	if len(latestAppQueries) == 0 {
+		return v1web.NewRequestError(fmt.Errorf("missing query parameter: latest_app"), http.StatusBadRequest)
+	}
+	latestAppID := latestAppQueries[0]
+	if _, err := strconv.ParseUint(latestAppID, 10, 64); err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid 'latest_app' format: %s", latestAppID), http.StatusBadRequest)
+	}
+
+	result, numOfPages, numOfApps, err := h.ApplicationCore.GetApplicationsPagination(ctx, latestAppID, order, page, limit)
+	if err != nil {
+		return fmt.Errorf("error fetching pagination results: %w", err)
+	}
+
+	type Payload struct {
+		NumOfPages int64            `json:"num_of_pages"`
+		NumOfApps  int64            `json:"num_of_apps"`
+		Items      []db.Application `json:"items"`
+	}
+
+	return web.Respond(ctx, w, Payload{
+		NumOfPages: numOfPages,
+		NumOfApps:  numOfApps,
+		Items:      result,
+	}, http.StatusOK)
+}
+
+// GetApplicationCount retrieves the number of applications synced.
+func (h Handlers) GetApplicationCount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	count, err := h.ApplicationCore.GetApplicationCount(ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching application count: %w", err)
+	}
+
+	return web.Respond(ctx, w, count, http.StatusOK)
+}
+
+// GetTransactionsByApp pages through the transactions of an application with
+// the page, limit and order query parameters.
+func (h Handlers) GetTransactionsByApp(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	id := web.Param(r, "id")
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid id format: %s", id), http.StatusBadRequest)
+	}
+
+	limit, order, page, err := v1web.PageQueries(r)
+	if err != nil {
+		return err
+	}
+
+	result, numOfPages, numOfTxns, err := h.TransactionCore.GetTransactionsByAppPagination(ctx, id, order, page, limit)
+	if err != nil {
+		return fmt.Errorf("error fetching pagination results: %w", err)
+	}
+
+	type Payload struct {
+		NumOfPages int64               `json:"num_of_pages"`
+		NumOfTxns  int64               `json:"num_of_txns"`
+		Items      []txndb.Transaction `json:"items"`
+	}
+
+	return web.Respond(ctx, w, Payload{
+		NumOfPages: numOfPages,
+		NumOfTxns:  numOfTxns,
+		Items:      result,
+	}, http.StatusOK)
+}
+
+// GetTransactionsByAppCount retrieves the number of transactions of an
+// application.
+func (h Handlers) GetTransactionsByAppCount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	id := web.Param(r, "id")
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid id format: %s", id), http.StatusBadRequest)
+	}
+
+	count, err := h.TransactionCore.GetTransactionCountByApp(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error fetching transaction count by application ID[%q]: %w", id, err)
+	}
+
+	return web.Respond(ctx, w, count, http.StatusOK)
+}
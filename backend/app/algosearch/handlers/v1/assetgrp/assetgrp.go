@@ -3,7 +3,12 @@ package assetgrp
 import (
 	"context"
 	"fmt"
+	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/core/algod"
+	"github.com/kevguy/algosearch/backend/business/core/asset"
+	"github.com/kevguy/algosearch/backend/business/core/asset/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	txndb "github.com/kevguy/algosearch/backend/business/core/transaction/db"
 	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 
@@ -13,7 +18,9 @@ import (
 )
 
 type Handlers struct {
-	AlgodCore algod.Core
+	AlgodCore       algod.Core
+	AssetCore       asset.Core
+	TransactionCore transaction.Core
 }
 
 // ./sandbox goal asset create --assetmetadatab64 b3Jp --creator LSDNNEAHUH6WB5YUGU6UYP3WPCZBNYE2NSJWGCXDQMFB33Q6GNLOAR5X6E --total 100 --decimals 3
@@ -37,3 +44,122 @@ func (h Handlers) GetAssetByIDFromAPI(ctx context.Context, w http.ResponseWriter
 	return web.Respond(ctx, w, asset, http.StatusOK)
 
 }
+
+// GetAsset retrieves an asset from CouchDB based on the asset ID (id)
+func (h Handlers) GetAsset(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	id := web.Param(r, "id")
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid id format: %s", id), http.StatusBadRequest)
+	}
+
+	assetData, err := h.AssetCore.GetAsset(ctx, id)
+	if err != nil {
+		if kivik.StatusCode(err) == http.StatusNotFound {
+			return v1web.NewRequestError(fmt.Errorf("asset %s not found", id), http.StatusNotFound)
+		}
+		return fmt.Errorf("unable to get asset %s: %w", id, err)
+	}
+
+	return web.Respond(ctx, w, assetData, http.StatusOK)
+}
+
+// GetAssetsPagination pages through the assets with the page, limit, order
+// and latest_asset query parameters.
+func (h Handlers) GetAssetsPagination(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	limit, order, page, err := v1web.PageQueries(r)
+	if err != nil {
+		return err
+	}
+
+	// latest_asset
+	latestAssetQueries := web.Query(r, "latest_asset")
+	if len(latestAssetQueries) == 0 {
+		return v1web.NewRequestError(fmt.Errorf("missing query parameter: latest_asset"), http.StatusBadRequest)
+	}
+	latestAssetID := latestAssetQueries[0]
+	if _, err := strconv.ParseUint(latestAssetID, 10, 64); err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid 'latest_asset' format: %s", latestAssetID), http.StatusBadRequest)
+	}
+
+	result, numOfPages, numOfAssets, err := h.AssetCore.GetAssetsPagination(ctx, latestAssetID, order, page, limit)
+	if err != nil {
+		return fmt.Errorf("error fetching pagination results: %w", err)
+	}
+
+	type Payload struct {
+		NumOfPages  int64      `json:"num_of_pages"`
+		NumOfAssets int64      `json:"num_of_assets"`
+		Items       []db.Asset `json:"items"`
+	}
+
+	return web.Respond(ctx, w, Payload{
+		NumOfPages:  numOfPages,
+		NumOfAssets: numOfAssets,
+		Items:       result,
+	}, http.StatusOK)
+}
+
+// GetAssetCount retrieves the number of assets synced.
+func (h Handlers) GetAssetCount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	count, err := h.AssetCore.GetAssetCount(ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching asset count: %w", err)
+	}
+
+	return web.Respond(ctx, w, count, http.StatusOK)
+}
+
+// GetTransactionsByAsset pages through the transactions of an asset with the
+// page, limit and order query parameters.
+func (h Handlers) GetTransactionsByAsset(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	id := web.Param(r, "id")
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid id format: %s", id), http.StatusBadRequest)
+	}
+
+	limit, order, page, err := v1web.PageQueries(r)
+	if err != nil {
+		return err
+	}
+
+	result, numOfPages, numOfTxns, err := h.TransactionCore.GetTransactionsByAssetPagination(ctx, id, order, page, limit)
+	if err != nil {
+		return fmt.Errorf("error fetching pagination results: %w", err)
+	}
+
+	type Payload struct {
+		NumOfPages int64               `json:"num_of_pages"`
+		NumOfTxns  int64               `json:"num_of_txns"`
+		Items      []txndb.Transaction `json:"items"`
+	}
+
+	return web.Respond(ctx, w, Payload{
+		NumOfPages: numOfPages,
+		NumOfTxns:  numOfTxns,
+		Items:      result,
+	}, http.StatusOK)
+}
+
+// GetTransactionsByAssetCount retrieves the number of transactions of an asset.
+func (h Handlers) GetTransactionsByAssetCount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	id := web.Param(r, "id")
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid id format: %s", id), http.StatusBadRequest)
+	}
+
+	count, err := h.TransactionCore.GetTransactionCountByAsset(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error fetching transaction count by asset ID[%q]: %w", id, err)
+	}
+
+	return web.Respond(ctx, w, count, http.StatusOK)
+}
@@ -0,0 +1,39 @@
+// Package groupgrp maintains the group of handlers for atomic transaction
+// group access.
+package groupgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+)
+
+type Handlers struct {
+	TransactionCore transaction.Core
+}
+
+// GetGroup retrieves an atomic group based on its ID (group_id), in standard
+// or URL-safe base64: its members in order, their total fees and the accounts
+// taking part.
+func (h Handlers) GetGroup(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	groupID := web.Param(r, "group_id")
+
+	group, err := h.TransactionCore.GetGroup(ctx, groupID)
+	if err != nil {
+		switch {
+		case errors.Is(err, transaction.ErrInvalidGroupID):
+			return v1web.NewRequestError(fmt.Errorf("invalid group_id format: %s", groupID), http.StatusBadRequest)
+		case errors.Is(err, transaction.ErrGroupNotFound):
+			return v1web.NewRequestError(fmt.Errorf("group %s not found", groupID), http.StatusNotFound)
+		}
+		return fmt.Errorf("unable to get group %s: %w", groupID, err)
+	}
+
+	return web.Respond(ctx, w, group, http.StatusOK)
+}
@@ -0,0 +1,117 @@
+package roundgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/core/block/db"
+	txndb "github.com/kevguy/algosearch/backend/business/core/transaction/db"
+	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+)
+
+// GetBlockByHash retrieves a block from CouchDB based on its hash (hash).
+func (h Handlers) GetBlockByHash(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	hash := web.Param(r, "hash")
+
+	blockData, err := h.BlockCore.GetBlockByHash(ctx, hash)
+	if err != nil {
+		if kivik.StatusCode(err) == http.StatusNotFound || errors.Is(err, db.ErrNotCommitted) {
+			return v1web.NewRequestError(fmt.Errorf("block %s not found", hash), http.StatusNotFound)
+		}
+		return fmt.Errorf("unable to get block %s: %w", hash, err)
+	}
+
+	return web.Respond(ctx, w, blockData, http.StatusOK)
+}
+
+// GetRoundTransactions pages through the transaction documents of a round
+// (num) with the limit and page query parameters, in the order they were
+// confirmed in, or the other way round with order set to desc.
+func (h Handlers) GetRoundTransactions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+	numStr := web.Param(r, "num")
+	num, err := strconv.ParseUint(numStr, 10, 64)
+	if err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid num format: %s", numStr), http.StatusBadRequest)
+	}
+
+	// limit
+	limitQueries := web.Query(r, "limit")
+	if len(limitQueries) == 0 {
+		return v1web.NewRequestError(fmt.Errorf("missing query parameter: limit"), http.StatusBadRequest)
+	}
+	limit, err := strconv.Atoi(limitQueries[0])
+	if err != nil || limit < 1 {
+		return v1web.NewRequestError(fmt.Errorf("invalid 'limit' format: %s", limitQueries[0]), http.StatusBadRequest)
+	}
+
+	// order
+	order := "asc"
+	if orderQueries := web.Query(r, "order"); len(orderQueries) > 0 {
+		order = orderQueries[0]
+	}
+	if order != "asc" && order != "desc" {
+		return v1web.NewRequestError(fmt.Errorf("invalid 'order' format: %s", order), http.StatusBadRequest)
+	}
+
+	// page
+	pageQueries := web.Query(r, "page")
+	if len(pageQueries) == 0 {
+		return v1web.NewRequestError(fmt.Errorf("missing query parameter: page"), http.StatusBadRequest)
+	}
+	page, err := strconv.Atoi(pageQueries[0])
+	if err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid 'page' format: %s", pageQueries[0]), http.StatusBadRequest)
+	}
+
+	type Payload struct {
+		NumOfPages int64               `json:"num_of_pages"`
+		NumOfTxns  int64               `json:"num_of_txns"`
+		Items      []txndb.Transaction `json:"items"`
+	}
+
+	// Rounds without transactions are common, and have no page to read.
+	count, err := h.TransactionCore.GetTransactionCountByRound(ctx, num)
+	if err != nil {
+		return fmt.Errorf("error fetching transaction count of round %d: %w", num, err)
+	}
+	if count == 0 {
+		return web.Respond(ctx, w, Payload{Items: []txndb.Transaction{}}, http.StatusOK)
+	}
+
+	result, numOfPages, numOfTxns, err := h.TransactionCore.GetTransactionsByRoundPagination(ctx, num, order, int64(page), int64(limit))
+	if err != nil {
+		return fmt.Errorf("error fetching pagination results: %w", err)
+	}
+
+	return web.Respond(ctx, w, Payload{
+		NumOfPages: numOfPages,
+		NumOfTxns:  numOfTxns,
+		Items:      result,
+	}, http.StatusOK)
+}
+
+// itemsOf returns the blocks of a list page as they are to be sent back: their
+// headers alone when the header_only query parameter is set, the whole blocks
+// otherwise.
+func itemsOf(r *http.Request, blocks []db.Block) (interface{}, error) {
+	headerOnlyQueries := web.Query(r, "header_only")
+	if len(headerOnlyQueries) == 0 {
+		return blocks, nil
+	}
+
+	headerOnly, err := strconv.ParseBool(headerOnlyQueries[0])
+	if err != nil {
+		return nil, v1web.NewRequestError(fmt.Errorf("invalid 'header_only' format: %s", headerOnlyQueries[0]), http.StatusBadRequest)
+	}
+	if headerOnly {
+		return db.Headers(blocks), nil
+	}
+	return blocks, nil
+}
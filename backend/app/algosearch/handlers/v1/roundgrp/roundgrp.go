@@ -5,7 +5,8 @@ import (
 	"fmt"
 	"github.com/kevguy/algosearch/backend/business/core/algod"
 	"github.com/kevguy/algosearch/backend/business/core/block"
-	"github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
 	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
@@ -14,8 +15,9 @@ import (
 )
 
 type Handlers struct {
-	BlockCore block.Core
-	AlgodCore algod.Core
+	BlockCore       block.Core
+	TransactionCore transaction.Core
+	AlgodCore       algod.Core
 }
 
 // GetCurrentRoundFromAPI retrieves the current round and returns the block data from Algod API
@@ -114,7 +116,11 @@ func (h Handlers) GetEarliestSyncedRound(ctx context.Context, w http.ResponseWri
 // The application counts from the latest_blk, calculates the number of pages using the number
 // of items specified and retrieves the list of block for different pages.
 // It returns the number of pages, number of blocks til the end and the list of blocks of interest
-// as the response.
+// as the response, along with the cursors to the pages before and after.
+//
+// Without page, the blocks are read from the optional cursor parameter instead, a
+// next or prev cursor of an earlier response, and latest_blk is not needed.
+// With header_only set, only the headers of the blocks are sent back.
 func (h Handlers) GetRoundsPagination(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 
 	// limit
@@ -123,41 +129,41 @@ func (h Handlers) GetRoundsPagination(ctx context.Context, w http.ResponseWriter
 		return v1web.NewRequestError(fmt.Errorf("missing query parameter: limit"), http.StatusBadRequest)
 	}
 	limit, err := strconv.Atoi(limitQueries[0])
-	if err != nil {
+	if err != nil || limit < 1 {
 		return v1web.NewRequestError(fmt.Errorf("invalid 'limit' format: %s", limitQueries[0]), http.StatusBadRequest)
 	}
 
-	// latest_blk
-	latestBlkQueries := web.Query(r, "latest_blk")
-	if len(latestBlkQueries) == 0 {
-		return v1web.NewRequestError(fmt.Errorf("missing query parameter: latest_blk"), http.StatusBadRequest)
+	// order
+	var order string
+	orderQueries := web.Query(r, "order")
+	if len(orderQueries) == 0 {
+		//return validate.NewRequestError(fmt.Errorf("missing query parameter: sort"), http.StatusBadRequest)
+		order = "desc"
+	} else {
+		order = orderQueries[0]
 	}
-	latestBlk, err := strconv.Atoi(latestBlkQueries[0])
-	if err != nil {
-		return v1web.NewRequestError(fmt.Errorf("invalid 'start' format: %s", latestBlkQueries[0]), http.StatusBadRequest)
+	if order != "asc" && order != "desc" {
+		return v1web.NewRequestError(fmt.Errorf("invalid 'sort' format: %s", orderQueries[0]), http.StatusBadRequest)
 	}
 
 	// page
 	pageQueries := web.Query(r, "page")
 	if len(pageQueries) == 0 {
-		return v1web.NewRequestError(fmt.Errorf("missing query parameter: page"), http.StatusBadRequest)
+		return h.getRoundsByCursor(ctx, w, r, order, int64(limit))
 	}
 	page, err := strconv.Atoi(pageQueries[0])
 	if err != nil {
-		return v1web.NewRequestError(fmt.Errorf("invalid 'page' format: %s", latestBlkQueries[0]), http.StatusBadRequest)
+		return v1web.NewRequestError(fmt.Errorf("invalid 'page' format: %s", pageQueries[0]), http.StatusBadRequest)
 	}
 
-	// order
-	var order string
-	orderQueries := web.Query(r, "order")
-	if len(orderQueries) == 0 {
-		//return validate.NewRequestError(fmt.Errorf("missing query parameter: sort"), http.StatusBadRequest)
-		order = "desc"
-	} else {
-		order = orderQueries[0]
+	// latest_blk
+	latestBlkQueries := web.Query(r, "latest_blk")
+	if len(latestBlkQueries) == 0 {
+		return v1web.NewRequestError(fmt.Errorf("missing query parameter: latest_blk"), http.StatusBadRequest)
 	}
-	if order != "asc" && order != "desc" {
-		return v1web.NewRequestError(fmt.Errorf("invalid 'sort' format: %s", orderQueries[0]), http.StatusBadRequest)
+	latestBlk, err := strconv.Atoi(latestBlkQueries[0])
+	if err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid 'start' format: %s", latestBlkQueries[0]), http.StatusBadRequest)
 	}
 
 	result, numOfPages, numOfBlks, err := h.BlockCore.GetBlocksPagination(ctx, int64(latestBlk), order, int64(page), int64(limit))
@@ -165,16 +171,60 @@ func (h Handlers) GetRoundsPagination(ctx context.Context, w http.ResponseWriter
 		return errors.Wrap(err, "Error fetching pagination results")
 	}
 
+	var links cursor.Links
+	if len(result) > 0 {
+		links = cursor.Around(result[0].Position(), result[len(result)-1].Position(), page > 1, int64(page) < numOfPages)
+	}
+
+	items, err := itemsOf(r, result)
+	if err != nil {
+		return err
+	}
+
 	type Payload struct {
 		NumOfPages	int64 `json:"num_of_pages"`
 		NumOfBlks	int64   `json:"num_of_blks"`
-		Items []db.Block `json:"items"`
+		Items interface{} `json:"items"`
+		cursor.Links
 	}
 
 	return web.Respond(ctx, w, Payload{
 		NumOfPages: numOfPages,
 		NumOfBlks:  numOfBlks,
-		Items:      result,
+		Items:      items,
+		Links:      links,
 	}, http.StatusOK)
 }
 
+// getRoundsByCursor responds with up to limit blocks read from the cursor
+// query parameter, or from the start of the list without it, along with the
+// cursors to the pages before and after.
+func (h Handlers) getRoundsByCursor(ctx context.Context, w http.ResponseWriter, r *http.Request, order string, limit int64) error {
+	var cur *cursor.Cursor
+	if cursorQueries := web.Query(r, "cursor"); len(cursorQueries) > 0 {
+		var err error
+		if cur, err = cursor.Decode(cursorQueries[0]); err != nil {
+			return v1web.NewRequestError(err, http.StatusBadRequest)
+		}
+	}
+
+	result, links, err := h.BlockCore.GetBlocksByCursor(ctx, cur, order, limit)
+	if err != nil {
+		return errors.Wrap(err, "Error fetching cursor results")
+	}
+
+	items, err := itemsOf(r, result)
+	if err != nil {
+		return err
+	}
+
+	type Payload struct {
+		Items interface{} `json:"items"`
+		cursor.Links
+	}
+
+	return web.Respond(ctx, w, Payload{
+		Items: items,
+		Links: links,
+	}, http.StatusOK)
+}
@@ -38,6 +38,7 @@ func (h Handlers) SrchKey(ctx context.Context, w http.ResponseWriter, r *http.Re
 	var acctFound = false
 	var assetFound = false
 	var appFound = false
+	var groupFound = false
 
 	// Search if block exists
 	block, err := h.BlockCore.GetBlockByHash(ctx, keyQueries[0])
@@ -85,7 +86,10 @@ func (h Handlers) SrchKey(ctx context.Context, w http.ResponseWriter, r *http.Re
 
 	// TODO: Search by Asset Name
 
-	// TODO: Search Group Tx ID
+	// Search if transaction group exists
+	if _, err := h.TransactionCore.GetGroup(ctx, keyQueries[0]); err == nil {
+		groupFound = true
+	}
 
 	type Response struct {
 		BlockHashFound bool `json:"block_hash_found"`
@@ -94,6 +98,7 @@ func (h Handlers) SrchKey(ctx context.Context, w http.ResponseWriter, r *http.Re
 		AcctFound bool `json:"acct_found"`
 		AssetFound bool `json:"asset_found"`
 		AppFound bool `json:"application_found"`
+		GroupFound bool `json:"group_found"`
 	}
 
 	return web.Respond(ctx, w, Response{
@@ -103,5 +108,6 @@ func (h Handlers) SrchKey(ctx context.Context, w http.ResponseWriter, r *http.Re
 		AcctFound:       acctFound,
 		AssetFound:      assetFound,
 		AppFound:        appFound,
+		GroupFound:      groupFound,
 	}, http.StatusOK)
 }
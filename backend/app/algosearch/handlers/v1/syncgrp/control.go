@@ -0,0 +1,99 @@
+package syncgrp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kevguy/algosearch/backend/app/algosearch/blocksynchronizer"
+	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"github.com/pkg/errors"
+)
+
+// errSyncDisabled is returned by the sync control endpoints when this instance
+// was started without the block synchronizer.
+var errSyncDisabled = errors.New("block synchronizer is not enabled on this instance")
+
+// StopAt is what we expect from clients setting the stop-at round. A null
+// round lifts the limit.
+type StopAt struct {
+	Round *uint64 `json:"round"`
+}
+
+// GetStatus reports what the block synchronizer of this instance is doing.
+func (h Handlers) GetStatus(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.Synchronizer == nil {
+		return v1web.NewRequestError(errSyncDisabled, http.StatusServiceUnavailable)
+	}
+
+	status, err := h.Synchronizer.Status(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to get sync status")
+	}
+
+	return web.Respond(ctx, w, status, http.StatusOK)
+}
+
+// Pause stops the block synchronizer right away. The round in progress is
+// synced again once it resumes.
+func (h Handlers) Pause(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.Synchronizer == nil {
+		return v1web.NewRequestError(errSyncDisabled, http.StatusServiceUnavailable)
+	}
+
+	h.Synchronizer.Pause()
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// Resume starts the block synchronizer again.
+func (h Handlers) Resume(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.Synchronizer == nil {
+		return v1web.NewRequestError(errSyncDisabled, http.StatusServiceUnavailable)
+	}
+
+	h.Synchronizer.Resume()
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// SetStopAt sets or clears the round the block synchronizer stops at.
+func (h Handlers) SetStopAt(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.Synchronizer == nil {
+		return v1web.NewRequestError(errSyncDisabled, http.StatusServiceUnavailable)
+	}
+
+	var stopAt StopAt
+	if err := web.Decode(r, &stopAt); err != nil {
+		return v1web.NewRequestError(errors.Wrap(err, "unable to decode payload"), http.StatusBadRequest)
+	}
+
+	h.Synchronizer.SetStopAt(stopAt.Round)
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// RestartFrom makes the block synchronizer sync again from a round (num). It
+// answers 409 when another instance holds the sync lease.
+func (h Handlers) RestartFrom(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.Synchronizer == nil {
+		return v1web.NewRequestError(errSyncDisabled, http.StatusServiceUnavailable)
+	}
+
+	numStr := web.Param(r, "num")
+	num, err := strconv.ParseUint(numStr, 10, 64)
+	if err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid num format: %s", numStr), http.StatusBadRequest)
+	}
+
+	if err := h.Synchronizer.RestartFrom(num); err != nil {
+		if errors.Is(err, blocksynchronizer.ErrNotLeader) {
+			return v1web.NewRequestError(err, http.StatusConflict)
+		}
+		return errors.Wrapf(err, "unable to restart from round %d", num)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusAccepted)
+}
@@ -0,0 +1,71 @@
+// Package syncgrp maintains the group of handlers for inspecting and steering
+// the block synchronizer.
+package syncgrp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kevguy/algosearch/backend/app/algosearch/blocksynchronizer"
+	"github.com/kevguy/algosearch/backend/business/core/block"
+	"github.com/kevguy/algosearch/backend/business/core/block/db"
+	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"github.com/pkg/errors"
+)
+
+type Handlers struct {
+	Ingester  blocksynchronizer.Ingester
+	BlockCore block.Core
+	// Synchronizer is nil when this instance doesn't run the synchronizer.
+	Synchronizer *blocksynchronizer.BlockSynchronizer
+}
+
+// GetGaps scans the synced rounds and returns the ranges of rounds missing
+// from the database.
+func (h Handlers) GetGaps(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	gaps, err := h.BlockCore.GetRoundGaps(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to scan for gaps")
+	}
+
+	var missing uint64
+	for _, gap := range gaps {
+		missing += gap.Size()
+	}
+
+	return web.Respond(ctx, w, struct {
+		Gaps          []db.RoundGap `json:"gaps"`
+		MissingRounds uint64        `json:"missing_rounds"`
+	}{
+		Gaps:          gaps,
+		MissingRounds: missing,
+	}, http.StatusOK)
+}
+
+// GetFailedRounds retrieves every round the synchronizer gave up on.
+func (h Handlers) GetFailedRounds(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	failedRounds, err := h.Ingester.GetFailedRounds(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to get failed rounds")
+	}
+
+	return web.Respond(ctx, w, failedRounds, http.StatusOK)
+}
+
+// RedriveFailedRound ingests a failed round (num) again.
+func (h Handlers) RedriveFailedRound(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	numStr := web.Param(r, "num")
+	num, err := strconv.ParseUint(numStr, 10, 64)
+	if err != nil {
+		return v1web.NewRequestError(fmt.Errorf("invalid num format: %s", numStr), http.StatusBadRequest)
+	}
+
+	if err := h.Ingester.RedriveFailedRound(ctx, num); err != nil {
+		return errors.Wrapf(err, "unable to re-drive round %d", num)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
@@ -0,0 +1,41 @@
+package transactiongrp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kevguy/algosearch/backend/business/core/transaction/db"
+	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"github.com/pkg/errors"
+)
+
+// GetInnerTransaction retrieves an inner transaction from CouchDB based on the ID of its
+// top-level transaction (id) and its dot-separated path (path), e.g. "1.0" for the first
+// inner transaction of the second inner transaction.
+func (h Handlers) GetInnerTransaction(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := web.Param(r, "id")
+	pathStr := web.Param(r, "path")
+
+	var path []int
+	for _, s := range strings.Split(pathStr, ".") {
+		idx, err := strconv.Atoi(s)
+		if err != nil || idx < 0 {
+			return v1web.NewRequestError(fmt.Errorf("invalid path format: %s", pathStr), http.StatusBadRequest)
+		}
+		path = append(path, idx)
+	}
+
+	transactionData, err := h.TransactionCore.GetInnerTransaction(ctx, id, path)
+	if err != nil {
+		if errors.Is(err, db.ErrInnerTxnNotFound) {
+			return v1web.NewRequestError(err, http.StatusNotFound)
+		}
+		return errors.Wrapf(err, "unable to get inner transaction %s of transaction %s", pathStr, id)
+	}
+
+	return web.Respond(ctx, w, transactionData, http.StatusOK)
+}
@@ -3,8 +3,10 @@ package transactiongrp
 import (
 	"context"
 	"fmt"
+	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/core/transaction"
 	"github.com/kevguy/algosearch/backend/business/core/transaction/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
 	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
@@ -16,7 +18,8 @@ type Handlers struct {
 	TransactionCore transaction.Core
 }
 
-// GetTransaction retrieves a block from CouchDB based on the round number (num)
+// GetTransaction retrieves a transaction document from CouchDB based on the
+// transaction ID (id), including the fields of the newer transaction types.
 func (h Handlers) GetTransaction(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	_, err := web.GetValues(ctx)
 	if err != nil {
@@ -27,6 +30,9 @@ func (h Handlers) GetTransaction(ctx context.Context, w http.ResponseWriter, r *
 	// TODO: add trace ID
 	transactionData, err := h.TransactionCore.GetTransaction(ctx, id)
 	if err != nil {
+		if kivik.StatusCode(err) == http.StatusNotFound || errors.Is(err, db.ErrNotCommitted) {
+			return v1web.NewRequestError(fmt.Errorf("transaction %s not found", id), http.StatusNotFound)
+		}
 		return errors.Wrapf(err, "unable to get transaction %s", id)
 	}
 
@@ -63,6 +69,10 @@ func (h Handlers) GetEarliestSyncedTransaction(ctx context.Context, w http.Respo
 	return web.Respond(ctx, w, transactionData, http.StatusOK)
 }
 
+// GetTransactionsPagination pages through the transactions with the page and
+// latest_txn query parameters, or, without page, reads them from the optional
+// cursor parameter, a next or prev cursor of an earlier response. Both answer
+// with the cursors to the pages before and after.
 func (h Handlers) GetTransactionsPagination(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 
 	// limit
@@ -71,21 +81,40 @@ func (h Handlers) GetTransactionsPagination(ctx context.Context, w http.Response
 		return v1web.NewRequestError(fmt.Errorf("missing query parameter: limit"), http.StatusBadRequest)
 	}
 	limit, err := strconv.Atoi(limitQueries[0])
-	if err != nil {
+	if err != nil || limit < 1 {
 		return v1web.NewRequestError(fmt.Errorf("invalid 'limit' format: %s", limitQueries[0]), http.StatusBadRequest)
 	}
 
-	// latest_txn
-	latestTxnQueries := web.Query(r, "latest_txn")
-	if len(latestTxnQueries) == 0 {
-		return v1web.NewRequestError(fmt.Errorf("missing query parameter: latest_txn"), http.StatusBadRequest)
+	// order
+	var order string
+	orderQueries := web.Query(r, "order")
+	if len(orderQueries) == 0 {
+		//return validate.NewRequestError(fmt.Errorf("missing query parameter: sort"), http.StatusBadRequest)
+		order = "desc"
+	} else {
+		order = orderQueries[0]
+	}
+	if order != "asc" && order != "desc" {
+		return v1web.NewRequestError(fmt.Errorf("invalid 'sort' format: %s", orderQueries[0]), http.StatusBadRequest)
 	}
-	latestTxn := latestTxnQueries[0]
 
 	// page
 	pageQueries := web.Query(r, "page")
 	if len(pageQueries) == 0 {
-		return v1web.NewRequestError(fmt.Errorf("missing query parameter: page"), http.StatusBadRequest)
+		cur, err := cursorOf(r)
+		if err != nil {
+			return err
+		}
+
+		result, links, err := h.TransactionCore.GetTransactionsByCursor(ctx, cur, order, int64(limit))
+		if err != nil {
+			return fmt.Errorf("error fetching cursor results: %w", err)
+		}
+
+		return web.Respond(ctx, w, cursorPayload{
+			Items: result,
+			Links: links,
+		}, http.StatusOK)
 	}
 	page, err := strconv.Atoi(pageQueries[0])
 	if err != nil {
@@ -93,33 +122,60 @@ func (h Handlers) GetTransactionsPagination(ctx context.Context, w http.Response
 		return v1web.NewRequestError(fmt.Errorf("invalid 'page' format: %s", pageQueries[0]), http.StatusBadRequest)
 	}
 
-	// order
-	var order string
-	orderQueries := web.Query(r, "order")
-	if len(orderQueries) == 0 {
-		//return validate.NewRequestError(fmt.Errorf("missing query parameter: sort"), http.StatusBadRequest)
-		order = "desc"
-	} else {
-		order = orderQueries[0]
-	}
-	if order != "asc" && order != "desc" {
-		return v1web.NewRequestError(fmt.Errorf("invalid 'sort' format: %s", orderQueries[0]), http.StatusBadRequest)
+	// latest_txn
+	latestTxnQueries := web.Query(r, "latest_txn")
+	if len(latestTxnQueries) == 0 {
+		return v1web.NewRequestError(fmt.Errorf("missing query parameter: latest_txn"), http.StatusBadRequest)
 	}
+	latestTxn := latestTxnQueries[0]
 
 	result, numOfPages, numOfTxns, err := h.TransactionCore.GetTransactionsPagination(ctx, latestTxn, order, int64(page), int64(limit))
 	if err != nil {
 		return fmt.Errorf("error fetching pagination results: %w", err)
 	}
 
-	type Payload struct {
-		NumOfPages	int64 `json:"num_of_pages"`
-		NumOfTxns	int64               `json:"num_of_txns"`
-		Items []db.Transaction `json:"items"`
-	}
-
-	return web.Respond(ctx, w, Payload{
+	return web.Respond(ctx, w, pagePayload{
 		NumOfPages: numOfPages,
 		NumOfTxns:  numOfTxns,
 		Items:      result,
+		Links:      pageLinks(result, page, numOfPages),
 	}, http.StatusOK)
 }
+
+// pagePayload is the response to a page of transactions.
+type pagePayload struct {
+	NumOfPages int64            `json:"num_of_pages"`
+	NumOfTxns  int64            `json:"num_of_txns"`
+	Items      []db.Transaction `json:"items"`
+	cursor.Links
+}
+
+// cursorPayload is the response to transactions read from a cursor.
+type cursorPayload struct {
+	Items []db.Transaction `json:"items"`
+	cursor.Links
+}
+
+// cursorOf parses the optional cursor query parameter. It returns nil without
+// it, to read from the start of the list.
+func cursorOf(r *http.Request) (*cursor.Cursor, error) {
+	cursorQueries := web.Query(r, "cursor")
+	if len(cursorQueries) == 0 {
+		return nil, nil
+	}
+
+	cur, err := cursor.Decode(cursorQueries[0])
+	if err != nil {
+		return nil, v1web.NewRequestError(err, http.StatusBadRequest)
+	}
+	return cur, nil
+}
+
+// pageLinks returns the cursors to the pages on either side of page pageNo of
+// numOfPages pages of transactions.
+func pageLinks(txns []db.Transaction, pageNo int, numOfPages int64) cursor.Links {
+	if len(txns) == 0 {
+		return cursor.Links{}
+	}
+	return cursor.Around(txns[0].Position(), txns[len(txns)-1].Position(), pageNo > 1, int64(pageNo) < numOfPages)
+}
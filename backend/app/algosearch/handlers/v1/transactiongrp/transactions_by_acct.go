@@ -3,7 +3,6 @@ package transactiongrp
 import (
 	"context"
 	"fmt"
-	"github.com/kevguy/algosearch/backend/business/core/transaction/db"
 	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"net/http"
@@ -36,6 +35,10 @@ func (h Handlers) GetTransactionsByAcctIDCount(ctx context.Context, w http.Respo
 }
 
 
+// GetTransactionsByAcctID pages through the transactions of an account with the
+// page query parameter, or, without it, reads them from the optional cursor
+// parameter, a next or prev cursor of an earlier response. Both answer with the
+// cursors to the pages before and after.
 func (h Handlers) GetTransactionsByAcctID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 
 	acctID := web.Param(r, "acct_id")
@@ -46,21 +49,10 @@ func (h Handlers) GetTransactionsByAcctID(ctx context.Context, w http.ResponseWr
 		return v1web.NewRequestError(fmt.Errorf("missing query parameter: limit"), http.StatusBadRequest)
 	}
 	limit, err := strconv.Atoi(limitQueries[0])
-	if err != nil {
+	if err != nil || limit < 1 {
 		return v1web.NewRequestError(fmt.Errorf("invalid 'limit' format: %s", limitQueries[0]), http.StatusBadRequest)
 	}
 
-	// page
-	pageNoQueries := web.Query(r, "page")
-	if len(pageNoQueries) == 0 {
-		return v1web.NewRequestError(fmt.Errorf("missing query parameter: page"), http.StatusBadRequest)
-	}
-	pageNo, err := strconv.Atoi(pageNoQueries[0])
-	if err != nil {
-		//return v1web.NewRequestError(fmt.Errorf("invalid 'page' format: %s", latestTxnQueries[0]), http.StatusBadRequest)
-		return v1web.NewRequestError(fmt.Errorf("invalid 'page' format: %s", pageNoQueries[0]), http.StatusBadRequest)
-	}
-
 	// order
 	var order string
 	orderQueries := web.Query(r, "order")
@@ -74,20 +66,39 @@ func (h Handlers) GetTransactionsByAcctID(ctx context.Context, w http.ResponseWr
 		return v1web.NewRequestError(fmt.Errorf("invalid 'sort' format: %s", orderQueries[0]), http.StatusBadRequest)
 	}
 
-	result, numOfPages, numOfTxns, err := h.TransactionCore.GetTransactionsByAcctPagination(ctx, acctID, order, int64(pageNo), int64(limit))
+	// page
+	pageNoQueries := web.Query(r, "page")
+	if len(pageNoQueries) == 0 {
+		cur, err := cursorOf(r)
+		if err != nil {
+			return err
+		}
+
+		result, links, err := h.TransactionCore.GetTransactionsByAcctCursor(ctx, acctID, cur, order, int64(limit))
+		if err != nil {
+			return fmt.Errorf("error fetching cursor results: %w", err)
+		}
+
+		return web.Respond(ctx, w, cursorPayload{
+			Items: result,
+			Links: links,
+		}, http.StatusOK)
+	}
+	pageNo, err := strconv.Atoi(pageNoQueries[0])
 	if err != nil {
-		return fmt.Errorf("error fetching pagination results: %w", err)
+		//return v1web.NewRequestError(fmt.Errorf("invalid 'page' format: %s", latestTxnQueries[0]), http.StatusBadRequest)
+		return v1web.NewRequestError(fmt.Errorf("invalid 'page' format: %s", pageNoQueries[0]), http.StatusBadRequest)
 	}
 
-	type Payload struct {
-		NumOfPages	int64 `json:"num_of_pages"`
-		NumOfTxns	int64 `json:"num_of_txns"`
-		Items []db.Transaction `json:"items"`
+	result, numOfPages, numOfTxns, err := h.TransactionCore.GetTransactionsByAcctPagination(ctx, acctID, order, int64(pageNo), int64(limit))
+	if err != nil {
+		return fmt.Errorf("error fetching pagination results: %w", err)
 	}
 
-	return web.Respond(ctx, w, Payload{
+	return web.Respond(ctx, w, pagePayload{
 		NumOfPages: numOfPages,
 		NumOfTxns:  numOfTxns,
 		Items:      result,
+		Links:      pageLinks(result, pageNo, numOfPages),
 	}, http.StatusOK)
 }
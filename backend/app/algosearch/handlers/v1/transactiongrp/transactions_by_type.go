@@ -0,0 +1,51 @@
+package transactiongrp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kevguy/algosearch/backend/business/core/algod"
+	v1web "github.com/kevguy/algosearch/backend/business/web/v1"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"github.com/pkg/errors"
+)
+
+// defaultTypeLimit is the number of transactions returned by GetTransactionsByType
+// when no limit is given.
+const defaultTypeLimit = 20
+
+// GetTransactionsByType retrieves the transactions of a type (type), e.g. pay, appl or stpf.
+func (h Handlers) GetTransactionsByType(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	txnType := web.Param(r, "type")
+	if !algod.IsKnownTxnType(txnType) {
+		return v1web.NewRequestError(fmt.Errorf("unknown transaction type: %s", txnType), http.StatusBadRequest)
+	}
+
+	// limit
+	limit := defaultTypeLimit
+	if limitQueries := web.Query(r, "limit"); len(limitQueries) > 0 {
+		var err error
+		limit, err = strconv.Atoi(limitQueries[0])
+		if err != nil || limit < 1 {
+			return v1web.NewRequestError(fmt.Errorf("invalid 'limit' format: %s", limitQueries[0]), http.StatusBadRequest)
+		}
+	}
+
+	// order
+	order := "desc"
+	if orderQueries := web.Query(r, "order"); len(orderQueries) > 0 {
+		order = orderQueries[0]
+	}
+	if order != "asc" && order != "desc" {
+		return v1web.NewRequestError(fmt.Errorf("invalid 'order' format: %s", order), http.StatusBadRequest)
+	}
+
+	transactions, err := h.TransactionCore.GetTransactionsByType(ctx, txnType, order, int64(limit))
+	if err != nil {
+		return errors.Wrapf(err, "unable to get transactions of type %s", txnType)
+	}
+
+	return web.Respond(ctx, w, transactions, http.StatusOK)
+}
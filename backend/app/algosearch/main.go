@@ -6,7 +6,10 @@ import (
 	"expvar" // Calls init function.
 	"fmt"
 	"github.com/kevguy/algosearch/backend/app/algosearch/blocksynchronizer"
+	"github.com/kevguy/algosearch/backend/business/data/store/bolt"
+	"github.com/kevguy/algosearch/backend/business/data/store/postgres"
 	"github.com/kevguy/algosearch/backend/business/sys/auth"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
 	"github.com/kevguy/algosearch/backend/foundation/algod"
 	"github.com/kevguy/algosearch/backend/foundation/couchdb"
 	"github.com/kevguy/algosearch/backend/foundation/indexer"
@@ -21,6 +24,8 @@ import (
 
 	_indexer "github.com/algorand/go-algorand-sdk/client/v2/indexer"
 	"github.com/ardanlabs/conf/v2"
+	"github.com/go-kivik/kivik/v4"
+	"github.com/jmoiron/sqlx"
 	"github.com/kevguy/algosearch/backend/app/algosearch/handlers"
 	"github.com/kevguy/algosearch/backend/foundation/logger"
 	"go.opentelemetry.io/otel"
@@ -85,7 +90,24 @@ func run(log *zap.SugaredLogger) error {
 			IdleTimeout     time.Duration `conf:"default:120s"`
 			ShutdownTimeout time.Duration `conf:"default:20s"`
 			EnableSync      bool          `conf:"default:true,help:specifies if the API should auto-sync new blocks"`
-			SyncInternal    time.Duration `conf:"default:3s"`
+			BlockSource     string        `conf:"default:algod,help:where new rounds are read from, algod or indexer"`
+			SyncInternal    time.Duration `conf:"default:3s,help:how often to poll the block source for new rounds when not waiting on it"`
+			WaitForBlock    bool          `conf:"default:true,help:long-poll the block source for the next round instead of polling on an interval, algod only"`
+			CatchupWorkers  int           `conf:"default:8,help:number of rounds ingested concurrently while catching up"`
+			CatchupLag      uint64        `conf:"default:10,help:rounds behind the tip before switching to catch-up mode"`
+			CatchupBatch    uint64        `conf:"default:100,help:rounds backfilled between recording the last synced round"`
+			EnrichWorkers   int           `conf:"default:8,help:concurrent algod lookups for the accounts, assets and applications of a round"`
+			StateDeltas     bool          `conf:"default:true,help:update accounts, assets and applications from the state delta of each round instead of looking them up"`
+			RetryAttempts   int           `conf:"default:5,help:attempts made to fetch a round from the block source before recording it as failed"`
+			RetryDelay      time.Duration `conf:"default:500ms,help:wait before retrying a round, doubled on every retry"`
+			RetryMaxDelay   time.Duration `conf:"default:30s,help:maximum wait between two retries of a round"`
+			GapScanInterval time.Duration `conf:"default:5m,help:how often synced rounds are scanned for gaps, 0 disables the scan"`
+			SyncLeaseTTL    time.Duration `conf:"default:30s,help:how long the lease electing the one replica that syncs lasts, 0 makes every replica sync"`
+			SyncInstanceID  string        `conf:"help:identifies this replica as the sync lease holder, defaults to host name and pid"`
+			SyncStartRound  string        `conf:"default:0,help:round to start at on an empty database, a round number or tip-N for N rounds below the tip"`
+			RetentionRounds uint64        `conf:"default:0,help:number of recent rounds kept, older blocks and transactions are pruned, 0 keeps everything"`
+			PruneInterval   time.Duration `conf:"default:1h,help:how often rounds outside the retention window are pruned"`
+			Storage         string        `conf:"default:couchdb,help:where chain data is stored: couchdb|postgres|bolt"`
 		}
 		Auth struct {
 			KeysFolder string `conf:"default:zarf/keys/"`
@@ -98,6 +120,18 @@ func run(log *zap.SugaredLogger) error {
 			Host     string `conf:"default:localhost:5984"`
 			Name     string `conf:"default:algo_global"`
 		}
+		DB struct {
+			User         string `conf:"default:postgres"`
+			Password     string `conf:"default:postgres,mask"`
+			Host         string `conf:"default:localhost"`
+			Name         string `conf:"default:postgres"`
+			MaxIdleConns int    `conf:"default:0"`
+			MaxOpenConns int    `conf:"default:0"`
+			DisableTLS   bool   `conf:"default:true"`
+		}
+		Bolt struct {
+			DataDir string `conf:"default:data,help:directory the bolt storage keeps its file in"`
+		}
 		Algorand struct {
 			AlgodProtocol   string `conf:"default:http,env:ALGOD_PROTOCOL"`
 			AlgodAddr       string `conf:"default:localhost:4001,env:ALGOD_ADDR"`
@@ -223,20 +257,74 @@ func run(log *zap.SugaredLogger) error {
 	}
 
 	// =========================================================================
-	// Start CouchDB Client
+	// Start Block Source
 
-	log.Infow("startup", "status", "initializing couchdb client support", "host", cfg.CouchDB.Host)
+	log.Infow("startup", "status", "initializing block source", "source", cfg.Web.BlockSource)
 
-	couchConfig := couchdb.Config{
-		Protocol: cfg.CouchDB.Protocol,
-		User:     cfg.CouchDB.User,
-		Password: cfg.CouchDB.Password,
-		Host:     cfg.CouchDB.Host,
+	blockSource, err := blocksynchronizer.NewBlockSource(cfg.Web.BlockSource, log, algodClient, indexerClient)
+	if err != nil {
+		return fmt.Errorf("initializing block source: %w", err)
 	}
 
-	db, err := couchdb.Open(couchConfig)
-	if err != nil {
-		return fmt.Errorf("connecting to couchdb database: %w", err)
+	// =========================================================================
+	// Start Storage
+
+	var (
+		couchClient *kivik.Client
+		pgDB        *sqlx.DB
+		cores       blocksynchronizer.Cores
+	)
+	switch cfg.Web.Storage {
+	case "couchdb":
+		log.Infow("startup", "status", "initializing couchdb client support", "host", cfg.CouchDB.Host)
+
+		couchClient, err = couchdb.Open(couchdb.Config{
+			Protocol: cfg.CouchDB.Protocol,
+			User:     cfg.CouchDB.User,
+			Password: cfg.CouchDB.Password,
+			Host:     cfg.CouchDB.Host,
+		})
+		if err != nil {
+			return fmt.Errorf("connecting to couchdb database: %w", err)
+		}
+		cores = blocksynchronizer.NewCouchCores(log, couchClient, cfg.CouchDB.Name)
+
+	case "postgres":
+		log.Infow("startup", "status", "initializing postgres database support", "host", cfg.DB.Host)
+
+		pgDB, err = database.Open(database.Config{
+			User:         cfg.DB.User,
+			Password:     cfg.DB.Password,
+			Host:         cfg.DB.Host,
+			Name:         cfg.DB.Name,
+			MaxIdleConns: cfg.DB.MaxIdleConns,
+			MaxOpenConns: cfg.DB.MaxOpenConns,
+			DisableTLS:   cfg.DB.DisableTLS,
+		})
+		if err != nil {
+			return fmt.Errorf("connecting to postgres database: %w", err)
+		}
+		defer func() {
+			log.Infow("shutdown", "status", "stopping postgres database support", "host", cfg.DB.Host)
+			pgDB.Close()
+		}()
+		cores = blocksynchronizer.NewStoreCores(postgres.NewStore(log, pgDB))
+
+	case "bolt":
+		log.Infow("startup", "status", "opening bolt storage", "datadir", cfg.Bolt.DataDir)
+
+		store, err := bolt.Open(cfg.Bolt.DataDir)
+		if err != nil {
+			return fmt.Errorf("opening bolt storage: %w", err)
+		}
+		defer func() {
+			log.Infow("shutdown", "status", "closing bolt storage", "datadir", cfg.Bolt.DataDir)
+			store.Close()
+		}()
+		cores = blocksynchronizer.NewStoreCores(store)
+
+	default:
+		return fmt.Errorf("unknown storage %q, expected couchdb, postgres or bolt", cfg.Web.Storage)
 	}
 
 	hub := websocket.NewHub()
@@ -251,7 +339,7 @@ func run(log *zap.SugaredLogger) error {
 	// related endpoints. This includes the standard library endpoints.
 
 	// Construct the mux for the debug calls.
-	debugMux := handlers.DebugMux(build, log, db, algodClient)
+	debugMux := handlers.DebugMux(build, log, couchClient, pgDB, algodClient)
 
 	// Start the service listening for debug requests.
 	// Not concerned with shutting this down with load shedding.
@@ -261,6 +349,43 @@ func run(log *zap.SugaredLogger) error {
 		}
 	}()
 
+	// =========================================================================
+	// Start Block Synchronizer
+
+	var blocksync *blocksynchronizer.BlockSynchronizer
+	if cfg.Web.EnableSync {
+		startRound, err := blocksynchronizer.ParseStartRound(cfg.Web.SyncStartRound)
+		if err != nil {
+			return fmt.Errorf("parsing sync start round: %w", err)
+		}
+
+		// Start the publisher to collect/publish metrics.
+		blocksync, err = blocksynchronizer.New(log, blocksynchronizer.Config{
+			Interval:         cfg.Web.SyncInternal,
+			WaitForBlock:     cfg.Web.WaitForBlock,
+			CatchupWorkers:   cfg.Web.CatchupWorkers,
+			CatchupThreshold: cfg.Web.CatchupLag,
+			CatchupBatchSize: cfg.Web.CatchupBatch,
+			EnrichWorkers:    cfg.Web.EnrichWorkers,
+			StateDeltas:      cfg.Web.StateDeltas,
+			Retry: blocksynchronizer.RetryPolicy{
+				Attempts:  cfg.Web.RetryAttempts,
+				BaseDelay: cfg.Web.RetryDelay,
+				MaxDelay:  cfg.Web.RetryMaxDelay,
+			},
+			GapScanInterval: cfg.Web.GapScanInterval,
+			LeaseTTL:        cfg.Web.SyncLeaseTTL,
+			InstanceID:      cfg.Web.SyncInstanceID,
+			Start:           startRound,
+			RetentionRounds: cfg.Web.RetentionRounds,
+			PruneInterval:   cfg.Web.PruneInterval,
+		}, blockSource, algodClient, cores, hub)
+		if err != nil {
+			return fmt.Errorf("starting publisher: %w", err)
+		}
+		defer blocksync.Stop()
+	}
+
 	// =========================================================================
 	// Start API Service
 
@@ -280,9 +405,10 @@ func run(log *zap.SugaredLogger) error {
 		Auth:          auth,
 		AlgodClient:   algodClient,
 		IndexerClient: indexerClient,
-		CouchClient:   db,
+		BlockSource:   blockSource,
+		Synchronizer:  blocksync,
+		Cores:         cores,
 		Hub:           hub,
-		DBName:        cfg.CouchDB.Name,
 	})
 
 	// Construct a server to service the requests against the mux.
@@ -305,15 +431,6 @@ func run(log *zap.SugaredLogger) error {
 		serverErrors <- api.ListenAndServe()
 	}()
 
-	if cfg.Web.EnableSync {
-		// Start the publisher to collect/publish metrics.
-		blocksync, err := blocksynchronizer.New(log, cfg.Web.SyncInternal, algodClient, couchConfig, hub, cfg.CouchDB.Name)
-		if err != nil {
-			return fmt.Errorf("starting publisher: %w", err)
-		}
-		defer blocksync.Stop()
-	}
-
 	// =========================================================================
 	// Shutdown
 
@@ -155,5 +155,5 @@ func (a Agent) GetTransaction(ctx context.Context, traceID string, log *zap.Suga
 			return &idxTransaction, nil
 		}
 	}
-	return &couchTransaction, nil
+	return &couchTransaction.Transaction, nil
 }
@@ -7,12 +7,26 @@ import (
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
 	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/core/account/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
 	"go.uber.org/zap"
 )
 
+// Storer declares the behavior the account core needs from a storage backend.
+// The CouchDB store in the db package is the default one.
+type Storer interface {
+	AddAccount(ctx context.Context, account models.Account) (string, string, error)
+	AddAccounts(ctx context.Context, accounts []models.Account) (bool, error)
+	GetAccount(ctx context.Context, accountAddr string) (models.Account, error)
+	GetEarliestAccountID(ctx context.Context) (string, error)
+	GetLatestAccountID(ctx context.Context) (string, error)
+	GetAccountCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error)
+	GetAccountsPagination(ctx context.Context, latestAccountID string, order string, pageNo, limit int64) ([]db.Account, int64, int64, error)
+	GetAccountsFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]db.Account, bool, error)
+}
+
 // Core manages the set of API's for block access.
 type Core struct {
-	store db.Store
+	store Storer
 }
 
 // NewCore constructs a core for product api access.
@@ -22,6 +36,14 @@ func NewCore(log *zap.SugaredLogger, couchClient *kivik.Client, dbName string) C
 	}
 }
 
+// NewCoreWithStore constructs a core for account api access backed by the given
+// store.
+func NewCoreWithStore(store Storer) Core {
+	return Core{
+		store: store,
+	}
+}
+
 func (c Core) AddAccount(ctx context.Context, account models.Account) (string, string, error) {
 	return c.store.AddAccount(ctx, account)
 }
@@ -49,3 +71,18 @@ func (c Core) GetAccountCountBtnKeys(ctx context.Context, startKey, endKey strin
 func (c Core) GetAccountsPagination(ctx context.Context, latestAccountID string, order string, pageNo, limit int64) ([]db.Account, int64, int64, error) {
 	return c.store.GetAccountsPagination(ctx, latestAccountID, order, pageNo, limit)
 }
+
+// GetAccountsByCursor retrieves up to limit accounts read from cur, or from the
+// start of the list when cur is nil, along with the links to the pages on
+// either side of them.
+func (c Core) GetAccountsByCursor(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]db.Account, cursor.Links, error) {
+	accounts, more, err := c.store.GetAccountsFrom(ctx, cur, order, limit)
+	if err != nil {
+		return nil, cursor.Links{}, err
+	}
+	if len(accounts) == 0 {
+		return accounts, cursor.Links{}, nil
+	}
+
+	return accounts, cursor.LinksOf(cur, accounts[0].Position(), accounts[len(accounts)-1].Position(), more), nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
 	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/couchdb"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -57,7 +58,7 @@ func (s Store) AddAccount(ctx context.Context, account models.Account) (string,
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	rev, err := db.Put(ctx, doc.Address, doc)
+	rev, err := couchdb.Upsert(ctx, db, doc.Address, doc)
 	if err != nil {
 		return "", "", errors.Wrap(err, s.dbName+ " database can't insert account id " + doc.Address)
 	}
@@ -83,22 +84,19 @@ func (s Store) AddAccounts(ctx context.Context, accounts []models.Account) (bool
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	accounts_ := make([]interface{}, len(accounts))
-	//fmt.Println("Here are the accounts")
-	//fmt.Printf("%v\n", accounts)
-
-	// https://stackoverflow.com/questions/55755929/go-convert-interface-to-map
-	// https://stackoverflow.com/questions/44094325/add-data-to-interface-in-struct
+	ids := make([]string, len(accounts))
 	for i := range accounts {
-		doc := NewAccount{
-			ID:      &accounts[i].Address,
+		ids[i] = accounts[i].Address
+	}
+
+	err = couchdb.BulkUpsert(ctx, db, ids, func(i int, rev string) interface{} {
+		return NewAccount{
+			ID:      &ids[i],
+			Rev:     rev,
 			Account: accounts[i],
 			DocType: DocType,
 		}
-		accounts_[i] = doc
-	}
-
-	_, err = db.BulkDocs(ctx, accounts_)
+	})
 	if err != nil {
 		return false, errors.Wrap(err, "Can't bulk insert the accounts")
 	}
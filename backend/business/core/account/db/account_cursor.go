@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetAccountsFrom retrieves up to limit accounts read from cur, or from the
+// start of the list when cur is nil, ordered by address in the given order. The
+// boolean returned reports whether accounts are left past them in the
+// direction they were read in.
+func (s Store) GetAccountsFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]Account, bool, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "account.GetAccountsFrom")
+	span.SetAttributes(attribute.Int64("limit", limit))
+	defer span.End()
+
+	s.log.Infow("account.GetAccountsFrom",
+		"traceid", web.GetTraceID(ctx),
+		"cursor", cur,
+		"limit", limit)
+
+	if limit < 1 {
+		return nil, false, cursor.ErrLimit
+	}
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return nil, false, fmt.Errorf(s.dbName+" database check fails: %w", err)
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	descending := cur.Descending(order)
+
+	// The account at the cursor is read too when it exists, so one more than
+	// needed to tell whether accounts are left is asked for.
+	options := kivik.Options{
+		"include_docs": true,
+		"descending":   descending,
+		"limit":        limit + 2,
+	}
+	if cur != nil {
+		options["start_key"] = cur.ID
+	}
+
+	rows, err := db.Query(ctx, schema.AccountDDoc, "_view/"+schema.AccountViewByIDInLatest, options)
+	if err != nil {
+		return nil, false, fmt.Errorf("fetch data error: %w", err)
+	}
+	defer rows.Close()
+
+	var fetchedAccounts = []Account{}
+	for rows.Next() {
+		var account Account
+		if err := rows.ScanDoc(&account); err != nil {
+			return nil, false, fmt.Errorf("unwrapping account: %w", err)
+		}
+		if cur.Includes(account.Position(), descending) {
+			fetchedAccounts = append(fetchedAccounts, account)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+
+	more := int64(len(fetchedAccounts)) > limit
+	if more {
+		fetchedAccounts = fetchedAccounts[:limit]
+	}
+	if cur != nil && cur.Before {
+		for i, j := 0, len(fetchedAccounts)-1; i < j; i, j = i+1, j-1 {
+			fetchedAccounts[i], fetchedAccounts[j] = fetchedAccounts[j], fetchedAccounts[i]
+		}
+	}
+
+	return fetchedAccounts, more, nil
+}
@@ -1,9 +1,13 @@
 package db
 
-import "github.com/algorand/go-algorand-sdk/client/v2/common/models"
+import (
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+)
 
 type NewAccount struct {
 	ID *string `json:"_id"`
+	Rev string `json:"_rev,omitempty"`
 	models.Account
 	DocType string `json:"doc_type"`
 }
@@ -13,3 +17,9 @@ type Account struct {
 	ID		string	`json:"_id,omitempty"`
 	Rev		string	`json:"_rev,omitempty"`
 }
+
+// Position returns where the account stands in the list of accounts, which
+// orders them by address.
+func (a Account) Position() cursor.Position {
+	return cursor.Position{ID: a.ID}
+}
@@ -0,0 +1,579 @@
+package algod
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/types"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Kinds of creatables, as algod numbers them.
+const (
+	assetCreatable uint64 = 0
+	appCreatable   uint64 = 1
+)
+
+// accountStatuses maps the account statuses algod encodes in state deltas to
+// the names it uses in account objects.
+var accountStatuses = map[byte]string{
+	0: "Offline",
+	1: "Online",
+	2: "NotParticipating",
+}
+
+// LedgerStateDelta is the change a round made to the ledger, as algod serves it
+// from /v2/deltas. Only what is needed to update accounts, assets and
+// applications is decoded.
+type LedgerStateDelta struct {
+	Accts      AccountDeltas                `codec:"Accts"`
+	Creatables map[uint64]ModifiedCreatable `codec:"Creatables"`
+}
+
+// AccountDeltas lists the accounts and account resources a round changed.
+type AccountDeltas struct {
+	Accts          []BalanceRecord       `codec:"Accts"`
+	AppResources   []AppResourceRecord   `codec:"AppResources"`
+	AssetResources []AssetResourceRecord `codec:"AssetResources"`
+}
+
+// BalanceRecord is the state of an account at the end of the round, without its
+// asset and application resources.
+type BalanceRecord struct {
+	Addr                types.Address     `codec:"Addr"`
+	Status              byte              `codec:"Status"`
+	MicroAlgos          uint64            `codec:"MicroAlgos"`
+	RewardsBase         uint64            `codec:"RewardsBase"`
+	RewardedMicroAlgos  uint64            `codec:"RewardedMicroAlgos"`
+	AuthAddr            types.Address     `codec:"AuthAddr"`
+	TotalAppSchema      types.StateSchema `codec:"TotalAppSchema"`
+	TotalExtraAppPages  uint32            `codec:"TotalExtraAppPages"`
+	TotalAppParams      uint64            `codec:"TotalAppParams"`
+	TotalAppLocalStates uint64            `codec:"TotalAppLocalStates"`
+	TotalAssetParams    uint64            `codec:"TotalAssetParams"`
+	TotalAssets         uint64            `codec:"TotalAssets"`
+	VoteID              [32]byte          `codec:"VoteID"`
+	SelectionID         [32]byte          `codec:"SelectionID"`
+	StateProofID        [64]byte          `codec:"StateProofID"`
+	VoteFirstValid      uint64            `codec:"VoteFirstValid"`
+	VoteLastValid       uint64            `codec:"VoteLastValid"`
+	VoteKeyDilution     uint64            `codec:"VoteKeyDilution"`
+}
+
+// AssetResourceRecord is the change a round made to the holding of an asset by
+// an account, or to the parameters of an asset the account created.
+type AssetResourceRecord struct {
+	Aidx    uint64            `codec:"Aidx"`
+	Addr    types.Address     `codec:"Addr"`
+	Params  AssetParamsDelta  `codec:"Params"`
+	Holding AssetHoldingDelta `codec:"Holding"`
+}
+
+// AssetParamsDelta holds the new parameters of an asset, if they changed.
+type AssetParamsDelta struct {
+	Params  *types.AssetParams `codec:"Params"`
+	Deleted bool               `codec:"Deleted"`
+}
+
+// AssetHoldingDelta holds the new holding of an asset, if it changed.
+type AssetHoldingDelta struct {
+	Holding *AssetHolding `codec:"Holding"`
+	Deleted bool          `codec:"Deleted"`
+}
+
+// AssetHolding is the amount of an asset an account holds.
+type AssetHolding struct {
+	Amount uint64 `codec:"a"`
+	Frozen bool   `codec:"f"`
+}
+
+// AppResourceRecord is the change a round made to the local state of an
+// application in an account, or to the parameters of an application the
+// account created.
+type AppResourceRecord struct {
+	Aidx   uint64             `codec:"Aidx"`
+	Addr   types.Address      `codec:"Addr"`
+	Params AppParamsDelta     `codec:"Params"`
+	State  AppLocalStateDelta `codec:"State"`
+}
+
+// AppParamsDelta holds the new parameters of an application, if they changed.
+type AppParamsDelta struct {
+	Params  *AppParams `codec:"Params"`
+	Deleted bool       `codec:"Deleted"`
+}
+
+// AppLocalStateDelta holds the new local state of an application, if it changed.
+type AppLocalStateDelta struct {
+	LocalState *AppLocalState `codec:"LocalState"`
+	Deleted    bool           `codec:"Deleted"`
+}
+
+// AppParams are the programs and global state of an application.
+type AppParams struct {
+	ApprovalProgram   []byte               `codec:"approv"`
+	ClearStateProgram []byte               `codec:"clearp"`
+	GlobalState       map[string]TealValue `codec:"gs"`
+	LocalStateSchema  types.StateSchema    `codec:"lsch"`
+	GlobalStateSchema types.StateSchema    `codec:"gsch"`
+	ExtraProgramPages uint32               `codec:"epp"`
+}
+
+// AppLocalState is the state an application keeps in an account.
+type AppLocalState struct {
+	Schema   types.StateSchema    `codec:"hsch"`
+	KeyValue map[string]TealValue `codec:"tkv"`
+}
+
+// TealValue is a value kept in application state.
+type TealValue struct {
+	Type  uint64 `codec:"tt"`
+	Bytes string `codec:"tb"`
+	Uint  uint64 `codec:"ui"`
+}
+
+// ModifiedCreatable records an asset or application created or deleted in the
+// round.
+type ModifiedCreatable struct {
+	Ctype   uint64        `codec:"Ctype"`
+	Created bool          `codec:"Created"`
+	Creator types.Address `codec:"Creator"`
+}
+
+// ledgerStateDeltaParams are the query parameters of the state delta endpoint.
+type ledgerStateDeltaParams struct {
+	Format string `url:"format,omitempty"`
+}
+
+// GetLedgerStateDelta retrieves the change the given round made to the ledger.
+// algod only serves the deltas of recent rounds, unless it runs as a follower.
+func (c Core) GetLedgerStateDelta(ctx context.Context, roundNum uint64) (LedgerStateDelta, error) {
+
+	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "algod.GetLedgerStateDelta")
+	span.SetAttributes(attribute.Int64("round", int64(roundNum)))
+	defer span.End()
+
+	// The SDK has no request for state deltas yet, so the endpoint is called
+	// through the underlying client.
+	rawDelta, err := (*common.Client)(c.algodClient).GetRaw(ctx, fmt.Sprintf("/v2/deltas/%d", roundNum), ledgerStateDeltaParams{Format: "msgpack"}, nil)
+	if err != nil {
+		return LedgerStateDelta{}, errors.Wrapf(err, "unable to query for the state delta of round %d", roundNum)
+	}
+
+	return DecodeLedgerStateDelta(rawDelta)
+}
+
+// DecodeLedgerStateDelta decodes a state delta in the msgpack format algod
+// serves it in.
+func DecodeLedgerStateDelta(rawDelta []byte) (LedgerStateDelta, error) {
+	var delta LedgerStateDelta
+	if err := msgpack.NewLenientDecoder(bytes.NewReader(rawDelta)).Decode(&delta); err != nil {
+		return LedgerStateDelta{}, errors.Wrap(err, "decoding state delta")
+	}
+	return delta, nil
+}
+
+// Addresses lists the accounts the delta changes, each once.
+func (d LedgerStateDelta) Addresses() []string {
+	var addrs []string
+	seen := make(map[types.Address]bool)
+	add := func(addr types.Address) {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr.String())
+		}
+	}
+	for _, rec := range d.Accts.Accts {
+		add(rec.Addr)
+	}
+	for _, rec := range d.Accts.AssetResources {
+		add(rec.Addr)
+	}
+	for _, rec := range d.Accts.AppResources {
+		add(rec.Addr)
+	}
+	return addrs
+}
+
+// AssetIDs lists the assets whose parameters the delta changes.
+func (d LedgerStateDelta) AssetIDs() []uint64 {
+	var ids []uint64
+	for _, rec := range d.Accts.AssetResources {
+		if rec.Params.Params != nil || rec.Params.Deleted {
+			ids = append(ids, rec.Aidx)
+		}
+	}
+	return ids
+}
+
+// AppIDs lists the applications whose parameters the delta changes.
+func (d LedgerStateDelta) AppIDs() []uint64 {
+	var ids []uint64
+	for _, rec := range d.Accts.AppResources {
+		if rec.Params.Params != nil || rec.Params.Deleted {
+			ids = append(ids, rec.Aidx)
+		}
+	}
+	return ids
+}
+
+// LedgerState holds the stored accounts, assets and applications a state delta
+// is applied to. Entities missing from it are treated as new. Applying a delta
+// may reuse the slices of the accounts it holds.
+type LedgerState struct {
+	Accounts map[string]models.Account
+	Assets   map[uint64]models.Asset
+	Apps     map[uint64]models.Application
+}
+
+// ApplyStateDelta applies the delta of the given round to state and returns the
+// accounts, assets and applications it changed, as they are at the end of the
+// round. Accounts already updated by a later round are left alone.
+func ApplyStateDelta(round uint64, delta LedgerStateDelta, state LedgerState) ([]models.Account, []models.Asset, []models.Application) {
+	var addrs []string
+	accounts := make(map[string]*models.Account)
+	skipped := make(map[string]bool)
+	account := func(addr types.Address) *models.Account {
+		address := addr.String()
+		if acct, ok := accounts[address]; ok {
+			return acct
+		}
+		if skipped[address] {
+			return nil
+		}
+		acct, found := state.Accounts[address]
+		if found && acct.Round > round {
+			skipped[address] = true
+			return nil
+		}
+		if !found {
+			acct = models.Account{Address: address, CreatedAtRound: round}
+		}
+		acct.Round = round
+		accounts[address] = &acct
+		addrs = append(addrs, address)
+		return &acct
+	}
+
+	for _, rec := range delta.Accts.Accts {
+		if acct := account(rec.Addr); acct != nil {
+			applyBalanceRecord(acct, rec, round)
+		}
+	}
+
+	var assets []models.Asset
+	for _, rec := range delta.Accts.AssetResources {
+		acct := account(rec.Addr)
+		if acct == nil {
+			continue
+		}
+
+		switch {
+		case rec.Holding.Deleted:
+			acct.Assets = removeAssetHolding(acct.Assets, rec.Aidx)
+		case rec.Holding.Holding != nil:
+			acct.Assets = putAssetHolding(acct.Assets, rec.Aidx, *rec.Holding.Holding, round)
+		}
+
+		switch {
+		case rec.Params.Deleted:
+			acct.CreatedAssets = removeAsset(acct.CreatedAssets, rec.Aidx)
+			asset, found := state.Assets[rec.Aidx]
+			if !found {
+				asset = models.Asset{Index: rec.Aidx, Params: models.AssetParams{Creator: rec.Addr.String()}}
+			}
+			asset.Deleted = true
+			asset.DestroyedAtRound = round
+			assets = append(assets, asset)
+		case rec.Params.Params != nil:
+			asset := models.Asset{
+				Index:  rec.Aidx,
+				Params: convertAssetParams(rec.Addr, *rec.Params.Params),
+			}
+			if stored, found := state.Assets[rec.Aidx]; found {
+				asset.CreatedAtRound = stored.CreatedAtRound
+			}
+			if created(delta, rec.Aidx, assetCreatable) {
+				asset.CreatedAtRound = round
+			}
+			acct.CreatedAssets = putAsset(acct.CreatedAssets, asset)
+			assets = append(assets, asset)
+		}
+	}
+
+	var apps []models.Application
+	for _, rec := range delta.Accts.AppResources {
+		acct := account(rec.Addr)
+		if acct == nil {
+			continue
+		}
+
+		switch {
+		case rec.State.Deleted:
+			acct.AppsLocalState = removeAppLocalState(acct.AppsLocalState, rec.Aidx)
+		case rec.State.LocalState != nil:
+			acct.AppsLocalState = putAppLocalState(acct.AppsLocalState, rec.Aidx, *rec.State.LocalState, round)
+		}
+
+		switch {
+		case rec.Params.Deleted:
+			acct.CreatedApps = removeApp(acct.CreatedApps, rec.Aidx)
+			app, found := state.Apps[rec.Aidx]
+			if !found {
+				app = models.Application{Id: rec.Aidx, Params: models.ApplicationParams{Creator: rec.Addr.String()}}
+			}
+			app.Deleted = true
+			app.DeletedAtRound = round
+			apps = append(apps, app)
+		case rec.Params.Params != nil:
+			app := models.Application{
+				Id:     rec.Aidx,
+				Params: convertAppParams(rec.Addr, *rec.Params.Params),
+			}
+			if stored, found := state.Apps[rec.Aidx]; found {
+				app.CreatedAtRound = stored.CreatedAtRound
+			}
+			if created(delta, rec.Aidx, appCreatable) {
+				app.CreatedAtRound = round
+			}
+			acct.CreatedApps = putApp(acct.CreatedApps, app)
+			apps = append(apps, app)
+		}
+	}
+
+	changed := make([]models.Account, len(addrs))
+	for i, addr := range addrs {
+		changed[i] = *accounts[addr]
+	}
+	return changed, assets, apps
+}
+
+// applyBalanceRecord copies the state of an account at the end of a round
+// into acct.
+func applyBalanceRecord(acct *models.Account, rec BalanceRecord, round uint64) {
+	acct.Amount = rec.MicroAlgos
+	acct.AmountWithoutPendingRewards = rec.MicroAlgos
+	acct.PendingRewards = 0
+	acct.Rewards = rec.RewardedMicroAlgos
+	acct.RewardBase = rec.RewardsBase
+	acct.Status = accountStatuses[rec.Status]
+	acct.AuthAddr = addressOrEmpty(rec.AuthAddr)
+	acct.AppsTotalSchema = convertStateSchema(rec.TotalAppSchema)
+	acct.AppsTotalExtraPages = uint64(rec.TotalExtraAppPages)
+	acct.TotalCreatedApps = rec.TotalAppParams
+	acct.TotalAppsOptedIn = rec.TotalAppLocalStates
+	acct.TotalCreatedAssets = rec.TotalAssetParams
+	acct.TotalAssetsOptedIn = rec.TotalAssets
+
+	acct.Participation = models.AccountParticipation{}
+	if rec.VoteID != [32]byte{} {
+		acct.Participation = models.AccountParticipation{
+			VoteParticipationKey:      append([]byte(nil), rec.VoteID[:]...),
+			SelectionParticipationKey: append([]byte(nil), rec.SelectionID[:]...),
+			VoteFirstValid:            rec.VoteFirstValid,
+			VoteLastValid:             rec.VoteLastValid,
+			VoteKeyDilution:           rec.VoteKeyDilution,
+		}
+		if rec.StateProofID != [64]byte{} {
+			acct.Participation.StateProofKey = append([]byte(nil), rec.StateProofID[:]...)
+		}
+	}
+
+	// An account is closed out when it is left with nothing at all.
+	closed := rec.MicroAlgos == 0 && rec.TotalAssets == 0 && rec.TotalAssetParams == 0 &&
+		rec.TotalAppLocalStates == 0 && rec.TotalAppParams == 0
+	if closed && !acct.Deleted {
+		acct.ClosedAtRound = round
+	}
+	acct.Deleted = closed
+}
+
+// created reports whether the delta records the asset or application with the
+// given ID as created.
+func created(delta LedgerStateDelta, id uint64, ctype uint64) bool {
+	c, ok := delta.Creatables[id]
+	return ok && c.Created && c.Ctype == ctype
+}
+
+func putAssetHolding(holdings []models.AssetHolding, id uint64, holding AssetHolding, round uint64) []models.AssetHolding {
+	for i := range holdings {
+		if holdings[i].AssetId == id {
+			holdings[i].Amount = holding.Amount
+			holdings[i].IsFrozen = holding.Frozen
+			return holdings
+		}
+	}
+	return append(holdings, models.AssetHolding{
+		AssetId:        id,
+		Amount:         holding.Amount,
+		IsFrozen:       holding.Frozen,
+		OptedInAtRound: round,
+	})
+}
+
+func removeAssetHolding(holdings []models.AssetHolding, id uint64) []models.AssetHolding {
+	kept := holdings[:0]
+	for _, holding := range holdings {
+		if holding.AssetId != id {
+			kept = append(kept, holding)
+		}
+	}
+	return kept
+}
+
+func putAsset(assets []models.Asset, asset models.Asset) []models.Asset {
+	for i := range assets {
+		if assets[i].Index == asset.Index {
+			assets[i] = asset
+			return assets
+		}
+	}
+	return append(assets, asset)
+}
+
+func removeAsset(assets []models.Asset, id uint64) []models.Asset {
+	kept := assets[:0]
+	for _, asset := range assets {
+		if asset.Index != id {
+			kept = append(kept, asset)
+		}
+	}
+	return kept
+}
+
+func putAppLocalState(states []models.ApplicationLocalState, id uint64, state AppLocalState, round uint64) []models.ApplicationLocalState {
+	for i := range states {
+		if states[i].Id == id {
+			states[i].Schema = convertStateSchema(state.Schema)
+			states[i].KeyValue = convertTealKeyValues(state.KeyValue)
+			return states
+		}
+	}
+	return append(states, models.ApplicationLocalState{
+		Id:             id,
+		Schema:         convertStateSchema(state.Schema),
+		KeyValue:       convertTealKeyValues(state.KeyValue),
+		OptedInAtRound: round,
+	})
+}
+
+func removeAppLocalState(states []models.ApplicationLocalState, id uint64) []models.ApplicationLocalState {
+	kept := states[:0]
+	for _, state := range states {
+		if state.Id != id {
+			kept = append(kept, state)
+		}
+	}
+	return kept
+}
+
+func putApp(apps []models.Application, app models.Application) []models.Application {
+	for i := range apps {
+		if apps[i].Id == app.Id {
+			apps[i] = app
+			return apps
+		}
+	}
+	return append(apps, app)
+}
+
+func removeApp(apps []models.Application, id uint64) []models.Application {
+	kept := apps[:0]
+	for _, app := range apps {
+		if app.Id != id {
+			kept = append(kept, app)
+		}
+	}
+	return kept
+}
+
+// convertAssetParams converts asset parameters to the form algod serves them in
+// over its REST API.
+func convertAssetParams(creator types.Address, params types.AssetParams) models.AssetParams {
+	converted := models.AssetParams{
+		Creator:       creator.String(),
+		Total:         params.Total,
+		Decimals:      uint64(params.Decimals),
+		DefaultFrozen: params.DefaultFrozen,
+		Name:          params.AssetName,
+		NameB64:       []byte(params.AssetName),
+		UnitName:      params.UnitName,
+		UnitNameB64:   []byte(params.UnitName),
+		Url:           params.URL,
+		UrlB64:        []byte(params.URL),
+		Manager:       addressOrEmpty(params.Manager),
+		Reserve:       addressOrEmpty(params.Reserve),
+		Freeze:        addressOrEmpty(params.Freeze),
+		Clawback:      addressOrEmpty(params.Clawback),
+	}
+	if params.MetadataHash != [types.AssetMetadataHashLen]byte{} {
+		converted.MetadataHash = append([]byte(nil), params.MetadataHash[:]...)
+	}
+	return converted
+}
+
+// convertAppParams converts application parameters to the form algod serves
+// them in over its REST API.
+func convertAppParams(creator types.Address, params AppParams) models.ApplicationParams {
+	return models.ApplicationParams{
+		Creator:           creator.String(),
+		ApprovalProgram:   params.ApprovalProgram,
+		ClearStateProgram: params.ClearStateProgram,
+		ExtraProgramPages: uint64(params.ExtraProgramPages),
+		GlobalState:       convertTealKeyValues(params.GlobalState),
+		GlobalStateSchema: convertStateSchema(params.GlobalStateSchema),
+		LocalStateSchema:  convertStateSchema(params.LocalStateSchema),
+	}
+}
+
+func convertStateSchema(schema types.StateSchema) models.ApplicationStateSchema {
+	return models.ApplicationStateSchema{
+		NumUint:      schema.NumUint,
+		NumByteSlice: schema.NumByteSlice,
+	}
+}
+
+// convertTealKeyValues converts application state to a list sorted by key, with
+// keys and byte values in base64 the way algod serves them.
+func convertTealKeyValues(kv map[string]TealValue) []models.TealKeyValue {
+	if len(kv) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(kv))
+	for key := range kv {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	converted := make([]models.TealKeyValue, len(keys))
+	for i, key := range keys {
+		value := kv[key]
+		converted[i] = models.TealKeyValue{
+			Key: base64.StdEncoding.EncodeToString([]byte(key)),
+			Value: models.TealValue{
+				Type:  value.Type,
+				Bytes: base64.StdEncoding.EncodeToString([]byte(value.Bytes)),
+				Uint:  value.Uint,
+			},
+		}
+	}
+	return converted
+}
+
+// addressOrEmpty returns addr as a string, or an empty string for the zero
+// address.
+func addressOrEmpty(addr types.Address) string {
+	if addr == (types.Address{}) {
+		return ""
+	}
+	return addr.String()
+}
@@ -0,0 +1,142 @@
+package algod
+
+import (
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/types"
+)
+
+func TestLedgerStateDelta(t *testing.T) {
+	t.Log("Given the need to update accounts from the state delta of a round.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a round creates an asset and moves it between accounts.", testID)
+		{
+			var creator, holder, stale types.Address
+			creator[0], holder[0], stale[0] = 1, 2, 3
+
+			rawDelta := msgpack.Encode(map[string]interface{}{
+				"Accts": map[string]interface{}{
+					"Accts": []interface{}{
+						map[string]interface{}{"Addr": creator[:], "Status": 1, "MicroAlgos": 5000, "TotalAssetParams": 1, "TotalAssets": 1, "VoteID": make([]byte, 32)},
+						map[string]interface{}{"Addr": stale[:], "MicroAlgos": 1},
+					},
+					"AssetResources": []interface{}{
+						map[string]interface{}{
+							"Aidx": 77,
+							"Addr": creator[:],
+							"Params": map[string]interface{}{
+								"Params": map[string]interface{}{"t": 1000, "un": "TOK", "m": creator[:]},
+							},
+							"Holding": map[string]interface{}{
+								"Holding": map[string]interface{}{"a": 990},
+							},
+						},
+						map[string]interface{}{
+							"Aidx": 77,
+							"Addr": holder[:],
+							"Holding": map[string]interface{}{
+								"Holding": map[string]interface{}{"a": 10, "f": true},
+							},
+						},
+					},
+				},
+				"Creatables": map[uint64]interface{}{
+					77: map[string]interface{}{"Ctype": 0, "Created": true, "Creator": creator[:], "Ndeltas": 1},
+				},
+				"Hdr": map[string]interface{}{"rnd": 100},
+			})
+
+			delta, err := DecodeLedgerStateDelta(rawDelta)
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould decode the delta despite unknown fields : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould decode the delta despite unknown fields.", success, testID)
+
+			state := LedgerState{
+				Accounts: map[string]models.Account{
+					holder.String(): {Address: holder.String(), Amount: 300, Round: 90, CreatedAtRound: 12},
+					stale.String():  {Address: stale.String(), Amount: 7, Round: 101},
+				},
+			}
+			accounts, assets, apps := ApplyStateDelta(100, delta, state)
+
+			if len(accounts) != 2 || len(assets) != 1 || len(apps) != 0 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould change two accounts and one asset : got %d, %d, %d.", failed, testID, len(accounts), len(assets), len(apps))
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould change two accounts and one asset.", success, testID)
+
+			acct := accounts[0]
+			if acct.Address != creator.String() || acct.Amount != 5000 || acct.Status != "Online" || acct.CreatedAtRound != 100 || acct.Round != 100 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould set the balance of the creator : %+v.", failed, testID, acct)
+			}
+			if len(acct.CreatedAssets) != 1 || len(acct.Assets) != 1 || acct.Assets[0].Amount != 990 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould give the creator the asset and its holding : %+v.", failed, testID, acct)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould set the balance, asset and holding of the creator.", success, testID)
+
+			acct = accounts[1]
+			if acct.Address != holder.String() || acct.Amount != 300 || acct.CreatedAtRound != 12 || acct.Round != 100 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould keep the balance of the holder : %+v.", failed, testID, acct)
+			}
+			if len(acct.Assets) != 1 || acct.Assets[0].Amount != 10 || !acct.Assets[0].IsFrozen || acct.Assets[0].OptedInAtRound != 100 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould add the holding of the holder : %+v.", failed, testID, acct.Assets)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould add the holding of the holder.", success, testID)
+
+			asset := assets[0]
+			if asset.Index != 77 || asset.CreatedAtRound != 100 || asset.Params.Total != 1000 || asset.Params.UnitName != "TOK" ||
+				asset.Params.Creator != creator.String() || asset.Params.Manager != creator.String() || asset.Params.Reserve != "" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould convert the asset parameters : %+v.", failed, testID, asset)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould convert the asset parameters.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a round closes an account out of an asset and destroys it.", testID)
+		{
+			var creator types.Address
+			creator[0] = 1
+
+			delta := LedgerStateDelta{
+				Accts: AccountDeltas{
+					Accts: []BalanceRecord{{Addr: creator}},
+					AssetResources: []AssetResourceRecord{{
+						Aidx:    77,
+						Addr:    creator,
+						Params:  AssetParamsDelta{Deleted: true},
+						Holding: AssetHoldingDelta{Deleted: true},
+					}},
+				},
+			}
+			state := LedgerState{
+				Accounts: map[string]models.Account{
+					creator.String(): {
+						Address:       creator.String(),
+						Amount:        5000,
+						Assets:        []models.AssetHolding{{AssetId: 77}, {AssetId: 78}},
+						CreatedAssets: []models.Asset{{Index: 77}},
+						Round:         100,
+					},
+				},
+				Assets: map[uint64]models.Asset{
+					77: {Index: 77, CreatedAtRound: 100, Params: models.AssetParams{Total: 1000}},
+				},
+			}
+			accounts, assets, _ := ApplyStateDelta(110, delta, state)
+
+			acct := accounts[0]
+			if !acct.Deleted || acct.ClosedAtRound != 110 || len(acct.Assets) != 1 || acct.Assets[0].AssetId != 78 || len(acct.CreatedAssets) != 0 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould close the account and drop the asset : %+v.", failed, testID, acct)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould close the account and drop the asset.", success, testID)
+
+			if len(assets) != 1 || !assets[0].Deleted || assets[0].DestroyedAtRound != 110 || assets[0].Params.Total != 1000 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould mark the stored asset destroyed : %+v.", failed, testID, assets)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould mark the stored asset destroyed.", success, testID)
+		}
+	}
+}
@@ -75,6 +75,11 @@ func ExtractAssetIdsFromTxn(txn models.Transaction, block types.Block) []uint64
 		list = append(list, txn.AssetTransferTransaction.AssetId)
 	}
 
+	// The block's payset has already been looked at for the parent transaction.
+	for _, innerTxn := range txn.InnerTxns {
+		list = append(list, ExtractAssetIdsFromTxn(innerTxn, types.Block{})...)
+	}
+
 	return removeDuplicateUint64Values(list)
 }
 
@@ -137,6 +142,11 @@ func ExtractAccountAddrsFromTxn(txn models.Transaction) []string {
 		list = append(list, txn.PaymentTransaction.Receiver)
 	}
 
+	// Payments and transfers made by a smart contract are inner transactions.
+	for _, innerTxn := range txn.InnerTxns {
+		list = append(list, ExtractAccountAddrsFromTxn(innerTxn)...)
+	}
+
 	return removeDuplicateStrValues(list)
 }
 
@@ -162,5 +172,9 @@ func ExtractApplicationIdsFromTxn(txn models.Transaction) []uint64 {
 		list = append(list, txn.ApplicationTransaction.ForeignApps...)
 	}
 
+	for _, innerTxn := range txn.InnerTxns {
+		list = append(list, ExtractApplicationIdsFromTxn(innerTxn)...)
+	}
+
 	return removeDuplicateUint64Values(list)
 }
@@ -4,21 +4,24 @@ import (
 	"encoding/base32"
 	"encoding/base64"
 	"fmt"
-	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
-	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
 	"strconv"
 )
 
 // PrintBlockInfoFromRawBytes processes the raw block bytes and prints all the block information out
 func PrintBlockInfoFromRawBytes(rawBlock []byte) error {
 
-	var response models.BlockResponse
-	err := msgpack.Decode(rawBlock, &response)
+	response, err := DecodeBlockResponse(rawBlock)
 	if err != nil {
 		fmt.Printf("error parsing response: %s\n", err)
 		return err
 	}
 
+	extras, err := decodeTransactionExtras(rawBlock)
+	if err != nil {
+		fmt.Printf("error parsing transaction types: %s\n", err)
+		return err
+	}
+
 	var blockInfo = response.Block
 
 	fmt.Println("========================================================")
@@ -74,10 +77,11 @@ func PrintBlockInfoFromRawBytes(rawBlock []byte) error {
 	fmt.Printf("\t- Timestamp: %d\n", blockInfo.TimeStamp)
 
 	// Print Transaction
-	fmt.Println("\t- Transactions:" + string(len(blockInfo.Payset)))
+	fmt.Printf("\t- Transactions: %d\n", len(blockInfo.Payset))
 	for idx, txn := range blockInfo.Payset {
 		fmt.Printf("\t\t- Transaction %d\n", idx)
 		PrintTransactionInBlock(txn, 3)
+		PrintTransactionExtras(extras[idx], 6)
 	}
 
 	// Print Transactions Root
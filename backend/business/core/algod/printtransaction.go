@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"github.com/algorand/go-algorand-sdk/types"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
 )
 
 func printPaymentTx(txn types.Transaction, padding int) {
@@ -82,6 +83,47 @@ func printAssetFreezeTx(txn types.Transaction, padding int) {
 	fmt.Printf(paddingStr + "\t- Asset Frozen: %t\n", txn.AssetFrozen)
 }
 
+func printStateProofTx(txn txnfields.TransactionStateProof, padding int) {
+
+	var paddingStr = ""
+	for i := 0; i < padding; i++ {
+		paddingStr += "\t"
+	}
+
+	fmt.Println(paddingStr + "\t- State Proof Transaction")
+	fmt.Printf(paddingStr + "\t- State Proof Type: %d\n", txn.StateProofType)
+	fmt.Printf(paddingStr + "\t- First Attested Round: %d\n", txn.Message.FirstAttestedRound)
+	fmt.Printf(paddingStr + "\t- Last Attested Round: %d\n", txn.Message.LastAttestedRound)
+	fmt.Printf(paddingStr + "\t- Ln Proven Weight: %d\n", txn.Message.LnProvenWeight)
+	fmt.Println(paddingStr + "\t- Block Headers Commitment: " + base64.StdEncoding.EncodeToString(txn.Message.BlockHeadersCommitment))
+	fmt.Println(paddingStr + "\t- Voters Commitment: " + base64.StdEncoding.EncodeToString(txn.Message.VotersCommitment))
+	fmt.Printf(paddingStr + "\t- State Proof: %d bytes\n", len(txn.StateProof))
+}
+
+func printHeartbeatTx(txn txnfields.TransactionHeartbeat, padding int) {
+
+	var paddingStr = ""
+	for i := 0; i < padding; i++ {
+		paddingStr += "\t"
+	}
+
+	fmt.Println(paddingStr + "\t- Heartbeat Transaction")
+	fmt.Println(paddingStr + "\t- Address: " + txn.HbAddress)
+	fmt.Printf(paddingStr + "\t- Key Dilution: %d\n", txn.HbKeyDilution)
+	fmt.Println(paddingStr + "\t- Seed: " + base64.StdEncoding.EncodeToString(txn.HbSeed))
+	fmt.Println(paddingStr + "\t- Vote ID: " + base64.StdEncoding.EncodeToString(txn.HbVoteID))
+}
+
+// PrintTransactionExtras prints the fields of the transaction types the SDK doesn't know about.
+func PrintTransactionExtras(extras txnfields.TransactionExtras, padding int) {
+	if extras.StateProofTransaction != nil {
+		printStateProofTx(*extras.StateProofTransaction, padding)
+	}
+	if extras.HeartbeatTransaction != nil {
+		printHeartbeatTx(*extras.HeartbeatTransaction, padding)
+	}
+}
+
 func PrintTransactionInBlock(txn types.SignedTxnInBlock, padding int) {
 
 	var paddingStr = ""
@@ -145,6 +187,9 @@ func PrintTransactionInBlock(txn types.SignedTxnInBlock, padding int) {
 	case types.ApplicationCallTx:
 		// TODO: finish this
 		break
+	case StateProofTx, HeartbeatTx:
+		// The SDK can't decode these, see PrintTransactionExtras.
+		break
 	}
 
 	// - ApplyData
@@ -5,7 +5,6 @@ import (
 	"encoding/base64"
 	"fmt"
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
-	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
 	"github.com/kevguy/algosearch/backend/business/core/block/db"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -72,6 +71,22 @@ func (c Core) GetCurrentRoundNum(ctx context.Context) (uint64, error) {
 	return nodeStatus.LastRound, nil
 }
 
+// WaitForRoundAfter blocks until the node has seen a round after the given one, or
+// until the node's own long-poll timeout expires, and returns the node's latest round.
+func (c Core) WaitForRoundAfter(ctx context.Context, roundNum uint64) (uint64, error) {
+
+	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "algod.WaitForRoundAfter")
+	span.SetAttributes(attribute.Int64("round", int64(roundNum)))
+	defer span.End()
+
+	nodeStatus, err := c.algodClient.StatusAfterBlock(roundNum).Do(ctx)
+	if err != nil {
+		return 0, errors.Wrapf(err, "waiting for round after %d", roundNum)
+	}
+
+	return nodeStatus.LastRound, nil
+}
+
 // GetRoundInRawBytes retrieves the specified round and returns result in byte format.
 func (c Core) GetRoundInRawBytes(ctx context.Context, roundNum uint64) ([]byte, error) {
 
@@ -109,8 +124,7 @@ func ConvertBlockRawBytes(ctx context.Context, rawBlock []byte) (db.NewBlock, er
 	//fmt.Println("RAW DATA")
 	//fmt.Printf("%+v\n\n", fuck)
 
-	var response models.BlockResponse
-	err := msgpack.Decode(rawBlock, &response)
+	response, err := DecodeBlockResponse(rawBlock)
 	if err != nil {
 		return db.NewBlock{}, errors.Wrap(err, "parsing response")
 	}
@@ -180,10 +194,17 @@ func ConvertBlockRawBytes(ctx context.Context, rawBlock []byte) (db.NewBlock, er
 
 	// Process Transactions
 	newBlock.Transactions = []models.Transaction{}
-	for _, txn := range blockInfo.Payset {
+	for i, txn := range blockInfo.Payset {
 		newBlock.Transactions = append(newBlock.Transactions, ProcessTransactionInBlock(
 			txn,
-			blockInfo))
+			blockInfo,
+			uint64(i)))
+	}
+
+	// Process the transaction types the SDK doesn't know about
+	newBlock.TransactionExtras, err = decodeTransactionExtras(rawBlock)
+	if err != nil {
+		return db.NewBlock{}, errors.Wrap(err, "parsing transaction types")
 	}
 
 	// Print Transactions Root
@@ -419,13 +419,34 @@ func stateDeltaToStateDelta(d types.StateDelta) []models.EvalDeltaKeyValue {
 	return delta
 }
 
+// logsToTransactionLogs converts the logs emitted by an application call, such as
+// ARC-28 events, into the raw bytes the transaction model stores.
+func logsToTransactionLogs(logs []string) [][]byte {
+	if len(logs) == 0 {
+		return nil
+	}
+	ret := make([][]byte, len(logs))
+	for i, l := range logs {
+		ret[i] = []byte(l)
+	}
+	return ret
+}
+
 // ProcessTransactionInBlock transforms a transaction found inside a block into the desired
-// transaction model
+// transaction model. offset is the position of the transaction in the block's payset.
 // References:
 // 	https://github.com/algorand/indexer/blob/6e4d737f2e4e49088b436a234caee6681435053d/api/handlers.go
 // 	https://github.com/algorand/indexer/blob/fac3b03349d108457abc27c083bd44052590c487/importer/importer.go
 // 	https://github.com/algorand/indexer/blob/6e4d737f2e4e49088b436a234caee6681435053d/api/converter_utils.go
-func ProcessTransactionInBlock(txn types.SignedTxnInBlock, blockInfo types.Block) models.Transaction {
+func ProcessTransactionInBlock(txn types.SignedTxnInBlock, blockInfo types.Block, offset uint64) models.Transaction {
+	return processSignedTxnWithAD(txn.SignedTxnWithAD, blockInfo, offset, false)
+}
+
+// processSignedTxnWithAD transforms a signed transaction and its apply data into the
+// desired transaction model. The inner transactions issued by an application call are
+// transformed recursively. Inner transactions have no ID of their own, they are
+// identified by their parent transaction and their position in it, and share its offset.
+func processSignedTxnWithAD(txn types.SignedTxnWithAD, blockInfo types.Block, offset uint64, inner bool) models.Transaction {
 
 	//var genesisHash = [32]byte(txn.Txn.GenesisHash)
 	//var genesisHashStr = base64.StdEncoding.EncodeToString(genesisHash[:])
@@ -442,30 +463,32 @@ func ProcessTransactionInBlock(txn types.SignedTxnInBlock, blockInfo types.Block
 
 	switch txn.Txn.Type {
 	case types.PaymentTx:
-		paymentTx := extractPaymentTx(txn.SignedTxnWithAD)
+		paymentTx := extractPaymentTx(txn)
 		payment = &paymentTx
 	case types.KeyRegistrationTx:
-		keyRegTx := extractKeyRegistrationTx(txn.SignedTxnWithAD)
+		keyRegTx := extractKeyRegistrationTx(txn)
 		keyreg = &keyRegTx
 	case types.AssetConfigTx:
-		assetConfigTx := extractAssetConfigTx(txn.SignedTxnWithAD)
+		assetConfigTx := extractAssetConfigTx(txn)
 		assetConfig = &assetConfigTx
 	case types.AssetTransferTx:
-		assetTransferTx := extractAssetTransferTx(txn.SignedTxnWithAD)
+		assetTransferTx := extractAssetTransferTx(txn)
 		assetTransfer = &assetTransferTx
 	case types.AssetFreezeTx:
-		assetFreezeTx := extractAssetFreezeTx(txn.SignedTxnWithAD)
+		assetFreezeTx := extractAssetFreezeTx(txn)
 		assetFreeze = &assetFreezeTx
 	case types.ApplicationCallTx:
-		applicationTx := extractApplicationTx(txn.SignedTxnWithAD)
+		applicationTx := extractApplicationTx(txn)
 		application = &applicationTx
+	case StateProofTx, HeartbeatTx:
+		// The SDK can't decode these, see decodeTransactionExtras.
 	}
 
 	sig := models.TransactionSignature{}
 
-	logicSig := lsigToTransactionLsig(txn.SignedTxnWithAD.SignedTxn.Lsig)
-	multiSig := msigToTransactionMsig(txn.SignedTxnWithAD.SignedTxn.Msig)
-	sigsig := sigToTransactionSig(txn.SignedTxnWithAD.Sig)
+	logicSig := lsigToTransactionLsig(txn.SignedTxn.Lsig)
+	multiSig := msigToTransactionMsig(txn.SignedTxn.Msig)
+	sigsig := sigToTransactionSig(txn.Sig)
 
 	if logicSig != nil {
 		sig.Logicsig = *logicSig
@@ -534,9 +557,7 @@ func ProcessTransactionInBlock(txn types.SignedTxnInBlock, blockInfo types.Block
 
 		ClosingAmount:  uint64(txn.ClosingAmount),
 		ConfirmedRound: uint64(blockInfo.Round),
-		// TODO: ask Algorand people on how to support this
-		// I can't find it anywhere.
-		IntraRoundOffset: 0.,
+		IntraRoundOffset: offset,
 		// TODO: ask Algorand to verify if it's really this one
 		RoundTime:  uint64(blockInfo.TimeStamp),
 		Fee:        uint64(txn.Txn.Fee),
@@ -558,12 +579,16 @@ func ProcessTransactionInBlock(txn types.SignedTxnInBlock, blockInfo types.Block
 		SenderRewards:   uint64(txn.SenderRewards),
 		Type:            string(txn.Txn.Type),
 		Signature:       sig,
-		Id:              crypto.TransactionIDString(txn.Txn),
 		// TODO
 		RekeyTo:          txn.Txn.RekeyTo.String(),
 		GlobalStateDelta: stateDeltaToStateDelta(txn.EvalDelta.GlobalDelta),
 		LocalStateDelta:  localStateDelta,
 		AuthAddr:         txn.AuthAddr.String(),
+		Logs:             logsToTransactionLogs(txn.EvalDelta.Logs),
+	}
+
+	if !inner {
+		transaction.Id = crypto.TransactionIDString(txn.Txn)
 	}
 
 	switch txn.Txn.Type {
@@ -593,5 +618,18 @@ func ProcessTransactionInBlock(txn types.SignedTxnInBlock, blockInfo types.Block
 		}
 	}
 
+	// The IDs of assets and applications created by inner transactions only
+	// exist in the apply data.
+	if txn.ApplyData.ConfigAsset != 0 {
+		transaction.CreatedAssetIndex = txn.ApplyData.ConfigAsset
+	}
+	if txn.ApplyData.ApplicationID != 0 {
+		transaction.CreatedApplicationIndex = txn.ApplyData.ApplicationID
+	}
+
+	for _, innerTxn := range txn.ApplyData.EvalDelta.InnerTxns {
+		transaction.InnerTxns = append(transaction.InnerTxns, processSignedTxnWithAD(innerTxn, blockInfo, offset, true))
+	}
+
 	return transaction
 }
@@ -0,0 +1,84 @@
+package algod
+
+import (
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/types"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestInnerTransactions(t *testing.T) {
+	t.Log("Given the need to convert transactions issued by smart contracts.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen an app call at offset 3 logs an event and issues a payment that issues an asset transfer.", testID)
+		{
+			var sender, receiver, assetReceiver types.Address
+			sender[0], receiver[0], assetReceiver[0] = 1, 2, 3
+
+			transfer := types.SignedTxnWithAD{}
+			transfer.Txn.Type = types.AssetTransferTx
+			transfer.Txn.XferAsset = 42
+			transfer.Txn.AssetReceiver = assetReceiver
+
+			payment := types.SignedTxnWithAD{}
+			payment.Txn.Type = types.PaymentTx
+			payment.Txn.Receiver = receiver
+			payment.EvalDelta.InnerTxns = []types.SignedTxnWithAD{transfer}
+
+			appCall := types.SignedTxnInBlock{}
+			appCall.Txn.Type = types.ApplicationCallTx
+			appCall.Txn.Sender = sender
+			appCall.Txn.ApplicationID = 7
+			appCall.EvalDelta.InnerTxns = []types.SignedTxnWithAD{payment}
+			appCall.EvalDelta.Logs = []string{"\x01event"}
+
+			txn := ProcessTransactionInBlock(appCall, types.Block{}, 3)
+
+			if len(txn.InnerTxns) != 1 || len(txn.InnerTxns[0].InnerTxns) != 1 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould convert inner transactions recursively : %+v.", failed, testID, txn.InnerTxns)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould convert inner transactions recursively.", success, testID)
+
+			if txn.Id == "" || txn.InnerTxns[0].Id != "" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould only give the top-level transaction an ID : %q, %q.", failed, testID, txn.Id, txn.InnerTxns[0].Id)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould only give the top-level transaction an ID.", success, testID)
+
+			if txn.IntraRoundOffset != 3 || txn.InnerTxns[0].IntraRoundOffset != 3 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould keep the offset of the transaction in the round : got %d, %d.", failed, testID, txn.IntraRoundOffset, txn.InnerTxns[0].IntraRoundOffset)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould keep the offset of the transaction in the round.", success, testID)
+
+			if len(txn.Logs) != 1 || string(txn.Logs[0]) != "\x01event" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould keep the logs of the application call : got %q.", failed, testID, txn.Logs)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould keep the logs of the application call.", success, testID)
+
+			accts := ExtractAccountAddrsFromTxn(txn)
+			for _, want := range []types.Address{receiver, assetReceiver} {
+				var found bool
+				for _, acct := range accts {
+					if acct == want.String() {
+						found = true
+					}
+				}
+				if !found {
+					t.Fatalf("\t\t%s\tTest %d:\tShould associate account %s : got %v.", failed, testID, want, accts)
+				}
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould associate the accounts of inner transactions.", success, testID)
+
+			assets := ExtractAssetIdsFromTxn(txn, types.Block{})
+			if len(assets) != 1 || assets[0] != 42 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould associate the assets of inner transactions : got %v.", failed, testID, assets)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould associate the assets of inner transactions.", success, testID)
+		}
+	}
+}
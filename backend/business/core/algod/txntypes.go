@@ -0,0 +1,142 @@
+package algod
+
+import (
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/types"
+	"github.com/algorand/go-codec/codec"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
+)
+
+// Transaction types introduced after the SDK's transaction model.
+const (
+	StateProofTx types.TxType = "stpf"
+	HeartbeatTx  types.TxType = "hb"
+)
+
+// TxnTypes lists every transaction type the explorer understands.
+var TxnTypes = []types.TxType{
+	types.PaymentTx,
+	types.KeyRegistrationTx,
+	types.AssetConfigTx,
+	types.AssetTransferTx,
+	types.AssetFreezeTx,
+	types.ApplicationCallTx,
+	StateProofTx,
+	HeartbeatTx,
+}
+
+// IsKnownTxnType reports whether txnType is one of TxnTypes.
+func IsKnownTxnType(txnType string) bool {
+	for _, t := range TxnTypes {
+		if string(t) == txnType {
+			return true
+		}
+	}
+	return false
+}
+
+// rawBlockExtras mirrors the parts of a block response holding the fields of
+// transaction types the SDK can't decode.
+type rawBlockExtras struct {
+	Block struct {
+		Payset []struct {
+			Txn rawTxnExtras `codec:"txn"`
+		} `codec:"txns"`
+	} `codec:"block"`
+}
+
+type rawTxnExtras struct {
+	Type types.TxType `codec:"type"`
+
+	// stpf
+	StateProofType uint64    `codec:"sptype"`
+	StateProof     codec.Raw `codec:"sp"`
+	Message        struct {
+		BlockHeadersCommitment []byte `codec:"b"`
+		VotersCommitment       []byte `codec:"v"`
+		LnProvenWeight         uint64 `codec:"P"`
+		FirstAttestedRound     uint64 `codec:"f"`
+		LastAttestedRound      uint64 `codec:"l"`
+	} `codec:"spmsg"`
+
+	// hb
+	Heartbeat struct {
+		Address     types.Address `codec:"a"`
+		Proof       codec.Raw     `codec:"prf"`
+		Seed        []byte        `codec:"sd"`
+		VoteID      []byte        `codec:"vid"`
+		KeyDilution uint64        `codec:"kd"`
+	} `codec:"hb"`
+}
+
+// decodeLenient decodes msgpack encoded data, ignoring the fields the target
+// doesn't know about so newer protocol fields don't make decoding fail.
+func decodeLenient(b []byte, objptr interface{}) error {
+	return codec.NewDecoderBytes(b, msgpack.LenientCodecHandle).Decode(objptr)
+}
+
+// DecodeBlockResponse decodes a block retrieved in msgpack format from algod.
+func DecodeBlockResponse(rawBlock []byte) (models.BlockResponse, error) {
+	var response models.BlockResponse
+	if err := decodeLenient(rawBlock, &response); err != nil {
+		return models.BlockResponse{}, err
+	}
+	return response, nil
+}
+
+// decodeTransactionExtras decodes the fields of the newer transaction types found in
+// the block's payset. The result is indexed like the payset.
+func decodeTransactionExtras(rawBlock []byte) ([]txnfields.TransactionExtras, error) {
+	var raw rawBlockExtras
+	if err := decodeLenient(rawBlock, &raw); err != nil {
+		return nil, err
+	}
+
+	extras := make([]txnfields.TransactionExtras, len(raw.Block.Payset))
+	for i, stxn := range raw.Block.Payset {
+		txn := stxn.Txn
+		switch txn.Type {
+		case StateProofTx:
+			extras[i].StateProofTransaction = &txnfields.TransactionStateProof{
+				StateProofType: txn.StateProofType,
+				StateProof:     txn.StateProof,
+				Message: txnfields.StateProofMessage{
+					BlockHeadersCommitment: txn.Message.BlockHeadersCommitment,
+					VotersCommitment:       txn.Message.VotersCommitment,
+					LnProvenWeight:         txn.Message.LnProvenWeight,
+					FirstAttestedRound:     txn.Message.FirstAttestedRound,
+					LastAttestedRound:      txn.Message.LastAttestedRound,
+				},
+			}
+		case HeartbeatTx:
+			extras[i].HeartbeatTransaction = &txnfields.TransactionHeartbeat{
+				HbAddress:     txn.Heartbeat.Address.String(),
+				HbKeyDilution: txn.Heartbeat.KeyDilution,
+				HbSeed:        txn.Heartbeat.Seed,
+				HbVoteID:      txn.Heartbeat.VoteID,
+				HbProof:       txn.Heartbeat.Proof,
+			}
+		}
+	}
+
+	return extras, nil
+}
+
+// ExtractAccountAddrsFromExtras returns the accounts referenced by the fields of
+// the newer transaction types.
+func ExtractAccountAddrsFromExtras(extras txnfields.TransactionExtras) []string {
+	var list []string
+
+	if extras.HeartbeatTransaction != nil && extras.HeartbeatTransaction.HbAddress != "" {
+		list = append(list, extras.HeartbeatTransaction.HbAddress)
+	}
+
+	return list
+}
+
+// ExtractAccountAddrs returns the accounts referenced by a transaction and by
+// the fields of the newer transaction types, each listed once.
+func ExtractAccountAddrs(txn models.Transaction, extras txnfields.TransactionExtras) []string {
+	return removeDuplicateStrValues(append(ExtractAccountAddrsFromTxn(txn), ExtractAccountAddrsFromExtras(extras)...))
+}
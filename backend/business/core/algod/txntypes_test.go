@@ -0,0 +1,81 @@
+package algod
+
+import (
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/types"
+)
+
+func TestTransactionExtras(t *testing.T) {
+	t.Log("Given the need to decode transaction types the SDK doesn't know about.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a block holds a payment, a state proof and a heartbeat.", testID)
+		{
+			var hbAddr types.Address
+			hbAddr[0] = 9
+
+			rawBlock := msgpack.Encode(map[string]interface{}{
+				"block": map[string]interface{}{
+					"rnd": 512,
+					"txns": []interface{}{
+						map[string]interface{}{
+							"txn": map[string]interface{}{"type": "pay", "amt": 1},
+						},
+						map[string]interface{}{
+							"txn": map[string]interface{}{
+								"type":   "stpf",
+								"sptype": 0,
+								"sp":     map[string]interface{}{"c": []byte{1, 2}},
+								"spmsg":  map[string]interface{}{"f": 257, "l": 512, "P": 7, "b": []byte{3}},
+							},
+						},
+						map[string]interface{}{
+							"txn": map[string]interface{}{
+								"type": "hb",
+								"hb":   map[string]interface{}{"a": hbAddr[:], "kd": 100, "sd": []byte{4}},
+							},
+						},
+					},
+				},
+			})
+
+			response, err := DecodeBlockResponse(rawBlock)
+			if err != nil || len(response.Block.Payset) != 3 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould decode the block despite unknown fields : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould decode the block despite unknown fields.", success, testID)
+
+			extras, err := decodeTransactionExtras(rawBlock)
+			if err != nil || len(extras) != 3 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould decode the fields of every transaction : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould decode the fields of every transaction.", success, testID)
+
+			if extras[0].StateProofTransaction != nil || extras[0].HeartbeatTransaction != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould leave the payment alone : %+v.", failed, testID, extras[0])
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould leave the payment alone.", success, testID)
+
+			sp := extras[1].StateProofTransaction
+			if sp == nil || sp.Message.FirstAttestedRound != 257 || sp.Message.LastAttestedRound != 512 || len(sp.StateProof) == 0 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould decode the state proof : %+v.", failed, testID, sp)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould decode the state proof.", success, testID)
+
+			hb := extras[2].HeartbeatTransaction
+			if hb == nil || hb.HbAddress != hbAddr.String() || hb.HbKeyDilution != 100 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould decode the heartbeat : %+v.", failed, testID, hb)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould decode the heartbeat.", success, testID)
+
+			accts := ExtractAccountAddrs(models.Transaction{Type: "hb", Sender: hbAddr.String()}, extras[2])
+			if len(accts) != 1 || accts[0] != hbAddr.String() {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list the heartbeat address once when it sends the heartbeat : %v.", failed, testID, accts)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould list the heartbeat address once when it sends the heartbeat.", success, testID)
+		}
+	}
+}
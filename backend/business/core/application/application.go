@@ -10,9 +10,22 @@ import (
 	"go.uber.org/zap"
 )
 
+// Storer declares the behavior the application core needs from a storage backend.
+// The CouchDB store in the db package is the default one.
+type Storer interface {
+	AddApplication(ctx context.Context, application models.Application) (string, string, error)
+	AddApplications(ctx context.Context, applications []models.Application) (bool, error)
+	GetApplication(ctx context.Context, applicationID string) (models.Application, error)
+	GetEarliestApplicationID(ctx context.Context) (string, error)
+	GetLatestApplicationID(ctx context.Context) (string, error)
+	GetApplicationCount(ctx context.Context) (int64, error)
+	GetApplicationCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error)
+	GetApplicationsPagination(ctx context.Context, latestApplicationID string, order string, pageNo, limit int64) ([]db.Application, int64, int64, error)
+}
+
 // Core manages the set of API's for application access.
 type Core struct {
-	store db.Store
+	store Storer
 }
 
 // NewCore constructs a core for application api access.
@@ -22,6 +35,14 @@ func NewCore(log *zap.SugaredLogger, couchClient *kivik.Client, dbName string) C
 	}
 }
 
+// NewCoreWithStore constructs a core for application api access backed by the given
+// store.
+func NewCoreWithStore(store Storer) Core {
+	return Core{
+		store: store,
+	}
+}
+
 func (c Core) AddApplication(ctx context.Context, application models.Application) (string, string, error) {
 	return c.store.AddApplication(ctx, application)
 }
@@ -42,6 +63,12 @@ func (c Core) GetLatestApplicationID(ctx context.Context) (string, error) {
 	return c.store.GetLatestApplicationID(ctx)
 }
 
+// GetApplicationCount retrieves the number of applications, which is 0 when
+// there are none.
+func (c Core) GetApplicationCount(ctx context.Context) (int64, error) {
+	return c.store.GetApplicationCount(ctx)
+}
+
 func (c Core) GetApplicationCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
 	return c.store.GetApplicationCountBtnKeys(ctx, startKey, endKey)
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
 	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	"github.com/kevguy/algosearch/backend/foundation/couchdb"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -50,7 +52,7 @@ func (s Store) AddApplication(ctx context.Context, application models.Applicatio
 		Application: application,
 		DocType:     DocType,
 	}
-	//docID := fmt.Sprintf("%s.%s", DocType, doc.Id)
+	//docID := fmt.Sprintf("%s.%d", DocType, doc.Id)
 	exist, err := s.couchClient.DBExists(ctx, s.dbName)
 	if err != nil || !exist {
 		return "", "", errors.Wrap(err, s.dbName+ " database check fails")
@@ -58,7 +60,7 @@ func (s Store) AddApplication(ctx context.Context, application models.Applicatio
 	db := s.couchClient.DB(s.dbName)
 
 	docID := strconv.FormatUint(doc.Id, 10)
-	rev, err := db.Put(ctx, docID, doc)
+	rev, err := couchdb.Upsert(ctx, db, docID, doc)
 	if err != nil {
 		return "", "", errors.Wrap(err, s.dbName+ " database can't insert application id " +docID)
 	}
@@ -83,23 +85,19 @@ func (s Store) AddApplications(ctx context.Context, applications []models.Applic
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	applications_ := make([]interface{}, len(applications))
-	//fmt.Println("Here are teh applications")
-	//fmt.Printf("%v\n", applications)
-
-	// https://stackoverflow.com/questions/55755929/go-convert-interface-to-map
-	// https://stackoverflow.com/questions/44094325/add-data-to-interface-in-struct
+	ids := make([]string, len(applications))
 	for i := range applications {
-		docID := strconv.FormatUint(applications[i].Id, 10)
-		doc := NewApplication{
-			ID:          &docID,
+		ids[i] = strconv.FormatUint(applications[i].Id, 10)
+	}
+
+	err = couchdb.BulkUpsert(ctx, db, ids, func(i int, rev string) interface{} {
+		return NewApplication{
+			ID:          &ids[i],
+			Rev:         rev,
 			Application: applications[i],
 			DocType:     DocType,
 		}
-		applications_[i] = doc
-	}
-
-	_, err = db.BulkDocs(ctx, applications_)
+	})
 	if err != nil {
 		return false, errors.Wrap(err, "Can't bulk insert the applications")
 	}
@@ -124,7 +122,7 @@ func (s Store) GetApplication(ctx context.Context, applicationID string) (models
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	docID := fmt.Sprintf("%s.%s", DocType, applicationID)
+	docID := applicationID
 	row := db.Get(ctx, docID)
 	if row == nil {
 		return models.Application{}, errors.Wrap(err, s.dbName+ " get data empty")
@@ -156,7 +154,7 @@ func (s Store) GetEarliestApplicationID(ctx context.Context) (string, error) {
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	rows, err := db.Query(ctx, schema.BlockDDoc, "_view/" +schema.ApplicationViewByIDInLatest, kivik.Options{
+	rows, err := db.Query(ctx, schema.ApplicationDDoc, "_view/" +schema.ApplicationViewByIDInLatest, kivik.Options{
 		"include_docs": true,
 		"descending": false,
 		"limit": 1,
@@ -176,8 +174,7 @@ func (s Store) GetEarliestApplicationID(ctx context.Context) (string, error) {
 		return "", errors.Wrap(err, "Can't find anything")
 	}
 
-	docID := fmt.Sprintf("%s.%s", DocType, doc.Id)
-	return docID, nil
+	return strconv.FormatUint(doc.Id, 10), nil
 }
 
 func (s Store) GetLatestApplicationID(ctx context.Context) (string, error) {
@@ -196,7 +193,7 @@ func (s Store) GetLatestApplicationID(ctx context.Context) (string, error) {
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	rows, err := db.Query(ctx, schema.BlockDDoc, "_view/" +schema.ApplicationViewByIDInLatest, kivik.Options{
+	rows, err := db.Query(ctx, schema.ApplicationDDoc, "_view/" +schema.ApplicationViewByIDInLatest, kivik.Options{
 		"include_docs": true,
 		"descending": true,
 		"limit": 1,
@@ -216,8 +213,46 @@ func (s Store) GetLatestApplicationID(ctx context.Context) (string, error) {
 		return "", errors.Wrap(err, "Can't find anything")
 	}
 
-	docID := fmt.Sprintf("%s.%s", DocType, doc.Id)
-	return docID, nil
+	return strconv.FormatUint(doc.Id, 10), nil
+}
+
+// GetApplicationCount retrieves the number of applications. The count view has
+// no rows when there are no applications, which counts as 0.
+func (s Store) GetApplicationCount(ctx context.Context) (int64, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "application.GetApplicationCount")
+	defer span.End()
+
+	s.log.Infow("application.GetApplicationCount", "traceid", web.GetTraceID(ctx))
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return 0, errors.Wrap(err, s.dbName+" database check fails")
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	rows, err := db.Query(ctx, schema.ApplicationDDoc, "_view/"+schema.ApplicationViewByIDInCount, kivik.Options{
+		"reduce":      true,
+		"group_level": 0,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "Fetch data error")
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		if err := rows.ScanValue(&count); err != nil {
+			return 0, errors.Wrap(err, "Can't find anything")
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, errors.Wrap(err, "rows error")
+	}
+
+	return count, nil
 }
 
 // GetApplicationCountBtnKeys retrieves the number of keys between two keys.
@@ -244,34 +279,39 @@ func (s Store) GetApplicationCountBtnKeys(ctx context.Context, startKey, endKey
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	rows, err := db.Query(ctx, schema.BlockDDoc, "_view/" +schema.ApplicationViewByIDInCount, kivik.Options{
-		"start_key": startKey,
-		"end_key": endKey,
-	})
+	first, err := store.ParseID(startKey)
 	if err != nil {
-		return 0, errors.Wrap(err, "Fetch data error")
+		return 0, err
+	}
+	last, err := store.ParseID(endKey)
+	if err != nil {
+		return 0, err
 	}
 
-	type Payload struct {
-		Key *string `json:"key"`
-		Value int64 `json:"value"`
+	rows, err := db.Query(ctx, schema.ApplicationDDoc, "_view/" +schema.ApplicationViewByIDInCount, kivik.Options{
+		"start_key": first,
+		"end_key": last,
+		"inclusive_end": true,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "Fetch data error")
 	}
 
-	var payload Payload
+	var count int64
 	for rows.Next() {
-		if err := rows.ScanDoc(&payload); err != nil {
+		if err := rows.ScanValue(&count); err != nil {
 			return 0, errors.Wrap(err, "Can't find anything")
 		}
 	}
 
-	return payload.Value, nil
+	return count, nil
 }
 
 func (s Store) GetApplicationsPagination(ctx context.Context, latestApplicationID string, order string, pageNo, limit int64) ([]Application, int64, int64, error) {
 
 	ctx, span := otel.GetTracerProvider().
 		Tracer("").
-		Start(ctx, "block.GetBlocksPagination")
+		Start(ctx, "application.GetApplicationsPagination")
 	span.SetAttributes(attribute.String("latestApplicationID", latestApplicationID))
 	span.SetAttributes(attribute.Int64("pageNo", pageNo))
 	span.SetAttributes(attribute.Int64("limit", limit))
@@ -283,13 +323,18 @@ func (s Store) GetApplicationsPagination(ctx context.Context, latestApplicationI
 		"pageNo", pageNo,
 		"limit", limit)
 
+	latestKey, err := store.ParseID(latestApplicationID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
 	// Get the earliest application id
-	earliestTxnID, err := s.GetEarliestApplicationID(ctx)
+	earliestApplicationID, err := s.GetEarliestApplicationID(ctx)
 	if err != nil {
 		return nil, 0, 0, errors.Wrap(err, ": Get earliest synced application id")
 	}
 
-	numOfApplications, err := s.GetApplicationCountBtnKeys(ctx, earliestTxnID, latestApplicationID)
+	numOfApplications, err := s.GetApplicationCountBtnKeys(ctx, earliestApplicationID, latestApplicationID)
 	if err != nil {
 		return nil, 0, 0, errors.Wrap(err, ": Get application count between keys")
 	}
@@ -316,7 +361,7 @@ func (s Store) GetApplicationsPagination(ctx context.Context, latestApplicationI
 		options["descending"] = true
 
 		// Start with latest block number
-		options["start_key"] = latestApplicationID
+		options["start_key"] = latestKey
 
 		// Use page number to calculate number of items to skip
 		skip := (pageNo - 1) * limit
@@ -336,7 +381,7 @@ func (s Store) GetApplicationsPagination(ctx context.Context, latestApplicationI
 		skip := (pageNo - 1) * limit
 		options["skip"] = skip
 
-		if (numOfApplications - skip) > limit {
+		if (numOfApplications - skip) < limit {
 			options["limit"] =  numOfApplications - skip
 		} else {
 			options["limit"] = limit
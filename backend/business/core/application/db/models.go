@@ -4,6 +4,7 @@ import "github.com/algorand/go-algorand-sdk/client/v2/common/models"
 
 type NewApplication struct {
 	ID *string `json:"_id"`
+	Rev string `json:"_rev,omitempty"`
 	models.Application
 	DocType string	`json:"doc_type"`
 }
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"encoding/base64"
+	"sort"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+	"github.com/kevguy/algosearch/backend/business/core/algod"
+)
+
+// Types and encodings of the values of a decoded state.
+const (
+	StateTypeBytes = "bytes"
+	StateTypeUint  = "uint"
+
+	EncodingText    = "text"
+	EncodingAddress = "address"
+	EncodingBase64  = "base64"
+)
+
+// StateValue is a key of the state of an application along with its value,
+// decoded from the base64 the algod API hands them in.
+//
+// Keys are shown as text when they are printable and left in base64
+// otherwise. Uint values are numbers. Bytes values are shown as text when
+// printable, as an address when 32 bytes long, and left in base64 otherwise;
+// Encoding tells which.
+type StateValue struct {
+	Key         string      `json:"key"`
+	KeyEncoding string      `json:"key_encoding"`
+	Type        string      `json:"type"`
+	Value       interface{} `json:"value"`
+	Encoding    string      `json:"encoding,omitempty"`
+}
+
+// DecodeState decodes the key-value pairs of the global or local state of an
+// application, sorted by key.
+func DecodeState(state []models.TealKeyValue) []StateValue {
+	decoded := make([]StateValue, 0, len(state))
+	for _, kv := range state {
+		key, keyEncoding := decodeBytes(kv.Key, false)
+
+		sv := StateValue{
+			Key:         key,
+			KeyEncoding: keyEncoding,
+		}
+		switch kv.Value.Type {
+		case 2:
+			sv.Type = StateTypeUint
+			sv.Value = kv.Value.Uint
+		default:
+			sv.Type = StateTypeBytes
+			sv.Value, sv.Encoding = decodeBytes(kv.Value.Bytes, true)
+		}
+		decoded = append(decoded, sv)
+	}
+
+	sort.SliceStable(decoded, func(i, j int) bool {
+		return decoded[i].Key < decoded[j].Key
+	})
+	return decoded
+}
+
+// decodeBytes decodes b64 into text when it is printable, or into an address
+// when it is 32 bytes long and asAddress is set. Otherwise b64 is returned as
+// is.
+func decodeBytes(b64 string, asAddress bool) (string, string) {
+	raw, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return b64, EncodingBase64
+	}
+
+	if len(raw) > 0 && algod.PrintableUTF8OrEmpty(string(raw)) != "" {
+		return string(raw), EncodingText
+	}
+	if asAddress && len(raw) == len(types.Address{}) {
+		if addr, err := types.EncodeAddress(raw); err == nil {
+			return addr, EncodingAddress
+		}
+	}
+	return b64, EncodingBase64
+}
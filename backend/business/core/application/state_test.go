@@ -0,0 +1,54 @@
+package application
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestDecodeState(t *testing.T) {
+	b64 := base64.StdEncoding.EncodeToString
+
+	var creator types.Address
+	creator[0] = 7
+
+	state := []models.TealKeyValue{
+		{Key: b64([]byte("total")), Value: models.TealValue{Type: 2, Uint: 42}},
+		{Key: b64([]byte("creator")), Value: models.TealValue{Type: 1, Bytes: b64(creator[:])}},
+		{Key: b64([]byte("name")), Value: models.TealValue{Type: 1, Bytes: b64([]byte("algosearch"))}},
+		{Key: b64([]byte{0xff, 0x01}), Value: models.TealValue{Type: 1, Bytes: b64([]byte{0x00, 0x02})}},
+	}
+
+	want := []StateValue{
+		{Key: "/wE=", KeyEncoding: EncodingBase64, Type: StateTypeBytes, Value: "AAI=", Encoding: EncodingBase64},
+		{Key: "creator", KeyEncoding: EncodingText, Type: StateTypeBytes, Value: creator.String(), Encoding: EncodingAddress},
+		{Key: "name", KeyEncoding: EncodingText, Type: StateTypeBytes, Value: "algosearch", Encoding: EncodingText},
+		{Key: "total", KeyEncoding: EncodingText, Type: StateTypeUint, Value: uint64(42)},
+	}
+
+	t.Log("Given the need to show the global state of an application decoded.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the state holds text, an address, raw bytes and a uint.", testID)
+		{
+			got := DecodeState(state)
+			if len(got) != len(want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould decode every key : got %d.", failed, testID, len(got))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("\t\t%s\tTest %d:\tShould decode key %d as %+v : got %+v.", failed, testID, i, want[i], got[i])
+				}
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould decode every key, sorted.", success, testID)
+		}
+	}
+}
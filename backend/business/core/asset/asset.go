@@ -10,9 +10,22 @@ import (
 	"go.uber.org/zap"
 )
 
+// Storer declares the behavior the asset core needs from a storage backend.
+// The CouchDB store in the db package is the default one.
+type Storer interface {
+	AddAsset(ctx context.Context, asset models.Asset) (string, string, error)
+	AddAssets(ctx context.Context, assets []models.Asset) (bool, error)
+	GetAsset(ctx context.Context, assetID string) (models.Asset, error)
+	GetEarliestAssetID(ctx context.Context) (string, error)
+	GetLatestAssetID(ctx context.Context) (string, error)
+	GetAssetCount(ctx context.Context) (int64, error)
+	GetAssetCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error)
+	GetAssetsPagination(ctx context.Context, latestAssetID string, order string, pageNo, limit int64) ([]db.Asset, int64, int64, error)
+}
+
 // Core manages the set of API's for block access.
 type Core struct {
-	store db.Store
+	store Storer
 }
 
 // NewCore constructs a core for product api access.
@@ -22,6 +35,14 @@ func NewCore(log *zap.SugaredLogger, couchClient *kivik.Client, dbName string) C
 	}
 }
 
+// NewCoreWithStore constructs a core for asset api access backed by the given
+// store.
+func NewCoreWithStore(store Storer) Core {
+	return Core{
+		store: store,
+	}
+}
+
 func (c Core) AddAsset(ctx context.Context, asset models.Asset) (string, string, error) {
 	return c.store.AddAsset(ctx, asset)
 }
@@ -42,6 +63,11 @@ func (c Core) GetLatestAssetID(ctx context.Context) (string, error) {
 	return c.store.GetLatestAssetID(ctx)
 }
 
+// GetAssetCount retrieves the number of assets, which is 0 when there are none.
+func (c Core) GetAssetCount(ctx context.Context) (int64, error) {
+	return c.store.GetAssetCount(ctx)
+}
+
 func (c Core) GetAssetCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
 	return c.store.GetAssetCountBtnKeys(ctx, startKey, endKey)
 }
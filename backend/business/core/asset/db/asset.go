@@ -3,10 +3,11 @@ package db
 
 import (
 	"context"
-	"fmt"
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
 	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	"github.com/kevguy/algosearch/backend/foundation/couchdb"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -58,7 +59,7 @@ func (s Store) AddAsset(ctx context.Context, asset models.Asset) (string, string
 	db := s.couchClient.DB(s.dbName)
 
 	docID := strconv.FormatUint(doc.Index, 10)
-	rev, err := db.Put(ctx, docID, doc)
+	rev, err := couchdb.Upsert(ctx, db, docID, doc)
 	if err != nil {
 		return "", "", errors.Wrap(err, s.dbName+ " database can't insert asset id " +docID)
 	}
@@ -83,32 +84,19 @@ func (s Store) AddAssets(ctx context.Context, assets []models.Asset) (bool, erro
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	assets_ := make([]interface{}, len(assets))
-	//fmt.Println("Here are teh assets")
-	//fmt.Printf("%v\n", assets)
-
-	// https://stackoverflow.com/questions/55755929/go-convert-interface-to-map
-	// https://stackoverflow.com/questions/44094325/add-data-to-interface-in-struct
+	ids := make([]string, len(assets))
 	for i := range assets {
-		docID := strconv.FormatUint(assets[i].Index, 10)
-		doc := NewAsset{
-			ID:      &docID,
+		ids[i] = strconv.FormatUint(assets[i].Index, 10)
+	}
+
+	err = couchdb.BulkUpsert(ctx, db, ids, func(i int, rev string) interface{} {
+		return NewAsset{
+			ID:      &ids[i],
+			Rev:     rev,
 			Asset:   assets[i],
 			DocType: DocType,
 		}
-		assets_[i] = doc
-		//fmt.Println("YYYYYYYYYY")
-		//fmt.Printf("%v\n", assets_[i])
-		//v, _ := assets_[i].(map[string]interface{})
-		//fmt.Println("VVVVVVVV")
-		//fmt.Printf("%v\n", v)
-		//v["_id"] = assets[i].Id
-		//assets_[i] = v
-		//fmt.Println("looping")
-		//fmt.Println(assets_[i])
-	}
-
-	_, err = db.BulkDocs(ctx, assets_)
+	})
 	if err != nil {
 		return false, errors.Wrap(err, "Can't bulk insert the assets")
 	}
@@ -133,7 +121,7 @@ func (s Store) GetAsset(ctx context.Context, assetID string) (models.Asset, erro
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	docID := fmt.Sprintf("%s.%s", DocType, assetID)
+	docID := assetID
 	row := db.Get(ctx, docID)
 	if row == nil {
 		return models.Asset{}, errors.Wrap(err, s.dbName+ " get data empty")
@@ -165,7 +153,7 @@ func (s Store) GetEarliestAssetID(ctx context.Context) (string, error) {
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	rows, err := db.Query(ctx, schema.BlockDDoc, "_view/" +schema.AssetViewByIDInLatest, kivik.Options{
+	rows, err := db.Query(ctx, schema.AssetDDoc, "_view/" +schema.AssetViewByIDInLatest, kivik.Options{
 		"include_docs": true,
 		"descending": false,
 		"limit": 1,
@@ -185,8 +173,7 @@ func (s Store) GetEarliestAssetID(ctx context.Context) (string, error) {
 		return "", errors.Wrap(err, "Can't find anything")
 	}
 
-	docID := fmt.Sprintf("%s.%s", DocType, doc.Index)
-	return docID, nil
+	return strconv.FormatUint(doc.Index, 10), nil
 }
 
 func (s Store) GetLatestAssetID(ctx context.Context) (string, error) {
@@ -205,7 +192,7 @@ func (s Store) GetLatestAssetID(ctx context.Context) (string, error) {
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	rows, err := db.Query(ctx, schema.BlockDDoc, "_view/" +schema.AssetViewByIDInLatest, kivik.Options{
+	rows, err := db.Query(ctx, schema.AssetDDoc, "_view/" +schema.AssetViewByIDInLatest, kivik.Options{
 		"include_docs": true,
 		"descending": true,
 		"limit": 1,
@@ -225,14 +212,52 @@ func (s Store) GetLatestAssetID(ctx context.Context) (string, error) {
 		return "", errors.Wrap(err, "Can't find anything")
 	}
 
-	docID := fmt.Sprintf("%s.%d", DocType, doc.Index)
-	return docID, nil
+	return strconv.FormatUint(doc.Index, 10), nil
 }
 
 // GetAssetCountBtnKeys retrieves the number of keys between two keys.
 // References:
 // 	https://stackoverflow.com/questions/11284383/couchdb-count-unique-document-field
 // 	https://stackoverflow.com/questions/12944294/using-a-couchdb-view-can-i-count-groups-and-filter-by-key-range-at-the-same-tim
+// GetAssetCount retrieves the number of assets. The count view has no rows
+// when there are no assets, which counts as 0.
+func (s Store) GetAssetCount(ctx context.Context) (int64, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "asset.GetAssetCount")
+	defer span.End()
+
+	s.log.Infow("asset.GetAssetCount", "traceid", web.GetTraceID(ctx))
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return 0, errors.Wrap(err, s.dbName+" database check fails")
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	rows, err := db.Query(ctx, schema.AssetDDoc, "_view/"+schema.AssetViewByIDInCount, kivik.Options{
+		"reduce":      true,
+		"group_level": 0,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "Fetch data error")
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		if err := rows.ScanValue(&count); err != nil {
+			return 0, errors.Wrap(err, "Can't find anything")
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, errors.Wrap(err, "rows error")
+	}
+
+	return count, nil
+}
+
 func (s Store) GetAssetCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
 
 	ctx, span := otel.GetTracerProvider().
@@ -253,34 +278,39 @@ func (s Store) GetAssetCountBtnKeys(ctx context.Context, startKey, endKey string
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	rows, err := db.Query(ctx, schema.BlockDDoc, "_view/" +schema.AssetViewByIDInCount, kivik.Options{
-		"start_key": startKey,
-		"end_key": endKey,
-	})
+	first, err := store.ParseID(startKey)
 	if err != nil {
-		return 0, errors.Wrap(err, "Fetch data error")
+		return 0, err
+	}
+	last, err := store.ParseID(endKey)
+	if err != nil {
+		return 0, err
 	}
 
-	type Payload struct {
-		Key *string `json:"key"`
-		Value int64 `json:"value"`
+	rows, err := db.Query(ctx, schema.AssetDDoc, "_view/" +schema.AssetViewByIDInCount, kivik.Options{
+		"start_key": first,
+		"end_key": last,
+		"inclusive_end": true,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "Fetch data error")
 	}
 
-	var payload Payload
+	var count int64
 	for rows.Next() {
-		if err := rows.ScanDoc(&payload); err != nil {
+		if err := rows.ScanValue(&count); err != nil {
 			return 0, errors.Wrap(err, "Can't find anything")
 		}
 	}
 
-	return payload.Value, nil
+	return count, nil
 }
 
 func (s Store) GetAssetsPagination(ctx context.Context, latestAssetID string, order string, pageNo, limit int64) ([]Asset, int64, int64, error) {
 
 	ctx, span := otel.GetTracerProvider().
 		Tracer("").
-		Start(ctx, "block.GetBlocksPagination")
+		Start(ctx, "asset.GetAssetsPagination")
 	span.SetAttributes(attribute.String("latestAssetID", latestAssetID))
 	span.SetAttributes(attribute.Int64("pageNo", pageNo))
 	span.SetAttributes(attribute.Int64("limit", limit))
@@ -293,12 +323,17 @@ func (s Store) GetAssetsPagination(ctx context.Context, latestAssetID string, or
 		"limit", limit)
 
 	// Get the earliest asset id
-	earliestTxnID, err := s.GetEarliestAssetID(ctx)
+	latestKey, err := store.ParseID(latestAssetID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	earliestAssetID, err := s.GetEarliestAssetID(ctx)
 	if err != nil {
 		return nil, 0, 0, errors.Wrap(err, ": Get earliest synced asset id")
 	}
 
-	numOfAssets, err := s.GetAssetCountBtnKeys(ctx, earliestTxnID, latestAssetID)
+	numOfAssets, err := s.GetAssetCountBtnKeys(ctx, earliestAssetID, latestAssetID)
 	if err != nil {
 		return nil, 0, 0, errors.Wrap(err, ": Get asset count between keys")
 	}
@@ -325,7 +360,7 @@ func (s Store) GetAssetsPagination(ctx context.Context, latestAssetID string, or
 		options["descending"] = true
 
 		// Start with latest block number
-		options["start_key"] = latestAssetID
+		options["start_key"] = latestKey
 
 		// Use page number to calculate number of items to skip
 		skip := (pageNo - 1) * limit
@@ -345,7 +380,7 @@ func (s Store) GetAssetsPagination(ctx context.Context, latestAssetID string, or
 		skip := (pageNo - 1) * limit
 		options["skip"] = skip
 
-		if (numOfAssets - skip) > limit {
+		if (numOfAssets - skip) < limit {
 			options["limit"] =  numOfAssets - skip
 		} else {
 			options["limit"] = limit
@@ -4,6 +4,7 @@ import "github.com/algorand/go-algorand-sdk/client/v2/common/models"
 
 type NewAsset struct {
 	ID *string `json:"_id"`
+	Rev string `json:"_rev,omitempty"`
 	models.Asset
 	DocType string	`json:"doc_type"`
 }
@@ -6,12 +6,32 @@ import (
 	"context"
 	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
 	"go.uber.org/zap"
 )
 
+// Storer declares the behavior the block core needs from a storage backend.
+// The CouchDB store in the db package is the default one.
+type Storer interface {
+	AddBlock(ctx context.Context, block db.NewBlock) (string, string, error)
+	CommitBlock(ctx context.Context, docID string) error
+	AddBlocks(ctx context.Context, blocks []db.Block) (bool, error)
+	GetBlockByHash(ctx context.Context, blockHash string) (db.Block, error)
+	GetBlockByNum(ctx context.Context, blockNum uint64) (db.Block, error)
+	GetEarliestSyncedRoundNumber(ctx context.Context) (uint64, error)
+	GetLastSyncedRoundNumber(ctx context.Context) (uint64, bool, error)
+	GetLatestBlock(ctx context.Context) (db.Block, error)
+	GetBlocksPagination(ctx context.Context, latestBlockNum int64, order string, pageNo int64, limit int64) ([]db.Block, int64, int64, error)
+	GetBlocksFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]db.Block, bool, error)
+	GetNumOfBlocks(ctx context.Context) (int64, error)
+	GetBlockTxnSpeed(ctx context.Context) (float64, error)
+	GetRoundGaps(ctx context.Context) ([]db.RoundGap, error)
+	DeleteBlocksBefore(ctx context.Context, round uint64) (int, error)
+}
+
 // Core manages the set of API's for block access.
 type Core struct {
-	store db.Store
+	store Storer
 }
 
 // NewCore constructs a core for block api access.
@@ -21,10 +41,22 @@ func NewCore(log *zap.SugaredLogger, couchClient *kivik.Client, dbName string) C
 	}
 }
 
+// NewCoreWithStore constructs a core for block api access backed by the given
+// store.
+func NewCoreWithStore(store Storer) Core {
+	return Core{
+		store: store,
+	}
+}
+
 func (c Core) AddBlock(ctx context.Context, block db.NewBlock) (string, string, error) {
 	return c.store.AddBlock(ctx, block)
 }
 
+func (c Core) CommitBlock(ctx context.Context, docID string) error {
+	return c.store.CommitBlock(ctx, docID)
+}
+
 func (c Core) AddBlocks(ctx context.Context, blocks []db.Block) (bool, error) {
 	return c.store.AddBlocks(ctx, blocks)
 }
@@ -53,6 +85,21 @@ func (c Core) GetBlocksPagination(ctx context.Context, latestBlockNum int64, ord
 	return c.store.GetBlocksPagination(ctx, latestBlockNum, order, pageNo, limit)
 }
 
+// GetBlocksByCursor retrieves up to limit committed blocks read from cur, or
+// from the start of the list when cur is nil, along with the links to the
+// pages on either side of them.
+func (c Core) GetBlocksByCursor(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]db.Block, cursor.Links, error) {
+	blocks, more, err := c.store.GetBlocksFrom(ctx, cur, order, limit)
+	if err != nil {
+		return nil, cursor.Links{}, err
+	}
+	if len(blocks) == 0 {
+		return blocks, cursor.Links{}, nil
+	}
+
+	return blocks, cursor.LinksOf(cur, blocks[0].Position(), blocks[len(blocks)-1].Position(), more), nil
+}
+
 func (c Core) GetNumOfBlocks(ctx context.Context) (int64, error) {
 	return c.store.GetNumOfBlocks(ctx)
 }
@@ -60,3 +107,11 @@ func (c Core) GetNumOfBlocks(ctx context.Context) (int64, error) {
 func (c Core) GetBlockTxnSpeed(ctx context.Context) (float64, error) {
 	return c.store.GetBlockTxnSpeed(ctx)
 }
+
+func (c Core) GetRoundGaps(ctx context.Context) ([]db.RoundGap, error) {
+	return c.store.GetRoundGaps(ctx)
+}
+
+func (c Core) DeleteBlocksBefore(ctx context.Context, round uint64) (int, error) {
+	return c.store.DeleteBlocksBefore(ctx, round)
+}
@@ -4,8 +4,10 @@ package db
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/couchdb"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -32,7 +34,9 @@ func NewStore(log *zap.SugaredLogger, couchClient *kivik.Client, dbName string)
 	}
 }
 
-// AddBlock adds a block to CouchDB using block hash as ID.
+// AddBlock adds a block to CouchDB using block hash as ID. The block is stored as pending
+// and stays hidden until CommitBlock is called for the document ID returned. A block that
+// is already committed stays committed, so saving a round again doesn't hide it.
 func (s Store) AddBlock(ctx context.Context, block NewBlock) (string, string, error) {
 
 	ctx, span := otel.GetTracerProvider().
@@ -45,6 +49,7 @@ func (s Store) AddBlock(ctx context.Context, block NewBlock) (string, string, er
 	var doc = NewBlockDoc{
 		NewBlock: block,
 		DocType:  DocType,
+		Pending:  true,
 	}
 	exist, err := s.couchClient.DBExists(ctx, s.dbName)
 	if err != nil || !exist {
@@ -56,9 +61,6 @@ func (s Store) AddBlock(ctx context.Context, block NewBlock) (string, string, er
 	//	"_id": block.BlockHash,
 	//	"key": strconv.FormatUint(block.Round, 10),
 	//})
-	fmt.Println("Block hash")
-	fmt.Println(doc.BlockHash)
-	fmt.Println(doc.Round)
 	var docID = ""
 	// This is to handle private network
 	if doc.BlockHash == "" {
@@ -66,12 +68,22 @@ func (s Store) AddBlock(ctx context.Context, block NewBlock) (string, string, er
 	} else {
 		docID = doc.BlockHash
 	}
-	rev, err := db.Put(ctx, docID, doc)
+
+	var existing struct {
+		Pending bool `json:"pending"`
+	}
+	switch err := db.Get(ctx, docID).ScanDoc(&existing); {
+	case err == nil:
+		doc.Pending = existing.Pending
+	case kivik.StatusCode(err) != http.StatusNotFound:
+		return "", "", errors.Wrapf(err, s.dbName+" database can't read block number %d", block.Round)
+	}
+
+	rev, err := couchdb.Upsert(ctx, db, docID, doc)
 	if err != nil {
 		return "", "", errors.Wrapf(err, s.dbName+" database can't insert block number %d", block.Round)
 	}
-	//return strconv.FormatUint(block.Round, 10), rev, nil
-	return block.BlockHash, rev, nil
+	return docID, rev, nil
 }
 
 // AddBlocks add blocks to CouchDB using their block hashes as IDs.
@@ -90,15 +102,17 @@ func (s Store) AddBlocks(ctx context.Context, blocks []Block) (bool, error) {
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	blocks_ := make([]interface{}, len(blocks))
+	ids := make([]string, len(blocks))
 	for i := range blocks {
-		blocks_[i] = blocks[i]
-		v, _ := blocks_[i].(map[string]interface{})
-		v["_id"] = blocks[i].BlockHash
-		blocks_[i] = v
+		ids[i] = blocks[i].BlockHash
 	}
 
-	_, err = db.BulkDocs(ctx, blocks_)
+	err = couchdb.BulkUpsert(ctx, db, ids, func(i int, rev string) interface{} {
+		doc := blocks[i]
+		doc.ID = ids[i]
+		doc.Rev = rev
+		return doc
+	})
 	if err != nil {
 		return false, errors.Wrap(err, "Can't bulk insert the blocks")
 	}
@@ -130,11 +144,13 @@ func (s Store) GetBlockByHash(ctx context.Context, blockHash string) (Block, err
 	}
 
 	var block Block
-	fmt.Printf("%v\n", row)
 	err = row.ScanDoc(&block)
 	if err != nil {
 		return Block{}, errors.Wrap(err, s.dbName+"cannot unpack data from row")
 	}
+	if block.Pending {
+		return Block{}, ErrNotCommitted
+	}
 
 	return block, nil
 }
@@ -263,14 +279,17 @@ func (s Store) GetLastSyncedRoundNumber(ctx context.Context) (uint64, bool, erro
 		return 0, false, errors.Wrap(err, "Fetch data error")
 	}
 
-	if rows.Err() != nil {
-		return 0, false, errors.Wrap(err, "rows error, Can't find anything")
-	}
+	defer rows.Close()
 
-	rows.Next()
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return 0, false, errors.Wrap(rows.Err(), "rows error, Can't find anything")
+		}
+		// No block has been synced yet.
+		return 0, false, nil
+	}
 	var doc Block
 	if err := rows.ScanDoc(&doc); err != nil {
-		// No docs can be found
 		return 0, false, errors.Wrap(err, "Can't find anything")
 	}
 
@@ -376,10 +395,10 @@ func (s Store) GetBlocksPagination(ctx context.Context, latestBlockNum int64, or
 		options["skip"] = (pageNo - 1) * limit
 
 		// Find the key to start reading and get the `page limit` number of records
-		if ((latestBlockNum - int64(earliestBlkNum) + 1) - skip) > limit {
+		if numOfBlks-skip > limit {
 			options["limit"] = limit
 		} else {
-			options["limit"] = latestBlockNum - skip
+			options["limit"] = numOfBlks - skip
 		}
 	} else {
 		// Ascending order
@@ -389,8 +408,8 @@ func (s Store) GetBlocksPagination(ctx context.Context, latestBlockNum int64, or
 		skip := (pageNo - 1) * limit
 		options["skip"] = skip
 
-		if (int64(earliestBlkNum) + skip + limit - 1 - latestBlockNum) > 0 {
-			options["limit"] = latestBlockNum - skip
+		if numOfBlks-skip < limit {
+			options["limit"] = numOfBlks - skip
 		} else {
 			options["limit"] = limit
 		}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrNotCommitted is returned when a block is stored but its round is not
+// fully saved yet.
+var ErrNotCommitted = errors.New("block is not committed yet")
+
+// CommitBlock marks the block stored under docID as committed. It is called
+// once the transactions, accounts, assets and applications of the round are
+// saved, and makes the round visible. Committing a committed block does nothing.
+func (s Store) CommitBlock(ctx context.Context, docID string) error {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "block.CommitBlock")
+	span.SetAttributes(attribute.String("docID", docID))
+	defer span.End()
+
+	s.log.Infow("block.CommitBlock", "traceid", web.GetTraceID(ctx), "docID", docID)
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return fmt.Errorf(s.dbName+" database check fails: %w", err)
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	// The block is read and written back as is, so no field is lost on the way.
+	var doc map[string]interface{}
+	if err := db.Get(ctx, docID).ScanDoc(&doc); err != nil {
+		return fmt.Errorf("reading block %s: %w", docID, err)
+	}
+	if _, pending := doc["pending"]; !pending {
+		return nil
+	}
+	delete(doc, "pending")
+
+	if _, err := db.Put(ctx, docID, doc); err != nil {
+		return fmt.Errorf(s.dbName+" database can't commit block %s: %w", docID, err)
+	}
+	return nil
+}
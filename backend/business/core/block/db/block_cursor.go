@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetBlocksFrom retrieves up to limit committed blocks read from cur, or from
+// the start of the list when cur is nil, in the given order. The boolean
+// returned reports whether blocks are left past them in the direction they
+// were read in.
+//
+// Instead of skipping the blocks of the pages before, the view on rounds is
+// read from the key of the cursor, so reading a page costs the same however deep
+// it is.
+func (s Store) GetBlocksFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]Block, bool, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "block.GetBlocksFrom")
+	span.SetAttributes(attribute.Int64("limit", limit))
+	defer span.End()
+
+	s.log.Infow("block.GetBlocksFrom",
+		"traceid", web.GetTraceID(ctx),
+		"cursor", cur,
+		"limit", limit)
+
+	if limit < 1 {
+		return nil, false, cursor.ErrLimit
+	}
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return nil, false, fmt.Errorf(s.dbName+" database check fails: %w", err)
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	descending := cur.Descending(order)
+
+	// The block at the cursor is read too when it still exists, so one more
+	// than needed to tell whether blocks are left is asked for.
+	options := kivik.Options{
+		"include_docs": true,
+		"descending":   descending,
+		"limit":        limit + 2,
+	}
+	if cur != nil {
+		options["start_key"] = cur.Round
+		options["start_key_doc_id"] = cur.ID
+	}
+
+	rows, err := db.Query(ctx, schema.BlockDDoc, "_view/"+schema.BlockViewByRoundNo, options)
+	if err != nil {
+		return nil, false, fmt.Errorf("fetch data error: %w", err)
+	}
+	defer rows.Close()
+
+	var fetchedBlocks = []Block{}
+	for rows.Next() {
+		var block Block
+		if err := rows.ScanDoc(&block); err != nil {
+			return nil, false, fmt.Errorf("unwrapping block: %w", err)
+		}
+		if cur.Includes(block.Position(), descending) {
+			fetchedBlocks = append(fetchedBlocks, block)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+
+	more := int64(len(fetchedBlocks)) > limit
+	if more {
+		fetchedBlocks = fetchedBlocks[:limit]
+	}
+	if cur != nil && cur.Before {
+		for i, j := 0, len(fetchedBlocks)-1; i < j; i, j = i+1, j-1 {
+			fetchedBlocks[i], fetchedBlocks[j] = fetchedBlocks[j], fetchedBlocks[i]
+		}
+	}
+
+	return fetchedBlocks, more, nil
+}
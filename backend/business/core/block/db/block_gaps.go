@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+)
+
+// gapScanPageSize is the number of rounds read from the block view per query
+// while scanning for gaps.
+const gapScanPageSize = 10000
+
+// GetRoundGaps scans the rounds stored in the database, from the earliest to the
+// latest one, and returns the ranges of rounds missing in between.
+func (s Store) GetRoundGaps(ctx context.Context) ([]RoundGap, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "block.GetRoundGaps")
+	defer span.End()
+
+	s.log.Infow("block.GetRoundGaps", "traceid", web.GetTraceID(ctx))
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return nil, fmt.Errorf(s.dbName+" database check fails: %w", err)
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	var gaps = []RoundGap{}
+	var next uint64
+	var started bool
+
+	for {
+		opts := kivik.Options{
+			"limit": gapScanPageSize,
+		}
+		if started {
+			opts["startkey"] = next
+		}
+
+		rows, err := db.Query(ctx, schema.BlockDDoc, "_view/"+schema.BlockViewByRoundNo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("fetch data error: %w", err)
+		}
+
+		var count int
+		for rows.Next() {
+			count++
+			var round uint64
+			if err := rows.ScanKey(&round); err != nil {
+				return nil, fmt.Errorf("unwrapping round number: %w", err)
+			}
+
+			switch {
+			case !started:
+				started = true
+			case round < next:
+				// The same round stored more than once.
+				continue
+			case round > next:
+				gaps = append(gaps, RoundGap{From: next, To: round - 1})
+			}
+			next = round + 1
+		}
+		if rows.Err() != nil {
+			return nil, fmt.Errorf("rows error: %w", rows.Err())
+		}
+
+		if count < gapScanPageSize {
+			break
+		}
+	}
+
+	return gaps, nil
+}
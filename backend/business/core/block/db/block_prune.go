@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// prunePageSize is the number of blocks deleted per bulk request while pruning.
+const prunePageSize = 500
+
+// DeleteBlocksBefore deletes every block older than round and returns how many
+// were deleted.
+func (s Store) DeleteBlocksBefore(ctx context.Context, round uint64) (int, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "block.DeleteBlocksBefore")
+	span.SetAttributes(attribute.Int64("round", int64(round)))
+	defer span.End()
+
+	s.log.Infow("block.DeleteBlocksBefore", "traceid", web.GetTraceID(ctx), "round", round)
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return 0, fmt.Errorf(s.dbName+" database check fails: %w", err)
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	var deleted int
+	for {
+		rows, err := db.Query(ctx, schema.BlockDDoc, "_view/"+schema.BlockViewByRoundNo, kivik.Options{
+			"include_docs":  true,
+			"endkey":        round,
+			"inclusive_end": false,
+			"limit":         prunePageSize,
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("fetch data error: %w", err)
+		}
+
+		var docs []interface{}
+		for rows.Next() {
+			var doc struct {
+				ID  string `json:"_id"`
+				Rev string `json:"_rev"`
+			}
+			if err := rows.ScanDoc(&doc); err != nil {
+				return deleted, fmt.Errorf("unwrapping block: %w", err)
+			}
+			docs = append(docs, map[string]interface{}{
+				"_id":      doc.ID,
+				"_rev":     doc.Rev,
+				"_deleted": true,
+			})
+		}
+		if rows.Err() != nil {
+			return deleted, fmt.Errorf("rows error: %w", rows.Err())
+		}
+
+		if len(docs) == 0 {
+			return deleted, nil
+		}
+		if _, err := db.BulkDocs(ctx, docs); err != nil {
+			return deleted, fmt.Errorf(s.dbName+" database can't delete blocks before round %d: %w", round, err)
+		}
+		deleted += len(docs)
+
+		if len(docs) < prunePageSize {
+			return deleted, nil
+		}
+	}
+}
@@ -2,6 +2,8 @@ package db
 
 import (
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
 )
 
 // NewBlock represents the data structure for constructing a new block data
@@ -9,11 +11,18 @@ type NewBlock struct {
 	models.Block
 	Proposer        string  `json:"proposer"`
 	BlockHash       string  `json:"block-hash"`
+	// TransactionExtras holds, for each entry of Transactions, the fields of
+	// transaction types models.Transaction has no room for. They are stored
+	// with the transactions, not with the block.
+	TransactionExtras []txnfields.TransactionExtras `json:"-"`
 }
 
 type NewBlockDoc struct {
 	NewBlock
 	DocType			string	`json:"doc_type"`
+	// Pending is set until everything else of the round is saved and the
+	// block is committed. Pending blocks are left out of every view.
+	Pending			bool	`json:"pending,omitempty"`
 }
 
 // Block represents the data structure of a block document.
@@ -23,3 +32,51 @@ type Block struct {
 	Rev string `json:"_rev,omitempty"`
 }
 
+// Position returns where the block stands in the list of blocks, which orders
+// them by round and then by document ID like the view on rounds does.
+func (b Block) Position() cursor.Position {
+	return cursor.Position{Round: b.Round, ID: b.ID}
+}
+
+// BlockHeader is the part of a block list pages show: the block without its
+// transactions, which are only counted.
+type BlockHeader struct {
+	Round             uint64 `json:"round"`
+	BlockHash         string `json:"block-hash"`
+	PreviousBlockHash []byte `json:"previous-block-hash"`
+	Timestamp         uint64 `json:"timestamp"`
+	Proposer          string `json:"proposer"`
+	TxnCount          int    `json:"txn-count"`
+}
+
+// Header returns the header of the block.
+func (b Block) Header() BlockHeader {
+	return BlockHeader{
+		Round:             b.Round,
+		BlockHash:         b.BlockHash,
+		PreviousBlockHash: b.PreviousBlockHash,
+		Timestamp:         b.Timestamp,
+		Proposer:          b.Proposer,
+		TxnCount:          len(b.Transactions),
+	}
+}
+
+// Headers returns the headers of blocks.
+func Headers(blocks []Block) []BlockHeader {
+	headers := make([]BlockHeader, len(blocks))
+	for i := range blocks {
+		headers[i] = blocks[i].Header()
+	}
+	return headers
+}
+
+// RoundGap represents a range of consecutive rounds missing from the database.
+type RoundGap struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// Size returns the number of rounds missing in the gap.
+func (g RoundGap) Size() uint64 {
+	return g.To - g.From + 1
+}
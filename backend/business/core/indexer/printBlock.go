@@ -59,7 +59,7 @@ func PrintBlockInfoFromJSONBlock(jsonBlock models.Block) error {
 	fmt.Printf("\t- Timestamp: %d\n", jsonBlock.Timestamp)
 
 	// Print Transaction
-	fmt.Println("\t- Transactions:" + string(len(jsonBlock.Transactions)))
+	fmt.Printf("\t- Transactions: %d\n", len(jsonBlock.Transactions))
 	for idx, txn := range jsonBlock.Transactions {
 		fmt.Printf("\t\t- Transaction %d\n", idx)
 		PrintTransactionInBlock(txn, 3)
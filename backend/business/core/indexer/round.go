@@ -3,6 +3,7 @@ package indexer
 import (
 	"context"
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/kevguy/algosearch/backend/business/core/block/db"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -29,6 +30,56 @@ func (c Core) GetRound(ctx context.Context, traceID string, roundNum uint64) (mo
 	return jsonBlock, nil
 }
 
+// GetCurrentRoundNum retrieves the latest round the Indexer has imported.
+func (c Core) GetCurrentRoundNum(ctx context.Context) (uint64, error) {
+
+	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "indexer.GetCurrentRoundNum")
+	defer span.End()
+
+	health, err := c.client.HealthCheck().Do(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "Error getting indexer health")
+	}
+
+	return health.Round, nil
+}
+
+// ConvertBlockJSON turns a block from the Indexer API into the block data saved
+// to CouchDB. The Indexer leaves out the transaction fields it shares with the
+// block, they are filled in so transactions look the same as the ones read from
+// algod. The Indexer knows neither the proposer nor the block hash.
+func ConvertBlockJSON(jsonBlock models.Block) db.NewBlock {
+	for i := range jsonBlock.Transactions {
+		fillTransactionFromBlock(&jsonBlock.Transactions[i], jsonBlock, jsonBlock.Transactions[i].IntraRoundOffset)
+	}
+
+	return db.NewBlock{
+		Block: jsonBlock,
+	}
+}
+
+// fillTransactionFromBlock sets the fields of txn and its inner transactions that
+// come from the block. Inner transactions share the offset of their parent.
+func fillTransactionFromBlock(txn *models.Transaction, jsonBlock models.Block, offset uint64) {
+	if txn.ConfirmedRound == 0 {
+		txn.ConfirmedRound = jsonBlock.Round
+	}
+	if txn.RoundTime == 0 {
+		txn.RoundTime = jsonBlock.Timestamp
+	}
+	if len(txn.GenesisHash) == 0 {
+		txn.GenesisHash = jsonBlock.GenesisHash
+	}
+	if txn.GenesisId == "" {
+		txn.GenesisId = jsonBlock.GenesisId
+	}
+	txn.IntraRoundOffset = offset
+
+	for i := range txn.InnerTxns {
+		fillTransactionFromBlock(&txn.InnerTxns[i], jsonBlock, offset)
+	}
+}
+
 // LookupRoundInJSON searches for a block from the Indexer API based upon the
 // round number given.
 func (c Core) LookupRoundInJSON(ctx context.Context, roundNum uint64) (models.Block, error) {
@@ -0,0 +1,67 @@
+package indexer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestConvertBlockJSON(t *testing.T) {
+	t.Log("Given the need to store Indexer blocks the way algod blocks are stored.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen converting a block with an application call issuing inner transactions.", testID)
+		{
+			jsonBlock := models.Block{
+				GenesisHash: []byte{1, 2, 3},
+				GenesisId:   "testnet-v1.0",
+				Round:       42,
+				Timestamp:   1650000000,
+				Transactions: []models.Transaction{
+					{Id: "PAY", Type: "pay"},
+					{
+						Id:               "APPL",
+						Type:             "appl",
+						IntraRoundOffset: 1,
+						InnerTxns: []models.Transaction{
+							{Type: "axfer"},
+							{Type: "appl", InnerTxns: []models.Transaction{{Type: "pay"}}},
+						},
+					},
+				},
+			}
+
+			newBlock := ConvertBlockJSON(jsonBlock)
+
+			if newBlock.Round != 42 || len(newBlock.Transactions) != 2 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould keep the block's round and transactions : got round %d with %d transactions.", failed, testID, newBlock.Round, len(newBlock.Transactions))
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould keep the block's round and transactions.", success, testID)
+
+			deepest := newBlock.Transactions[1].InnerTxns[1].InnerTxns[0]
+			for _, txn := range []models.Transaction{newBlock.Transactions[0], newBlock.Transactions[1], deepest} {
+				if txn.ConfirmedRound != 42 || txn.RoundTime != 1650000000 || txn.GenesisId != "testnet-v1.0" || !bytes.Equal(txn.GenesisHash, jsonBlock.GenesisHash) {
+					t.Fatalf("\t\t%s\tTest %d:\tShould fill in the block fields of %s transactions : got %+v.", failed, testID, txn.Type, txn)
+				}
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould fill in the block fields of top-level and inner transactions.", success, testID)
+
+			if deepest.IntraRoundOffset != 1 || newBlock.Transactions[1].InnerTxns[0].IntraRoundOffset != 1 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould give inner transactions the offset of their parent : got %d.", failed, testID, deepest.IntraRoundOffset)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould give inner transactions the offset of their parent.", success, testID)
+
+			if newBlock.BlockHash != "" || newBlock.Proposer != "" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould leave the block hash and proposer empty : got %q and %q.", failed, testID, newBlock.BlockHash, newBlock.Proposer)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould leave the block hash and proposer empty.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package db contains sync state related CRUD functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	DocType            = "sync_state"
+	FailedRoundDocType = "failed_round"
+	LeaseDocType       = "sync_lease"
+
+	// SyncStateDocID is the ID of the single document holding the sync state.
+	SyncStateDocID = "sync_state"
+	// SyncLeaseDocID is the ID of the single document holding the sync lease.
+	SyncLeaseDocID = "sync_lease"
+)
+
+// Store manages the set of API's for sync state access.
+type Store struct {
+	log         *zap.SugaredLogger
+	couchClient *kivik.Client
+	dbName      string
+}
+
+// NewStore constructs a sync state store for api access.
+func NewStore(log *zap.SugaredLogger, couchClient *kivik.Client, dbName string) Store {
+	return Store{
+		log:         log,
+		couchClient: couchClient,
+		dbName:      dbName,
+	}
+}
+
+// GetSyncState retrieves the sync state document from CouchDB. The boolean
+// returned is false when no sync state has been recorded yet.
+func (s Store) GetSyncState(ctx context.Context) (SyncState, bool, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "syncstate.GetSyncState")
+	defer span.End()
+
+	s.log.Infow("syncstate.GetSyncState", "traceid", web.GetTraceID(ctx))
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return SyncState{}, false, errors.Wrap(err, s.dbName+" database check fails")
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	var doc SyncState
+	if err := db.Get(ctx, SyncStateDocID).ScanDoc(&doc); err != nil {
+		if kivik.StatusCode(err) == http.StatusNotFound {
+			return SyncState{}, false, nil
+		}
+		return SyncState{}, false, fmt.Errorf("fetching sync state: %w", err)
+	}
+
+	return doc, true, nil
+}
+
+// SetLastSyncedRound records round as the highest round for which it and every
+// round before it have been synced to CouchDB.
+func (s Store) SetLastSyncedRound(ctx context.Context, round uint64) error {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "syncstate.SetLastSyncedRound")
+	span.SetAttributes(attribute.Int64("round", int64(round)))
+	defer span.End()
+
+	s.log.Infow("syncstate.SetLastSyncedRound", "traceid", web.GetTraceID(ctx), "round", round)
+
+	doc, _, err := s.GetSyncState(ctx)
+	if err != nil {
+		return err
+	}
+	doc.DocType = DocType
+	doc.LastSyncedRound = round
+	doc.NothingSynced = false
+	doc.UpdatedAt = time.Now().UTC()
+
+	db := s.couchClient.DB(s.dbName)
+	if _, err := db.Put(ctx, SyncStateDocID, doc); err != nil {
+		return errors.Wrapf(err, s.dbName+" database can't record last synced round %d", round)
+	}
+
+	return nil
+}
+
+// ResetSyncState records that no round has been synced, so syncing starts over
+// from the first round.
+func (s Store) ResetSyncState(ctx context.Context) error {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "syncstate.ResetSyncState")
+	defer span.End()
+
+	s.log.Infow("syncstate.ResetSyncState", "traceid", web.GetTraceID(ctx))
+
+	doc, _, err := s.GetSyncState(ctx)
+	if err != nil {
+		return err
+	}
+	doc.DocType = DocType
+	doc.LastSyncedRound = 0
+	doc.NothingSynced = true
+	doc.UpdatedAt = time.Now().UTC()
+
+	db := s.couchClient.DB(s.dbName)
+	if _, err := db.Put(ctx, SyncStateDocID, doc); err != nil {
+		return errors.Wrap(err, s.dbName+" database can't reset sync state")
+	}
+
+	return nil
+}
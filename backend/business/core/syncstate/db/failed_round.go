@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// failedRoundDocID returns the ID of the document tracking a failed round.
+func failedRoundDocID(round uint64) string {
+	return fmt.Sprintf("%s.%d", FailedRoundDocType, round)
+}
+
+// RecordFailedRound adds or updates the document tracking a round that could not be
+// ingested. Attempts are added to the ones already recorded for the round.
+func (s Store) RecordFailedRound(ctx context.Context, round uint64, attempts int, failure error) error {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "syncstate.RecordFailedRound")
+	span.SetAttributes(attribute.Int64("round", int64(round)))
+	defer span.End()
+
+	s.log.Infow("syncstate.RecordFailedRound", "traceid", web.GetTraceID(ctx), "round", round)
+
+	doc, found, err := s.GetFailedRound(ctx, round)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if !found {
+		doc = FailedRound{
+			DocType:       FailedRoundDocType,
+			Round:         round,
+			FirstFailedAt: now,
+		}
+	}
+	doc.Attempts += attempts
+	doc.LastFailedAt = now
+	if failure != nil {
+		doc.Error = failure.Error()
+	}
+
+	db := s.couchClient.DB(s.dbName)
+	if _, err := db.Put(ctx, failedRoundDocID(round), doc); err != nil {
+		return errors.Wrapf(err, s.dbName+" database can't record failed round %d", round)
+	}
+
+	return nil
+}
+
+// GetFailedRound retrieves the document tracking a failed round. The boolean returned
+// is false when the round is not recorded as failed.
+func (s Store) GetFailedRound(ctx context.Context, round uint64) (FailedRound, bool, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "syncstate.GetFailedRound")
+	span.SetAttributes(attribute.Int64("round", int64(round)))
+	defer span.End()
+
+	s.log.Infow("syncstate.GetFailedRound", "traceid", web.GetTraceID(ctx), "round", round)
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return FailedRound{}, false, errors.Wrap(err, s.dbName+" database check fails")
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	var doc FailedRound
+	if err := db.Get(ctx, failedRoundDocID(round)).ScanDoc(&doc); err != nil {
+		if kivik.StatusCode(err) == http.StatusNotFound {
+			return FailedRound{}, false, nil
+		}
+		return FailedRound{}, false, fmt.Errorf("fetching failed round %d: %w", round, err)
+	}
+
+	return doc, true, nil
+}
+
+// GetFailedRounds retrieves every round recorded as failed, in ascending round order.
+func (s Store) GetFailedRounds(ctx context.Context) ([]FailedRound, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "syncstate.GetFailedRounds")
+	defer span.End()
+
+	s.log.Infow("syncstate.GetFailedRounds", "traceid", web.GetTraceID(ctx))
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return nil, errors.Wrap(err, s.dbName+" database check fails")
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	rows, err := db.Query(ctx, schema.SyncDDoc, "_view/"+schema.SyncViewFailedRoundByRoundNo, kivik.Options{
+		"include_docs": true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetch data error")
+	}
+
+	var fetchedRounds = []FailedRound{}
+	for rows.Next() {
+		var doc FailedRound
+		if err := rows.ScanDoc(&doc); err != nil {
+			return nil, errors.Wrap(err, "unwrapping failed round")
+		}
+		fetchedRounds = append(fetchedRounds, doc)
+	}
+
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows error, Can't find anything")
+	}
+
+	return fetchedRounds, nil
+}
+
+// DeleteFailedRound removes the document tracking a failed round, once the round
+// has been ingested successfully. It is not an error if the round is not recorded.
+func (s Store) DeleteFailedRound(ctx context.Context, round uint64) error {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "syncstate.DeleteFailedRound")
+	span.SetAttributes(attribute.Int64("round", int64(round)))
+	defer span.End()
+
+	s.log.Infow("syncstate.DeleteFailedRound", "traceid", web.GetTraceID(ctx), "round", round)
+
+	doc, found, err := s.GetFailedRound(ctx, round)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+
+	db := s.couchClient.DB(s.dbName)
+	if _, err := db.Delete(ctx, doc.ID, doc.Rev); err != nil {
+		return errors.Wrapf(err, s.dbName+" database can't delete failed round %d", round)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetLease retrieves the sync lease document. The boolean returned is false when
+// no instance has ever taken the lease.
+func (s Store) GetLease(ctx context.Context) (SyncLease, bool, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "syncstate.GetLease")
+	defer span.End()
+
+	s.log.Infow("syncstate.GetLease", "traceid", web.GetTraceID(ctx))
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return SyncLease{}, false, errors.Wrap(err, s.dbName+" database check fails")
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	var doc SyncLease
+	if err := db.Get(ctx, SyncLeaseDocID).ScanDoc(&doc); err != nil {
+		if kivik.StatusCode(err) == http.StatusNotFound {
+			return SyncLease{}, false, nil
+		}
+		return SyncLease{}, false, fmt.Errorf("fetching sync lease: %w", err)
+	}
+
+	return doc, true, nil
+}
+
+// AcquireLease takes or renews the sync lease for holder, for ttl from now. The
+// lease can only be taken when it is free or has expired. The boolean returned
+// reports whether holder holds the lease, along with the lease as last seen.
+// CouchDB's revision check makes sure two instances racing for the lease can't
+// both win.
+func (s Store) AcquireLease(ctx context.Context, holder string, ttl time.Duration) (SyncLease, bool, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "syncstate.AcquireLease")
+	span.SetAttributes(attribute.String("holder", holder))
+	defer span.End()
+
+	s.log.Infow("syncstate.AcquireLease", "traceid", web.GetTraceID(ctx), "holder", holder)
+
+	lease, found, err := s.GetLease(ctx)
+	if err != nil {
+		return SyncLease{}, false, err
+	}
+
+	now := time.Now().UTC()
+	if found && lease.Holder != holder && now.Before(lease.ExpiresAt) {
+		return lease, false, nil
+	}
+
+	if !found || lease.Holder != holder {
+		lease.AcquiredAt = now
+	}
+	lease.DocType = LeaseDocType
+	lease.Holder = holder
+	lease.RenewedAt = now
+	lease.ExpiresAt = now.Add(ttl)
+
+	db := s.couchClient.DB(s.dbName)
+	rev, err := db.Put(ctx, SyncLeaseDocID, lease)
+	if err != nil {
+		if kivik.StatusCode(err) == http.StatusConflict {
+			// Another instance got there first.
+			lease, _, err := s.GetLease(ctx)
+			return lease, false, err
+		}
+		return SyncLease{}, false, errors.Wrap(err, s.dbName+" database can't write sync lease")
+	}
+	lease.Rev = rev
+
+	return lease, true, nil
+}
+
+// ReleaseLease gives up the sync lease if holder holds it, so another instance can
+// take it over without waiting for it to expire.
+func (s Store) ReleaseLease(ctx context.Context, holder string) error {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "syncstate.ReleaseLease")
+	span.SetAttributes(attribute.String("holder", holder))
+	defer span.End()
+
+	s.log.Infow("syncstate.ReleaseLease", "traceid", web.GetTraceID(ctx), "holder", holder)
+
+	lease, found, err := s.GetLease(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if !found || !lease.HeldBy(holder, now) {
+		return nil
+	}
+
+	lease.ExpiresAt = now
+	db := s.couchClient.DB(s.dbName)
+	if _, err := db.Put(ctx, SyncLeaseDocID, lease); err != nil {
+		if kivik.StatusCode(err) == http.StatusConflict {
+			return nil
+		}
+		return errors.Wrap(err, s.dbName+" database can't release sync lease")
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package db
+
+import "time"
+
+// NewSyncState represents the data structure for constructing the sync state document.
+type NewSyncState struct {
+	DocType         string `json:"doc_type"`
+	LastSyncedRound uint64 `json:"last_synced_round"`
+	// NothingSynced is set when syncing was restarted from the first round,
+	// LastSyncedRound is meaningless then.
+	NothingSynced bool      `json:"nothing_synced,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SyncState represents the data structure of the sync state document. It
+// records how far the block synchronizer has got with an unbroken run of rounds.
+type SyncState struct {
+	NewSyncState
+	ID  string `json:"_id,omitempty"`
+	Rev string `json:"_rev,omitempty"`
+}
+
+// FailedRound represents the data structure of a round the block synchronizer
+// could not ingest even after retrying.
+type FailedRound struct {
+	ID            string    `json:"_id,omitempty"`
+	Rev           string    `json:"_rev,omitempty"`
+	DocType       string    `json:"doc_type"`
+	Round         uint64    `json:"round"`
+	Error         string    `json:"error"`
+	Attempts      int       `json:"attempts"`
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	LastFailedAt  time.Time `json:"last_failed_at"`
+}
+
+// SyncLease represents the data structure of the lease document. The instance
+// holding an unexpired lease is the only one running the block synchronizer.
+type SyncLease struct {
+	ID         string    `json:"_id,omitempty"`
+	Rev        string    `json:"_rev,omitempty"`
+	DocType    string    `json:"doc_type"`
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// HeldBy reports whether holder holds the lease at the given time.
+func (l SyncLease) HeldBy(holder string, now time.Time) bool {
+	return l.Holder == holder && now.Before(l.ExpiresAt)
+}
@@ -0,0 +1,87 @@
+// Package syncstate provides the core business API of handling
+// the bookkeeping of the block synchronizer.
+package syncstate
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/core/syncstate/db"
+	"go.uber.org/zap"
+)
+
+// Storer declares the behavior the sync state core needs from a storage backend.
+// The CouchDB store in the db package is the default one.
+type Storer interface {
+	GetSyncState(ctx context.Context) (db.SyncState, bool, error)
+	SetLastSyncedRound(ctx context.Context, round uint64) error
+	ResetSyncState(ctx context.Context) error
+	RecordFailedRound(ctx context.Context, round uint64, attempts int, failure error) error
+	GetFailedRound(ctx context.Context, round uint64) (db.FailedRound, bool, error)
+	GetFailedRounds(ctx context.Context) ([]db.FailedRound, error)
+	DeleteFailedRound(ctx context.Context, round uint64) error
+	GetLease(ctx context.Context) (db.SyncLease, bool, error)
+	AcquireLease(ctx context.Context, holder string, ttl time.Duration) (db.SyncLease, bool, error)
+	ReleaseLease(ctx context.Context, holder string) error
+}
+
+// Core manages the set of API's for sync state access.
+type Core struct {
+	store Storer
+}
+
+// NewCore constructs a core for sync state api access.
+func NewCore(log *zap.SugaredLogger, couchClient *kivik.Client, dbName string) Core {
+	return Core{
+		store: db.NewStore(log, couchClient, dbName),
+	}
+}
+
+// NewCoreWithStore constructs a core for sync state api access backed by the given
+// store.
+func NewCoreWithStore(store Storer) Core {
+	return Core{
+		store: store,
+	}
+}
+
+func (c Core) GetSyncState(ctx context.Context) (db.SyncState, bool, error) {
+	return c.store.GetSyncState(ctx)
+}
+
+func (c Core) SetLastSyncedRound(ctx context.Context, round uint64) error {
+	return c.store.SetLastSyncedRound(ctx, round)
+}
+
+func (c Core) ResetSyncState(ctx context.Context) error {
+	return c.store.ResetSyncState(ctx)
+}
+
+func (c Core) RecordFailedRound(ctx context.Context, round uint64, attempts int, failure error) error {
+	return c.store.RecordFailedRound(ctx, round, attempts, failure)
+}
+
+func (c Core) GetFailedRound(ctx context.Context, round uint64) (db.FailedRound, bool, error) {
+	return c.store.GetFailedRound(ctx, round)
+}
+
+func (c Core) GetFailedRounds(ctx context.Context) ([]db.FailedRound, error) {
+	return c.store.GetFailedRounds(ctx)
+}
+
+func (c Core) DeleteFailedRound(ctx context.Context, round uint64) error {
+	return c.store.DeleteFailedRound(ctx, round)
+}
+
+func (c Core) GetLease(ctx context.Context) (db.SyncLease, bool, error) {
+	return c.store.GetLease(ctx)
+}
+
+func (c Core) AcquireLease(ctx context.Context, holder string, ttl time.Duration) (db.SyncLease, bool, error) {
+	return c.store.AcquireLease(ctx, holder, ttl)
+}
+
+func (c Core) ReleaseLease(ctx context.Context, holder string) error {
+	return c.store.ReleaseLease(ctx, holder)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"strconv"
 )
 
 func (s Store) GetTransactionCountByAcct(ctx context.Context, acctID, startKey, endKey string) (int64, error) {
@@ -38,20 +37,18 @@ func (s Store) GetTransactionCountByAcct(ctx context.Context, acctID, startKey,
 	if err != nil {
 		return 0, fmt.Errorf("fetch earliest transaction error: %w", err)
 	}
-	earliestRoundTime := earliestTxn.RoundTime
 
 	latestTxn, err := s.GetTransaction(ctx, endKey)
 	if err != nil {
 		return 0, fmt.Errorf("fetch latest transaction error: %w", err)
 	}
-	latestRoundTime := latestTxn.RoundTime
 
 	// https://github.com/go-kivik/kivik/issues/246
 	rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/" +schema.TransactionViewByAccountCount, kivik.Options{
 		//"start_key": []string{acctID, "1", strconv.FormatUint(earliestRoundTime, 10), startKey},
 		//"end_key": []string{acctID, "1", strconv.FormatUint(latestRoundTime, 10), endKey},
-		"start_key": []string{acctID, strconv.FormatUint(earliestRoundTime, 10), startKey},
-		"end_key": []string{acctID, strconv.FormatUint(latestRoundTime, 10), endKey},
+		"start_key": append([]interface{}{acctID}, txnViewKey(earliestTxn.Transaction, startKey)...),
+		"end_key": append([]interface{}{acctID}, txnViewKey(latestTxn.Transaction, endKey)...),
 		"inclusive_end": true,
 		"reduce": true,
 		"group_level": 0,
@@ -8,7 +8,6 @@ import (
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"strconv"
 )
 
 func (s Store) GetTransactionsByAcctPagination(ctx context.Context, acctID, order string, pageNo, limit int64) ([]Transaction, int64, int64, error) {
@@ -31,7 +30,6 @@ func (s Store) GetTransactionsByAcctPagination(ctx context.Context, acctID, orde
 		return nil, 0, 0, errors.Wrap(err, ": Get earliest synced transaction id")
 	}
 	earliestTxnID := earliestTxn.ID
-	earliestRoundTime := earliestTxn.RoundTime
 
 	// Get the latest transaction id
 	latestTxn, err := s.GetLatestAcctTransaction(ctx, acctID)
@@ -39,7 +37,6 @@ func (s Store) GetTransactionsByAcctPagination(ctx context.Context, acctID, orde
 		return nil, 0, 0, errors.Wrap(err, ": Get latest synced transaction id")
 	}
 	latestTxnID := latestTxn.ID
-	latestRoundTime := latestTxn.RoundTime
 
 	numOfTransactions, err := s.GetTransactionCountByAcct(ctx, acctID, earliestTxnID, latestTxnID)
 	if err != nil {
@@ -74,12 +71,7 @@ func (s Store) GetTransactionsByAcctPagination(ctx context.Context, acctID, orde
 		options["descending"] = true
 
 		// Start with latest block number we managed to find for the time being
-		options["start_key"] = []string{
-			acctID,
-			"1",
-			strconv.FormatUint(latestRoundTime, 10),
-			latestTxnID,
-		}
+		options["start_key"] = append([]interface{}{acctID, "1"}, txnViewKey(latestTxn.Transaction, latestTxnID)...)
 
 		// Use page number to calculate number of items to skip
 		skip := (pageNo - 1) * limit
@@ -97,12 +89,7 @@ func (s Store) GetTransactionsByAcctPagination(ctx context.Context, acctID, orde
 
 		//Start with earliest block number found
 		// Start with latest block number we managed to find for the time being
-		options["start_key"] = []string{
-			acctID,
-			"1",
-			strconv.FormatUint(earliestRoundTime, 10),
-			earliestTxnID,
-		}
+		options["start_key"] = append([]interface{}{acctID, "1"}, txnViewKey(earliestTxn.Transaction, earliestTxnID)...)
 
 		// Calculate the number of records to skip
 		skip := (pageNo - 1) * limit
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/business/data/store"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -64,13 +65,26 @@ func (s Store) getEarliestLatestAppTransaction(ctx context.Context, appID string
 	}
 	db := s.couchClient.DB(s.dbName)
 
+	key, err := store.ParseID(appID)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	first := []interface{}{key, "1"}
+	last := []interface{}{key, "1", map[string]interface{}{}}
+
 	options := kivik.Options{
 		"include_docs": true,
 		"limit": 1,
-		"start_key": fmt.Sprintf("\"[%s, 1]\"", appID),
+		"start_key": first,
+		"end_key": last,
+		"descending": false,
 	}
 
-	options["descending"] = !earliest
+	if !earliest {
+		options["start_key"], options["end_key"] = last, first
+		options["descending"] = true
+	}
 
 	rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/" + schema.TransactionViewByApplication, options)
 	if err != nil {
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetTransactionCountByApp retrieves the number of transactions an application is
+// involved in.
+func (s Store) GetTransactionCountByApp(ctx context.Context, appID string) (int64, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetTransactionCountByApp")
+	span.SetAttributes(attribute.String("appID", appID))
+	defer span.End()
+
+	s.log.Infow("transaction.GetTransactionCountByApp",
+		"traceid", web.GetTraceID(ctx),
+		"appID", appID)
+
+	return s.countByIndex(ctx, schema.TransactionViewByApplicationCount, appID)
+}
+
+// GetTransactionsByAppPagination retrieves a page of the transactions an
+// application is involved in, ordered by round, position within the round and
+// ID, along with the number of pages and of transactions.
+func (s Store) GetTransactionsByAppPagination(ctx context.Context, appID, order string, pageNo, limit int64) ([]Transaction, int64, int64, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetTransactionsByAppPagination")
+	span.SetAttributes(attribute.String("appID", appID))
+	span.SetAttributes(attribute.Int64("pageNo", pageNo))
+	span.SetAttributes(attribute.Int64("limit", limit))
+	defer span.End()
+
+	s.log.Infow("transaction.GetTransactionsByAppPagination",
+		"traceid", web.GetTraceID(ctx),
+		"appID", appID,
+		"pageNo", pageNo,
+		"limit", limit)
+
+	return s.pageByIndex(ctx, schema.TransactionViewByApplication, schema.TransactionViewByApplicationCount, appID, order, pageNo, limit)
+}
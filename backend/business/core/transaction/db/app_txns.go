@@ -2,9 +2,9 @@ package db
 
 import (
 	"context"
-	"fmt"
 	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/business/data/store"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -28,27 +28,32 @@ func (s Store) GetTransactionsByApp(ctx context.Context, appID string, order str
 	}
 	db := s.couchClient.DB(s.dbName)
 
+	key, err := store.ParseID(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The transactions of the application are keyed [appID, "1", ...] in the
+	// view on applications, between [appID, "1"] and [appID, "1", {}].
+	first := []interface{}{key, "1"}
+	last := []interface{}{key, "1", map[string]interface{}{}}
+
 	options := kivik.Options{
 		"include_docs": true,
-		//"limit": limit,
 		"reduce": false,
 		"inclusive_end": true,
-		//"start_key": []string{appID, "1"},
-		//"end_key": []string{appID, "2"},
 	}
 
 	if order == "asc" {
-		options["start_key"] = []string{appID, "1"}
-		options["end_key"] = []string{appID, "2"}
+		options["start_key"] = first
+		options["end_key"] = last
 		options["descending"] = false
 	} else {
 		// assuming it's "desc"
-		options["start_key"] = []string{appID, "2"}
-		options["end_key"] = []string{appID, "1"}
+		options["start_key"] = last
+		options["end_key"] = first
 		options["descending"] = true
 	}
-	fmt.Println(options["start_key"])
-	fmt.Println(options["end_key"])
 
 	rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/" +schema.TransactionViewByApplication, options)
 	if err != nil {
@@ -56,16 +61,12 @@ func (s Store) GetTransactionsByApp(ctx context.Context, appID string, order str
 	}
 
 	var fetchedTransactions []Transaction
-	var count = 0
 	for rows.Next() {
-		if count != 0 {
-			var transaction = Transaction{}
-			if err := rows.ScanDoc(&transaction); err != nil {
-				return nil, errors.Wrap(err, "unwrapping block")
-			}
-			fetchedTransactions = append(fetchedTransactions, transaction)
+		var transaction = Transaction{}
+		if err := rows.ScanDoc(&transaction); err != nil {
+			return nil, errors.Wrap(err, "unwrapping transaction")
 		}
-		count += 1
+		fetchedTransactions = append(fetchedTransactions, transaction)
 	}
 
 	if rows.Err() != nil {
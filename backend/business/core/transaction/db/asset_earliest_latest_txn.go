@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/business/data/store"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -64,15 +65,23 @@ func (s Store) getEarliestLatestAssetTransaction(ctx context.Context, assetID st
 	}
 	db := s.couchClient.DB(s.dbName)
 
+	key, err := store.ParseID(assetID)
+	if err != nil {
+		return Transaction{}, err
+	}
+
 	options := kivik.Options{
 		"include_docs": true,
 		"limit": 1,
-		"start_key": fmt.Sprintf("\"[%s, 1]\"", assetID),
 	}
 
-	if earliest == true {
+	if earliest {
+		options["start_key"] = []interface{}{key, "1"}
+		options["end_key"] = []interface{}{key, "1", map[string]interface{}{}}
 		options["descending"] = false
 	} else {
+		options["start_key"] = []interface{}{key, "1", map[string]interface{}{}}
+		options["end_key"] = []interface{}{key, "1"}
 		options["descending"] = true
 	}
 
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetTransactionCountByAsset retrieves the number of transactions an asset is
+// involved in.
+func (s Store) GetTransactionCountByAsset(ctx context.Context, assetID string) (int64, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetTransactionCountByAsset")
+	span.SetAttributes(attribute.String("assetID", assetID))
+	defer span.End()
+
+	s.log.Infow("transaction.GetTransactionCountByAsset",
+		"traceid", web.GetTraceID(ctx),
+		"assetID", assetID)
+
+	return s.countByIndex(ctx, schema.TransactionViewByAssetCount, assetID)
+}
+
+// GetTransactionsByAssetPagination retrieves a page of the transactions an
+// asset is involved in, ordered by round, position within the round and ID,
+// along with the number of pages and of transactions.
+func (s Store) GetTransactionsByAssetPagination(ctx context.Context, assetID, order string, pageNo, limit int64) ([]Transaction, int64, int64, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetTransactionsByAssetPagination")
+	span.SetAttributes(attribute.String("assetID", assetID))
+	span.SetAttributes(attribute.Int64("pageNo", pageNo))
+	span.SetAttributes(attribute.Int64("limit", limit))
+	defer span.End()
+
+	s.log.Infow("transaction.GetTransactionsByAssetPagination",
+		"traceid", web.GetTraceID(ctx),
+		"assetID", assetID,
+		"pageNo", pageNo,
+		"limit", limit)
+
+	return s.pageByIndex(ctx, schema.TransactionViewByAsset, schema.TransactionViewByAssetCount, assetID, order, pageNo, limit)
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetTransactionsByGroup retrieves the transactions of an atomic group, given
+// the base64 of its ID, ordered by round and by position within the round.
+func (s Store) GetTransactionsByGroup(ctx context.Context, groupID string) ([]Transaction, error) {
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetTransactionsByGroup")
+	span.SetAttributes(attribute.String("groupID", groupID))
+	defer span.End()
+
+	s.log.Infow("transaction.GetTransactionsByGroup",
+		"traceid", web.GetTraceID(ctx),
+		"groupID", groupID)
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return nil, errors.Wrap(err, s.dbName+" database check fails")
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/"+schema.TransactionViewByGroup, kivik.Options{
+		"include_docs": true,
+		"start_key":    []interface{}{groupID},
+		"end_key":      []interface{}{groupID, map[string]interface{}{}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch data error: %w", err)
+	}
+	defer rows.Close()
+
+	var fetchedTransactions = []Transaction{}
+	for rows.Next() {
+		var transaction = Transaction{}
+		if err := rows.ScanDoc(&transaction); err != nil {
+			return nil, errors.Wrap(err, "unwrapping transaction")
+		}
+		fetchedTransactions = append(fetchedTransactions, transaction)
+	}
+
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows error, Can't find anything")
+	}
+
+	return fetchedTransactions, nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrInnerTxnNotFound is returned when a path doesn't lead to an inner transaction.
+var ErrInnerTxnNotFound = errors.New("inner transaction not found")
+
+// GetInnerTransaction retrieves an inner transaction based upon the ID of the top-level
+// transaction it belongs to and its path. Each element of the path is the position of
+// the inner transaction among the ones issued by the transaction before it, so
+// []int{1, 0} is the first inner transaction of the second inner transaction.
+func (s Store) GetInnerTransaction(ctx context.Context, parentID string, path []int) (models.Transaction, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetInnerTransaction")
+	span.SetAttributes(attribute.String("transactionID", parentID))
+	defer span.End()
+
+	s.log.Infow("transaction.GetInnerTransaction", "traceid", web.GetTraceID(ctx), "transactionID", parentID, "path", path)
+
+	parent, err := s.GetTransaction(ctx, parentID)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	txn, ok := InnerTxnAt(parent.Transaction, path)
+	if !ok {
+		return models.Transaction{}, fmt.Errorf("transaction %s, path %v: %w", parentID, path, ErrInnerTxnNotFound)
+	}
+
+	return txn, nil
+}
+
+// InnerTxnAt walks down the inner transactions of txn following path.
+func InnerTxnAt(txn models.Transaction, path []int) (models.Transaction, bool) {
+	if len(path) == 0 {
+		return models.Transaction{}, false
+	}
+	for _, idx := range path {
+		if idx < 0 || idx >= len(txn.InnerTxns) {
+			return models.Transaction{}, false
+		}
+		txn = txn.InnerTxns[idx]
+	}
+	return txn, true
+}
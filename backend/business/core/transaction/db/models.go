@@ -1,14 +1,25 @@
 package db
 
-import "github.com/algorand/go-algorand-sdk/client/v2/common/models"
+import (
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+	app "github.com/kevguy/algosearch/backend/business/core/algod"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+)
 
 type NewTransaction struct {
 	ID *string							`json:"_id"`
+	Rev string							`json:"_rev,omitempty"`
 	models.Transaction
+	txnfields.TransactionExtras
 	DocType					string		`json:"doc_type"`
 	AssociatedAccounts		[]string	`json:"associated_accounts"`
 	AssociatedApplications	[]uint64	`json:"associated_applications"`
 	AssociatedAssets		[]uint64	`json:"associated_assets"`
+	// Pending is set until the block of the round is committed. Pending
+	// transactions are left out of every view.
+	Pending					bool		`json:"pending,omitempty"`
 }
 
 type Transaction struct {
@@ -16,3 +27,28 @@ type Transaction struct {
 	ID		string	`json:"_id,omitempty"`
 	Rev		string	`json:"_rev,omitempty"`
 }
+
+// Position returns where the transaction stands in the lists of transactions,
+// which order them by round, by position within the round and then by ID.
+func (t Transaction) Position() cursor.Position {
+	return cursor.Position{Round: t.ConfirmedRound, Offset: t.IntraRoundOffset, ID: t.ID}
+}
+
+// NewTransactionDoc builds the document a transaction is stored as, listing the
+// accounts, applications and assets it involves.
+func NewTransactionDoc(transaction models.Transaction, extras txnfields.TransactionExtras, blockInfo types.Block) NewTransaction {
+	return NewTransaction{
+		Transaction:            transaction,
+		TransactionExtras:      extras,
+		DocType:                DocType,
+		AssociatedAccounts:     app.ExtractAccountAddrs(transaction, extras),
+		AssociatedApplications: app.ExtractApplicationIdsFromTxn(transaction),
+		AssociatedAssets:       app.ExtractAssetIdsFromTxn(transaction, blockInfo),
+	}
+}
+
+// txnViewKey returns the key a transaction is stored under in the views ordering
+// transactions by round and by their position within the round.
+func txnViewKey(txn models.Transaction, id string) []interface{} {
+	return []interface{}{txn.ConfirmedRound, txn.IntraRoundOffset, id}
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+)
+
+// countByIndex counts the transactions an asset or application is involved in
+// with a query on countView, a view keyed [id, ...] reducing to a sum.
+func (s Store) countByIndex(ctx context.Context, countView, id string) (int64, error) {
+	key, err := store.ParseID(id)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.countInRange(ctx, countView, []interface{}{key}, []interface{}{key, map[string]interface{}{}})
+}
+
+// pageByIndex retrieves a page of the transactions an asset or application is
+// involved in with a query on view, a view keyed [id, "1", ...], counting them
+// with countView. It returns them along with the number of pages and of
+// transactions.
+func (s Store) pageByIndex(ctx context.Context, view, countView, id, order string, pageNo, limit int64) ([]Transaction, int64, int64, error) {
+	numOfTransactions, err := s.countByIndex(ctx, countView, id)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("get transaction count: %w", err)
+	}
+
+	// The count already checked the ID.
+	key, _ := store.ParseID(id)
+
+	// The transactions are keyed [id, "1", ...] in the view, between
+	// [id, "1"] and [id, "1", {}].
+	first := []interface{}{key, "1"}
+	last := []interface{}{key, "1", map[string]interface{}{}}
+
+	return s.pageInRange(ctx, view, first, last, numOfTransactions, order, pageNo, limit)
+}
+
+// countInRange counts the transactions keyed from first to last in countView,
+// a view reducing to a sum.
+func (s Store) countInRange(ctx context.Context, countView string, first, last []interface{}) (int64, error) {
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return 0, fmt.Errorf(s.dbName+" database check fails: %w", err)
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/"+countView, kivik.Options{
+		"start_key":     first,
+		"end_key":       last,
+		"inclusive_end": true,
+		"reduce":        true,
+		"group_level":   0,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("fetch data error: %w", err)
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		if err := rows.ScanValue(&count); err != nil {
+			return 0, fmt.Errorf("can't find anything: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return count, nil
+}
+
+// pageInRange retrieves a page of the numOfTransactions transactions keyed
+// from first to last in view, counted from the last one when order is "desc",
+// along with the number of pages and of transactions.
+func (s Store) pageInRange(ctx context.Context, view string, first, last []interface{}, numOfTransactions int64, order string, pageNo, limit int64) ([]Transaction, int64, int64, error) {
+	if limit < 1 {
+		return nil, 0, 0, fmt.Errorf("limit is less than 1")
+	}
+
+	var numOfPages = numOfTransactions / limit
+	if numOfTransactions%limit > 0 {
+		numOfPages += 1
+	}
+
+	if pageNo < 1 || pageNo > numOfPages {
+		return nil, 0, 0, fmt.Errorf("page number is less than 1 or exceeds page limit: %d", numOfPages)
+	}
+
+	// The count already checked the database.
+	db := s.couchClient.DB(s.dbName)
+
+	options := kivik.Options{
+		"include_docs": true,
+		"descending":   order == "desc",
+		"start_key":    first,
+		"end_key":      last,
+		"skip":         (pageNo - 1) * limit,
+		"limit":        limit,
+	}
+	if order == "desc" {
+		options["start_key"], options["end_key"] = last, first
+	}
+
+	rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/"+view, options)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("fetch data error: %w", err)
+	}
+	defer rows.Close()
+
+	var fetchedTransactions = []Transaction{}
+	for rows.Next() {
+		var transaction Transaction
+		if err := rows.ScanDoc(&transaction); err != nil {
+			return nil, 0, 0, fmt.Errorf("unwrapping transaction: %w", err)
+		}
+		fetchedTransactions = append(fetchedTransactions, transaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return fetchedTransactions, numOfPages, numOfTransactions, nil
+}
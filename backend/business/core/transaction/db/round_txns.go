@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// roundRange returns the first and last keys the transactions of a round can
+// have in the views keyed [round, offset, id].
+func roundRange(round uint64) ([]interface{}, []interface{}) {
+	return []interface{}{round}, []interface{}{round, map[string]interface{}{}}
+}
+
+// GetTransactionCountByRound retrieves the number of transactions confirmed in
+// a round.
+func (s Store) GetTransactionCountByRound(ctx context.Context, round uint64) (int64, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetTransactionCountByRound")
+	span.SetAttributes(attribute.Int64("round", int64(round)))
+	defer span.End()
+
+	s.log.Infow("transaction.GetTransactionCountByRound",
+		"traceid", web.GetTraceID(ctx),
+		"round", round)
+
+	first, last := roundRange(round)
+	return s.countInRange(ctx, schema.TransactionViewByIDCount, first, last)
+}
+
+// GetTransactionsByRoundPagination retrieves a page of the transactions
+// confirmed in a round, ordered by their position in the round, along with
+// the number of pages and of transactions.
+func (s Store) GetTransactionsByRoundPagination(ctx context.Context, round uint64, order string, pageNo, limit int64) ([]Transaction, int64, int64, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetTransactionsByRoundPagination")
+	span.SetAttributes(attribute.Int64("round", int64(round)))
+	span.SetAttributes(attribute.Int64("pageNo", pageNo))
+	span.SetAttributes(attribute.Int64("limit", limit))
+	defer span.End()
+
+	s.log.Infow("transaction.GetTransactionsByRoundPagination",
+		"traceid", web.GetTraceID(ctx),
+		"round", round,
+		"pageNo", pageNo,
+		"limit", limit)
+
+	first, last := roundRange(round)
+	numOfTransactions, err := s.countInRange(ctx, schema.TransactionViewByIDCount, first, last)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("get transaction count: %w", err)
+	}
+
+	return s.pageInRange(ctx, schema.TransactionViewInLatest, first, last, numOfTransactions, order, pageNo, limit)
+}
@@ -6,7 +6,8 @@ import (
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
 	"github.com/algorand/go-algorand-sdk/types"
 	"github.com/go-kivik/kivik/v4"
-	app "github.com/kevguy/algosearch/backend/business/core/algod"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
+	"github.com/kevguy/algosearch/backend/foundation/couchdb"
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -45,13 +46,7 @@ func (s Store) AddTransaction(ctx context.Context, transaction models.Transactio
 
 	s.log.Infow("transaction.AddTransaction", "traceid", web.GetTraceID(ctx))
 
-	var doc = NewTransaction{
-		Transaction:            transaction,
-		DocType:                DocType,
-		AssociatedAccounts:     app.ExtractAccountAddrsFromTxn(transaction),
-		AssociatedApplications: app.ExtractApplicationIdsFromTxn(transaction),
-		AssociatedAssets:       app.ExtractAssetIdsFromTxn(transaction, blockInfo),
-	}
+	var doc = NewTransactionDoc(transaction, txnfields.TransactionExtras{}, blockInfo)
 	//docId := fmt.Sprintf("%s.%s", DocType, doc.Id)
 	exist, err := s.couchClient.DBExists(ctx, s.dbName)
 	if err != nil || !exist {
@@ -59,7 +54,13 @@ func (s Store) AddTransaction(ctx context.Context, transaction models.Transactio
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	rev, err := db.Put(ctx, doc.Id, doc)
+	committed, err := roundCommitted(ctx, db, transaction.ConfirmedRound)
+	if err != nil {
+		return "", "", errors.Wrap(err, s.dbName+" database can't check round of transaction id "+doc.Id)
+	}
+	doc.Pending = !committed
+
+	rev, err := couchdb.Upsert(ctx, db, doc.Id, doc)
 	if err != nil {
 		return "", "", errors.Wrap(err, s.dbName+" database can't insert transaction id "+doc.Id)
 	}
@@ -68,8 +69,9 @@ func (s Store) AddTransaction(ctx context.Context, transaction models.Transactio
 
 // AddTransactions bulk-adds transactions to CouchDB.
 // It receives the []models.Transaction object and transform them into Transaction document objects and then
-// insert them into the global CouchDB table.
-func (s Store) AddTransactions(ctx context.Context, transactions []models.Transaction, blockInfo types.Block) (bool, error) {
+// insert them into the global CouchDB table. extras, when given, holds the fields of the newer transaction
+// types for each transaction.
+func (s Store) AddTransactions(ctx context.Context, transactions []models.Transaction, extras []txnfields.TransactionExtras, blockInfo types.Block) (bool, error) {
 
 	ctx, span := otel.GetTracerProvider().
 		Tracer("").
@@ -84,34 +86,34 @@ func (s Store) AddTransactions(ctx context.Context, transactions []models.Transa
 	}
 	db := s.couchClient.DB(s.dbName)
 
-	transactions_ := make([]interface{}, len(transactions))
-	//fmt.Println("Here are teh transactions")
-	//fmt.Printf("%v\n", transactions)
-
-	// https://stackoverflow.com/questions/55755929/go-convert-interface-to-map
-	// https://stackoverflow.com/questions/44094325/add-data-to-interface-in-struct
+	// Transactions stay pending until the block of their round is committed,
+	// unless it already is: saving a committed round again doesn't hide it.
+	committed := make(map[uint64]bool)
+	ids := make([]string, len(transactions))
+	docs := make([]NewTransaction, len(transactions))
 	for i := range transactions {
-		doc := NewTransaction{
-			ID:                     &transactions[i].Id,
-			Transaction:            transactions[i],
-			DocType:                DocType,
-			AssociatedAccounts:     app.ExtractAccountAddrsFromTxn(transactions[i]),
-			AssociatedApplications: app.ExtractApplicationIdsFromTxn(transactions[i]),
-			AssociatedAssets:       app.ExtractAssetIdsFromTxn(transactions[i], blockInfo),
+		round := transactions[i].ConfirmedRound
+		if _, ok := committed[round]; !ok {
+			if committed[round], err = roundCommitted(ctx, db, round); err != nil {
+				return false, errors.Wrap(err, s.dbName+" database can't check round of transactions")
+			}
+		}
+
+		ids[i] = transactions[i].Id
+		var txnExtras txnfields.TransactionExtras
+		if i < len(extras) {
+			txnExtras = extras[i]
 		}
-		transactions_[i] = doc
-		//fmt.Println("YYYYYYYYYY")
-		//fmt.Printf("%v\n", transactions_[i])
-		//v, _ := transactions_[i].(map[string]interface{})
-		//fmt.Println("VVVVVVVV")
-		//fmt.Printf("%v\n", v)
-		//v["_id"] = transactions[i].Id
-		//transactions_[i] = v
-		//fmt.Println("looping")
-		//fmt.Println(transactions_[i])
+		docs[i] = NewTransactionDoc(transactions[i], txnExtras, blockInfo)
+		docs[i].ID = &ids[i]
+		docs[i].Pending = !committed[round]
 	}
 
-	_, err = db.BulkDocs(ctx, transactions_)
+	err = couchdb.BulkUpsert(ctx, db, ids, func(i int, rev string) interface{} {
+		doc := docs[i]
+		doc.Rev = rev
+		return doc
+	})
 	if err != nil {
 		return false, errors.Wrap(err, "Can't bulk insert the transactions")
 	}
@@ -120,7 +122,7 @@ func (s Store) AddTransactions(ctx context.Context, transactions []models.Transa
 }
 
 // GetTransaction retrieves a transaction record from CouchDB based upon the transaction ID given.
-func (s Store) GetTransaction(ctx context.Context, transactionID string) (models.Transaction, error) {
+func (s Store) GetTransaction(ctx context.Context, transactionID string) (Transaction, error) {
 
 	ctx, span := otel.GetTracerProvider().
 		Tracer("").
@@ -132,7 +134,7 @@ func (s Store) GetTransaction(ctx context.Context, transactionID string) (models
 
 	exist, err := s.couchClient.DBExists(ctx, s.dbName)
 	if err != nil || !exist {
-		return models.Transaction{}, errors.Wrap(err, s.dbName+" database check fails")
+		return Transaction{}, errors.Wrap(err, s.dbName+" database check fails")
 	}
 	db := s.couchClient.DB(s.dbName)
 
@@ -141,16 +143,19 @@ func (s Store) GetTransaction(ctx context.Context, transactionID string) (models
 	//docId := fmt.Sprintf("%s.%s", DocType, transactionID)
 	row := db.Get(ctx, transactionID)
 	if row == nil {
-		return models.Transaction{}, errors.Wrap(err, s.dbName+" get data empty")
+		return Transaction{}, errors.Wrap(err, s.dbName+" get data empty")
 	}
 
 	var transaction Transaction
 	//fmt.Printf("%v\n", row)
 	err = row.ScanDoc(&transaction)
 	if err != nil {
-		return models.Transaction{}, errors.Wrap(err, s.dbName+"cannot unpack data from row")
+		return Transaction{}, errors.Wrap(err, s.dbName+"cannot unpack data from row")
+	}
+	if transaction.Pending {
+		return Transaction{}, ErrNotCommitted
 	}
 	//fmt.Println(transaction)
 
-	return transaction.Transaction, nil
+	return transaction, nil
 }
@@ -8,7 +8,6 @@ import (
 	"github.com/kevguy/algosearch/backend/foundation/web"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"strconv"
 )
 
 // GetTransactionCountBtnKeys gets the count between a transaction and another. The transactions are arranged
@@ -40,18 +39,16 @@ func (s Store) GetTransactionCountBtnKeys(ctx context.Context, startKey, endKey
 	if err != nil {
 		return 0, fmt.Errorf("fetch earliest transaction error: %w", err)
 	}
-	earliestRoundTime := earliestTxn.RoundTime
 
 	latestTxn, err := s.GetTransaction(ctx, endKey)
 	if err != nil {
 		return 0, fmt.Errorf("fetch latest transaction error: %w", err)
 	}
-	latestRoundTime := latestTxn.RoundTime
 
 	// https://github.com/go-kivik/kivik/issues/246
 	rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/" +schema.TransactionViewByIDCount, kivik.Options{
-		"start_key": []string{strconv.FormatUint(earliestRoundTime, 10), startKey},
-		"end_key": []string{strconv.FormatUint(latestRoundTime, 10), endKey},
+		"start_key": txnViewKey(earliestTxn.Transaction, startKey),
+		"end_key": txnViewKey(latestTxn.Transaction, endKey),
 		"inclusive_end": true,
 		"reduce": true,
 		"group_level": 0,
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetTransactionsFrom retrieves up to limit transactions read from cur, or from
+// the start of the list when cur is nil, in the given order. The boolean
+// returned reports whether transactions are left past them in the direction
+// they were read in.
+func (s Store) GetTransactionsFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]Transaction, bool, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetTransactionsFrom")
+	span.SetAttributes(attribute.Int64("limit", limit))
+	defer span.End()
+
+	s.log.Infow("transaction.GetTransactionsFrom",
+		"traceid", web.GetTraceID(ctx),
+		"cursor", cur,
+		"limit", limit)
+
+	descending := cur.Descending(order)
+	options := kivik.Options{
+		"descending": descending,
+	}
+	if cur != nil {
+		options["start_key"] = []interface{}{cur.Round, cur.Offset, cur.ID}
+	}
+
+	return s.transactionsFrom(ctx, schema.TransactionViewInLatest, options, cur, descending, limit)
+}
+
+// GetTransactionsByAcctFrom retrieves up to limit transactions of an account
+// read from cur, or from the start of the list when cur is nil, in the given
+// order. The boolean returned reports whether transactions are left past them
+// in the direction they were read in.
+func (s Store) GetTransactionsByAcctFrom(ctx context.Context, acctID string, cur *cursor.Cursor, order string, limit int64) ([]Transaction, bool, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetTransactionsByAcctFrom")
+	span.SetAttributes(attribute.String("acctID", acctID))
+	span.SetAttributes(attribute.Int64("limit", limit))
+	defer span.End()
+
+	s.log.Infow("transaction.GetTransactionsByAcctFrom",
+		"traceid", web.GetTraceID(ctx),
+		"acctID", acctID,
+		"cursor", cur,
+		"limit", limit)
+
+	// The transactions of the account are keyed [acctID, "1", ...] in the view
+	// on accounts, between [acctID, "1"] and [acctID, "1", {}].
+	first := []interface{}{acctID, "1"}
+	last := []interface{}{acctID, "1", map[string]interface{}{}}
+
+	descending := cur.Descending(order)
+	options := kivik.Options{
+		"descending": descending,
+		"start_key":  first,
+		"end_key":    last,
+	}
+	if descending {
+		options["start_key"], options["end_key"] = last, first
+	}
+	if cur != nil {
+		options["start_key"] = []interface{}{acctID, "1", cur.Round, cur.Offset, cur.ID}
+	}
+
+	return s.transactionsFrom(ctx, schema.TransactionViewByAccount, options, cur, descending, limit)
+}
+
+// transactionsFrom reads up to limit transactions from cur with a query on
+// view, leaving out the transaction at the cursor, and puts them back in the
+// order of the list when they were read backwards.
+func (s Store) transactionsFrom(ctx context.Context, view string, options kivik.Options, cur *cursor.Cursor, descending bool, limit int64) ([]Transaction, bool, error) {
+	if limit < 1 {
+		return nil, false, cursor.ErrLimit
+	}
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return nil, false, fmt.Errorf(s.dbName+" database check fails: %w", err)
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	// The transaction at the cursor is read too when it still exists, so one
+	// more than needed to tell whether transactions are left is asked for.
+	options["include_docs"] = true
+	options["limit"] = limit + 2
+
+	rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/"+view, options)
+	if err != nil {
+		return nil, false, fmt.Errorf("fetch data error: %w", err)
+	}
+	defer rows.Close()
+
+	var fetchedTransactions = []Transaction{}
+	for rows.Next() {
+		var transaction Transaction
+		if err := rows.ScanDoc(&transaction); err != nil {
+			return nil, false, fmt.Errorf("unwrapping transaction: %w", err)
+		}
+		if cur.Includes(transaction.Position(), descending) {
+			fetchedTransactions = append(fetchedTransactions, transaction)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+
+	more := int64(len(fetchedTransactions)) > limit
+	if more {
+		fetchedTransactions = fetchedTransactions[:limit]
+	}
+	if cur != nil && cur.Before {
+		for i, j := 0, len(fetchedTransactions)-1; i < j; i, j = i+1, j-1 {
+			fetchedTransactions[i], fetchedTransactions[j] = fetchedTransactions[j], fetchedTransactions[i]
+		}
+	}
+
+	return fetchedTransactions, more, nil
+}
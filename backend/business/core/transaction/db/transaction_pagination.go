@@ -8,7 +8,6 @@ import (
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"strconv"
 )
 
 
@@ -42,7 +41,6 @@ func (s Store) GetTransactionsPagination(ctx context.Context, startTransactionId
 		return nil, 0, 0, errors.Wrap(err, ": Get latest synced transaction id")
 	}
 	latestTxnID := latestTxn.ID
-	latestRoundTime := latestTxn.RoundTime
 
 	// Get the start transaction id
 	//startTxn, err := s.GetTransaction(ctx, startTransactionId)
@@ -83,7 +81,7 @@ func (s Store) GetTransactionsPagination(ctx context.Context, startTransactionId
 		options["descending"] = true
 
 		// Start with latest block number we managed to find for the time being
-		options["start_key"] = []string{strconv.FormatUint(latestRoundTime, 10), latestTxnID}
+		options["start_key"] = txnViewKey(latestTxn.Transaction, latestTxnID)
 
 		// Use page number to calculate number of items to skip
 		skip := (pageNo - 1) * limit
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// prunePageSize is the number of transactions deleted per bulk request while pruning.
+const prunePageSize = 500
+
+// DeleteTransactionsBefore deletes every transaction confirmed before round and
+// returns how many were deleted.
+func (s Store) DeleteTransactionsBefore(ctx context.Context, round uint64) (int, error) {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.DeleteTransactionsBefore")
+	span.SetAttributes(attribute.Int64("round", int64(round)))
+	defer span.End()
+
+	s.log.Infow("transaction.DeleteTransactionsBefore", "traceid", web.GetTraceID(ctx), "round", round)
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return 0, fmt.Errorf(s.dbName+" database check fails: %w", err)
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	// Pending transactions are left out of the view on every transaction, so
+	// those of rounds that were never committed are deleted as well.
+	deleted, err := deleteTransactions(ctx, db, schema.TransactionViewInLatest, []interface{}{round})
+	if err != nil {
+		return deleted, fmt.Errorf(s.dbName+" database can't delete transactions before round %d: %w", round, err)
+	}
+	deletedPending, err := deleteTransactions(ctx, db, schema.TransactionViewPendingByRound, round)
+	deleted += deletedPending
+	if err != nil {
+		return deleted, fmt.Errorf(s.dbName+" database can't delete pending transactions before round %d: %w", round, err)
+	}
+
+	return deleted, nil
+}
+
+// deleteTransactions deletes the transactions of view keyed before endKey, a
+// page at a time, and returns how many were deleted.
+func deleteTransactions(ctx context.Context, db *kivik.DB, view string, endKey interface{}) (int, error) {
+	var deleted int
+	for {
+		rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/"+view, kivik.Options{
+			"include_docs":  true,
+			"endkey":        endKey,
+			"inclusive_end": false,
+			"limit":         prunePageSize,
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("fetch data error: %w", err)
+		}
+
+		var docs []interface{}
+		for rows.Next() {
+			var doc struct {
+				ID  string `json:"_id"`
+				Rev string `json:"_rev"`
+			}
+			if err := rows.ScanDoc(&doc); err != nil {
+				return deleted, fmt.Errorf("unwrapping transaction: %w", err)
+			}
+			docs = append(docs, map[string]interface{}{
+				"_id":      doc.ID,
+				"_rev":     doc.Rev,
+				"_deleted": true,
+			})
+		}
+		if rows.Err() != nil {
+			return deleted, fmt.Errorf("rows error: %w", rows.Err())
+		}
+
+		if len(docs) == 0 {
+			return deleted, nil
+		}
+		if _, err := db.BulkDocs(ctx, docs); err != nil {
+			return deleted, err
+		}
+		deleted += len(docs)
+
+		if len(docs) < prunePageSize {
+			return deleted, nil
+		}
+	}
+}
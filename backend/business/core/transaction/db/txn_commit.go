@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/couchdb"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrNotCommitted is returned when a transaction is stored but the block of its
+// round is not committed yet.
+var ErrNotCommitted = errors.New("transaction is not committed yet")
+
+// CommitTransactions marks the pending transactions of round as committed. It
+// is called right before the block of the round is committed, and makes the
+// transactions visible.
+func (s Store) CommitTransactions(ctx context.Context, round uint64) error {
+
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.CommitTransactions")
+	span.SetAttributes(attribute.Int64("round", int64(round)))
+	defer span.End()
+
+	s.log.Infow("transaction.CommitTransactions", "traceid", web.GetTraceID(ctx), "round", round)
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return fmt.Errorf(s.dbName+" database check fails: %w", err)
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/"+schema.TransactionViewPendingByRound, kivik.Options{
+		"include_docs": true,
+		"key":          round,
+	})
+	if err != nil {
+		return fmt.Errorf("fetch data error: %w", err)
+	}
+	defer rows.Close()
+
+	// The transactions are read and written back as is, so no field is lost
+	// on the way.
+	var ids []string
+	var docs []map[string]interface{}
+	for rows.Next() {
+		var doc map[string]interface{}
+		if err := rows.ScanDoc(&doc); err != nil {
+			return fmt.Errorf("unwrapping transaction: %w", err)
+		}
+		delete(doc, "pending")
+		ids = append(ids, rows.ID())
+		docs = append(docs, doc)
+	}
+	if rows.Err() != nil {
+		return fmt.Errorf("rows error: %w", rows.Err())
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	err = couchdb.BulkUpsert(ctx, db, ids, func(i int, rev string) interface{} {
+		doc := docs[i]
+		doc["_rev"] = rev
+		return doc
+	})
+	if err != nil {
+		return fmt.Errorf(s.dbName+" database can't commit the transactions of round %d: %w", round, err)
+	}
+	return nil
+}
+
+// roundCommitted reports whether the block of round is committed, in which case
+// the transactions saved again for the round are committed right away.
+func roundCommitted(ctx context.Context, db *kivik.DB, round uint64) (bool, error) {
+	rows, err := db.Query(ctx, schema.BlockDDoc, "_view/"+schema.BlockViewByRoundNo, kivik.Options{
+		"key":   round,
+		"limit": 1,
+	})
+	if err != nil {
+		return false, fmt.Errorf("fetch data error: %w", err)
+	}
+	defer rows.Close()
+
+	committed := rows.Next()
+	if rows.Err() != nil {
+		return false, fmt.Errorf("rows error: %w", rows.Err())
+	}
+	return committed, nil
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/data/schema"
+	"github.com/kevguy/algosearch/backend/foundation/web"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetTransactionsByType retrieves up to limit transactions of the given type (e.g. pay, appl, stpf),
+// ordered by round and by position within the round.
+func (s Store) GetTransactionsByType(ctx context.Context, txnType string, order string, limit int64) ([]Transaction, error) {
+	ctx, span := otel.GetTracerProvider().
+		Tracer("").
+		Start(ctx, "transaction.GetTransactionsByType")
+	span.SetAttributes(attribute.String("txnType", txnType))
+	span.SetAttributes(attribute.Int64("limit", limit))
+	defer span.End()
+
+	s.log.Infow("transaction.GetTransactionsByType",
+		"traceid", web.GetTraceID(ctx),
+		"txnType", txnType,
+		"limit", limit)
+
+	exist, err := s.couchClient.DBExists(ctx, s.dbName)
+	if err != nil || !exist {
+		return nil, errors.Wrap(err, s.dbName+" database check fails")
+	}
+	db := s.couchClient.DB(s.dbName)
+
+	// An empty object sorts after every number and string in a view key.
+	first := []interface{}{txnType}
+	last := []interface{}{txnType, map[string]interface{}{}}
+
+	options := kivik.Options{
+		"include_docs": true,
+		"limit":        limit,
+	}
+	if order == "asc" {
+		options["start_key"] = first
+		options["end_key"] = last
+		options["descending"] = false
+	} else {
+		// assuming it's "desc"
+		options["start_key"] = last
+		options["end_key"] = first
+		options["descending"] = true
+	}
+
+	rows, err := db.Query(ctx, schema.TransactionDDoc, "_view/"+schema.TransactionViewByType, options)
+	if err != nil {
+		return nil, fmt.Errorf("fetch data error: %w", err)
+	}
+
+	var fetchedTransactions = []Transaction{}
+	for rows.Next() {
+		var transaction = Transaction{}
+		if err := rows.ScanDoc(&transaction); err != nil {
+			return nil, errors.Wrap(err, "unwrapping transaction")
+		}
+		fetchedTransactions = append(fetchedTransactions, transaction)
+	}
+
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows error, Can't find anything")
+	}
+
+	return fetchedTransactions, nil
+}
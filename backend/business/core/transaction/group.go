@@ -0,0 +1,84 @@
+package transaction
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kevguy/algosearch/backend/business/core/transaction/db"
+)
+
+// Set of error variables for looking up atomic groups.
+var (
+	ErrInvalidGroupID = errors.New("group ID is not the base64 of 32 bytes")
+	ErrGroupNotFound  = errors.New("group not found")
+)
+
+// Group is an atomic transaction group: its members in the order they were
+// confirmed in, the fees they paid in total and the accounts taking part.
+type Group struct {
+	ID           string           `json:"id"`
+	Round        uint64           `json:"round"`
+	NumOfTxns    int              `json:"num_of_txns"`
+	TotalFees    uint64           `json:"total_fees"`
+	Accounts     []string         `json:"accounts"`
+	Transactions []db.Transaction `json:"transactions"`
+}
+
+// NormalizeGroupID returns the standard base64 of a group ID, the form the
+// transactions hold it in. The ID can also be given in URL-safe base64, with
+// or without padding, since standard base64 can't be used in a path as is.
+func NormalizeGroupID(groupID string) (string, error) {
+	groupID = strings.TrimRight(groupID, "=")
+	for _, enc := range []*base64.Encoding{base64.RawStdEncoding, base64.RawURLEncoding} {
+		raw, err := enc.DecodeString(groupID)
+		if err == nil && len(raw) == 32 {
+			return base64.StdEncoding.EncodeToString(raw), nil
+		}
+	}
+	return "", ErrInvalidGroupID
+}
+
+// GetGroup retrieves the atomic group with the given ID.
+func (c Core) GetGroup(ctx context.Context, groupID string) (Group, error) {
+	id, err := NormalizeGroupID(groupID)
+	if err != nil {
+		return Group{}, err
+	}
+
+	txns, err := c.store.GetTransactionsByGroup(ctx, id)
+	if err != nil {
+		return Group{}, fmt.Errorf("get transactions of group %s: %w", id, err)
+	}
+	if len(txns) == 0 {
+		return Group{}, ErrGroupNotFound
+	}
+
+	return groupOf(id, txns), nil
+}
+
+// groupOf sums up the members of a group.
+func groupOf(id string, txns []db.Transaction) Group {
+	group := Group{
+		ID:           id,
+		Round:        txns[0].ConfirmedRound,
+		NumOfTxns:    len(txns),
+		Accounts:     []string{},
+		Transactions: txns,
+	}
+
+	seen := make(map[string]bool)
+	for _, txn := range txns {
+		group.TotalFees += txn.Fee
+		for _, acct := range txn.AssociatedAccounts {
+			if !seen[acct] {
+				seen[acct] = true
+				group.Accounts = append(group.Accounts, acct)
+			}
+		}
+	}
+
+	return group
+}
@@ -8,12 +8,50 @@ import (
 	"github.com/algorand/go-algorand-sdk/types"
 	"github.com/go-kivik/kivik/v4"
 	"github.com/kevguy/algosearch/backend/business/core/transaction/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
 	"go.uber.org/zap"
 )
 
+// Storer declares the behavior the transaction core needs from a storage backend.
+// The CouchDB store in the db package is the default one.
+type Storer interface {
+	AddTransaction(ctx context.Context, transaction models.Transaction, blockInfo types.Block) (string, string, error)
+	AddTransactions(ctx context.Context, transactions []models.Transaction, extras []txnfields.TransactionExtras, blockInfo types.Block) (bool, error)
+	GetTransaction(ctx context.Context, transactionID string) (db.Transaction, error)
+	GetInnerTransaction(ctx context.Context, parentID string, path []int) (models.Transaction, error)
+	GetTransactionCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error)
+	GetEarliestTransaction(ctx context.Context) (db.Transaction, error)
+	GetLatestTransaction(ctx context.Context) (db.Transaction, error)
+	GetTransactionsPagination(ctx context.Context, startTransactionID, order string, pageNo, limit int64) ([]db.Transaction, int64, int64, error)
+	GetTransactionsFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]db.Transaction, bool, error)
+	GetTransactionCountByRound(ctx context.Context, round uint64) (int64, error)
+	GetTransactionsByRoundPagination(ctx context.Context, round uint64, order string, pageNo, limit int64) ([]db.Transaction, int64, int64, error)
+	GetEarliestAcctTransaction(ctx context.Context, acctID string) (db.Transaction, error)
+	GetLatestAcctTransaction(ctx context.Context, acctID string) (db.Transaction, error)
+	GetTransactionCountByAcct(ctx context.Context, acctID, startKey, endKey string) (int64, error)
+	GetTransactionsByAcctPagination(ctx context.Context, acctID, order string, pageNo, limit int64) ([]db.Transaction, int64, int64, error)
+	GetTransactionsByAcctFrom(ctx context.Context, acctID string, cur *cursor.Cursor, order string, limit int64) ([]db.Transaction, bool, error)
+	GetTransactionsByAcct(ctx context.Context, acctID string, order string) ([]db.Transaction, error)
+	GetEarliestAppTransaction(ctx context.Context, appID string) (db.Transaction, error)
+	GetLatestAppTransaction(ctx context.Context, appID string) (db.Transaction, error)
+	GetTransactionsByApp(ctx context.Context, appID string, order string) ([]db.Transaction, error)
+	GetTransactionCountByApp(ctx context.Context, appID string) (int64, error)
+	GetTransactionsByAppPagination(ctx context.Context, appID, order string, pageNo, limit int64) ([]db.Transaction, int64, int64, error)
+	GetEarliestAssetTransaction(ctx context.Context, assetID string) (db.Transaction, error)
+	GetLatestAssetTransaction(ctx context.Context, assetID string) (db.Transaction, error)
+	GetTransactionsByAsset(ctx context.Context, assetID string, order string) ([]db.Transaction, error)
+	GetTransactionCountByAsset(ctx context.Context, assetID string) (int64, error)
+	GetTransactionsByAssetPagination(ctx context.Context, assetID, order string, pageNo, limit int64) ([]db.Transaction, int64, int64, error)
+	GetTransactionsByType(ctx context.Context, txnType string, order string, limit int64) ([]db.Transaction, error)
+	GetTransactionsByGroup(ctx context.Context, groupID string) ([]db.Transaction, error)
+	DeleteTransactionsBefore(ctx context.Context, round uint64) (int, error)
+	CommitTransactions(ctx context.Context, round uint64) error
+}
+
 // Core manages the set of API's for transaction access.
 type Core struct {
-	store db.Store
+	store Storer
 }
 
 // NewCore constructs a core for transaction api access.
@@ -23,18 +61,30 @@ func NewCore(log *zap.SugaredLogger, couchClient *kivik.Client, dbName string) C
 	}
 }
 
+// NewCoreWithStore constructs a core for transaction api access backed by the given
+// store.
+func NewCoreWithStore(store Storer) Core {
+	return Core{
+		store: store,
+	}
+}
+
 func (c Core) AddTransaction(ctx context.Context, transaction models.Transaction, blockInfo types.Block) (string, string, error) {
 	return c.store.AddTransaction(ctx, transaction, blockInfo)
 }
 
-func (c Core) AddTransactions(ctx context.Context, transactions []models.Transaction, blockInfo types.Block) (bool, error) {
-	return c.store.AddTransactions(ctx, transactions, blockInfo)
+func (c Core) AddTransactions(ctx context.Context, transactions []models.Transaction, extras []txnfields.TransactionExtras, blockInfo types.Block) (bool, error) {
+	return c.store.AddTransactions(ctx, transactions, extras, blockInfo)
 }
 
-func (c Core) GetTransaction(ctx context.Context, transactionID string) (models.Transaction, error) {
+func (c Core) GetTransaction(ctx context.Context, transactionID string) (db.Transaction, error) {
 	return c.store.GetTransaction(ctx, transactionID)
 }
 
+func (c Core) GetInnerTransaction(ctx context.Context, parentID string, path []int) (models.Transaction, error) {
+	return c.store.GetInnerTransaction(ctx, parentID, path)
+}
+
 func (c Core) GetTransactionCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
 	return c.store.GetTransactionCountBtnKeys(ctx, startKey, endKey)
 }
@@ -51,6 +101,26 @@ func (c Core) GetTransactionsPagination(ctx context.Context, startTransactionID,
 	return c.store.GetTransactionsPagination(ctx, startTransactionID, order, pageNo, limit)
 }
 
+// GetTransactionsByCursor retrieves up to limit transactions read from cur, or
+// from the start of the list when cur is nil, along with the links to the
+// pages on either side of them.
+func (c Core) GetTransactionsByCursor(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]db.Transaction, cursor.Links, error) {
+	txns, more, err := c.store.GetTransactionsFrom(ctx, cur, order, limit)
+	if err != nil {
+		return nil, cursor.Links{}, err
+	}
+
+	return txns, linksOf(cur, txns, more), nil
+}
+
+func (c Core) GetTransactionCountByRound(ctx context.Context, round uint64) (int64, error) {
+	return c.store.GetTransactionCountByRound(ctx, round)
+}
+
+func (c Core) GetTransactionsByRoundPagination(ctx context.Context, round uint64, order string, pageNo, limit int64) ([]db.Transaction, int64, int64, error) {
+	return c.store.GetTransactionsByRoundPagination(ctx, round, order, pageNo, limit)
+}
+
 func (c Core) GetEarliestAcctTransaction(ctx context.Context, acctID string) (db.Transaction, error) {
 	return c.store.GetEarliestAcctTransaction(ctx, acctID)
 }
@@ -67,6 +137,18 @@ func (c Core) GetTransactionsByAcctPagination(ctx context.Context, acctID, order
 	return c.store.GetTransactionsByAcctPagination(ctx, acctID, order, pageNo, limit)
 }
 
+// GetTransactionsByAcctCursor retrieves up to limit transactions of an account
+// read from cur, or from the start of the list when cur is nil, along with the
+// links to the pages on either side of them.
+func (c Core) GetTransactionsByAcctCursor(ctx context.Context, acctID string, cur *cursor.Cursor, order string, limit int64) ([]db.Transaction, cursor.Links, error) {
+	txns, more, err := c.store.GetTransactionsByAcctFrom(ctx, acctID, cur, order, limit)
+	if err != nil {
+		return nil, cursor.Links{}, err
+	}
+
+	return txns, linksOf(cur, txns, more), nil
+}
+
 func (c Core) GetTransactionsByAcct(ctx context.Context, acctID string, order string) ([]db.Transaction, error) {
 	return c.store.GetTransactionsByAcct(ctx, acctID, order)
 }
@@ -83,6 +165,14 @@ func (c Core) GetTransactionsByApp(ctx context.Context, appID string, order stri
 	return c.store.GetTransactionsByApp(ctx, appID, order)
 }
 
+func (c Core) GetTransactionCountByApp(ctx context.Context, appID string) (int64, error) {
+	return c.store.GetTransactionCountByApp(ctx, appID)
+}
+
+func (c Core) GetTransactionsByAppPagination(ctx context.Context, appID, order string, pageNo, limit int64) ([]db.Transaction, int64, int64, error) {
+	return c.store.GetTransactionsByAppPagination(ctx, appID, order, pageNo, limit)
+}
+
 func (c Core) GetEarliestAssetTransaction(ctx context.Context, assetID string) (db.Transaction, error) {
 	return c.store.GetEarliestAssetTransaction(ctx, assetID)
 }
@@ -94,3 +184,34 @@ func (c Core) GetLatestAssetTransaction(ctx context.Context, assetID string) (db
 func (c Core) GetTransactionsByAsset(ctx context.Context, assetID string, order string) ([]db.Transaction, error) {
 	return c.store.GetTransactionsByAsset(ctx, assetID, order)
 }
+
+func (c Core) GetTransactionCountByAsset(ctx context.Context, assetID string) (int64, error) {
+	return c.store.GetTransactionCountByAsset(ctx, assetID)
+}
+
+func (c Core) GetTransactionsByAssetPagination(ctx context.Context, assetID, order string, pageNo, limit int64) ([]db.Transaction, int64, int64, error) {
+	return c.store.GetTransactionsByAssetPagination(ctx, assetID, order, pageNo, limit)
+}
+
+func (c Core) GetTransactionsByType(ctx context.Context, txnType string, order string, limit int64) ([]db.Transaction, error) {
+	return c.store.GetTransactionsByType(ctx, txnType, order, limit)
+}
+
+func (c Core) DeleteTransactionsBefore(ctx context.Context, round uint64) (int, error) {
+	return c.store.DeleteTransactionsBefore(ctx, round)
+}
+
+// CommitTransactions makes the transactions of a round visible. Transactions
+// saved while the block of their round is pending stay hidden until then.
+func (c Core) CommitTransactions(ctx context.Context, round uint64) error {
+	return c.store.CommitTransactions(ctx, round)
+}
+
+// linksOf returns the links to the pages on either side of a page of
+// transactions read from cur.
+func linksOf(cur *cursor.Cursor, txns []db.Transaction, more bool) cursor.Links {
+	if len(txns) == 0 {
+		return cursor.Links{}
+	}
+	return cursor.LinksOf(cur, txns[0].Position(), txns[len(txns)-1].Position(), more)
+}
@@ -0,0 +1,37 @@
+// Package txnfields holds the fields of the transaction types that are newer
+// than the SDK's transaction model, shared by blocks and transactions.
+package txnfields
+
+// TransactionExtras holds the fields of the transaction types that are newer than
+// the SDK's transaction model.
+type TransactionExtras struct {
+	StateProofTransaction *TransactionStateProof `json:"state-proof-transaction,omitempty"`
+	HeartbeatTransaction  *TransactionHeartbeat  `json:"heartbeat-transaction,omitempty"`
+}
+
+// TransactionStateProof represents the fields of a state proof (stpf) transaction.
+type TransactionStateProof struct {
+	StateProofType uint64 `json:"state-proof-type"`
+	// StateProof is the msgpack encoded proof.
+	StateProof []byte            `json:"state-proof,omitempty"`
+	Message    StateProofMessage `json:"message"`
+}
+
+// StateProofMessage represents the message a state proof attests to.
+type StateProofMessage struct {
+	BlockHeadersCommitment []byte `json:"block-headers-commitment"`
+	VotersCommitment       []byte `json:"voters-commitment"`
+	LnProvenWeight         uint64 `json:"ln-proven-weight"`
+	FirstAttestedRound     uint64 `json:"first-attested-round"`
+	LastAttestedRound      uint64 `json:"latest-attested-round"`
+}
+
+// TransactionHeartbeat represents the fields of a heartbeat (hb) transaction.
+type TransactionHeartbeat struct {
+	HbAddress     string `json:"hb-address"`
+	HbKeyDilution uint64 `json:"hb-key-dilution"`
+	HbSeed        []byte `json:"hb-seed"`
+	HbVoteID      []byte `json:"hb-vote-id"`
+	// HbProof is the msgpack encoded proof of participation key possession.
+	HbProof []byte `json:"hb-proof,omitempty"`
+}
@@ -0,0 +1,120 @@
+// Package cursor provides opaque tokens marking where a page of a list ends, so
+// the page next to it can be read from there instead of skipping every item
+// before it. Unlike page numbers, cursors keep pointing at the same items while
+// new rounds are added to the list.
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Position is where an item stands in a list. Blocks are listed by round and
+// document ID, transactions by round, position within the round and ID, and
+// accounts by address, kept in ID. The fields a list doesn't use are left
+// zero.
+type Position struct {
+	Round  uint64 `json:"r,omitempty"`
+	Offset uint64 `json:"o,omitempty"`
+	ID     string `json:"i,omitempty"`
+}
+
+// Less reports whether p comes before q in ascending order.
+func (p Position) Less(q Position) bool {
+	if p.Round != q.Round {
+		return p.Round < q.Round
+	}
+	if p.Offset != q.Offset {
+		return p.Offset < q.Offset
+	}
+	return p.ID < q.ID
+}
+
+// Cursor marks where to read a page of a list from: the items after Position
+// in the order the list is read in, or the items before it when Before is set.
+// A nil cursor reads from the start of the list.
+type Cursor struct {
+	Position
+	Before bool `json:"b,omitempty"`
+}
+
+// Descending reports whether the items of a list in the given order are read
+// from c in descending order. Pages before a cursor are read backwards, then
+// put back in the order of the list.
+func (c *Cursor) Descending(order string) bool {
+	descending := order == "desc"
+	if c != nil && c.Before {
+		return !descending
+	}
+	return descending
+}
+
+// Includes reports whether an item at p is read from c, when reading in
+// descending order if descending is set: whether p lies past the position of
+// c. Every item is read from a nil cursor.
+func (c *Cursor) Includes(p Position, descending bool) bool {
+	if c == nil {
+		return true
+	}
+	if descending {
+		return p.Less(c.Position)
+	}
+	return c.Position.Less(p)
+}
+
+// Encode returns the opaque token of c.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a token returned by Encode.
+func Decode(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor %q: %w", token, err)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor %q: %w", token, err)
+	}
+
+	return &c, nil
+}
+
+// ErrLimit is returned when a page is asked for with a limit less than 1.
+var ErrLimit = errors.New("limit is less than 1")
+
+// Links holds the tokens of the pages on either side of a page, left empty
+// when there is no such page.
+type Links struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// Around returns the links of a page whose first and last items are at first
+// and last, given whether there are pages before and after it.
+func Around(first, last Position, hasPrev, hasNext bool) Links {
+	var links Links
+	if hasNext {
+		links.Next = Cursor{Position: last}.Encode()
+	}
+	if hasPrev {
+		links.Prev = Cursor{Position: first, Before: true}.Encode()
+	}
+	return links
+}
+
+// LinksOf returns the links of a page read from c whose first and last items
+// are at first and last. more reports whether items are left past the page in
+// the direction it was read in.
+func LinksOf(c *Cursor, first, last Position, more bool) Links {
+	hasPrev, hasNext := c != nil, more
+	if c != nil && c.Before {
+		hasPrev, hasNext = more, true
+	}
+	return Around(first, last, hasPrev, hasNext)
+}
@@ -1,3 +1,11 @@
 DELETE FROM sales;
 DELETE FROM products;
-DELETE FROM users;
\ No newline at end of file
+DELETE FROM users;
+DELETE FROM transactions;
+DELETE FROM blocks;
+DELETE FROM accounts;
+DELETE FROM assets;
+DELETE FROM applications;
+DELETE FROM sync_state;
+DELETE FROM failed_rounds;
+DELETE FROM sync_lease;
@@ -41,3 +41,136 @@ CREATE TABLE sales (
 	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
 	FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE
 );
+
+-- Version: 1.4
+-- Description: Create table blocks
+CREATE TABLE blocks (
+	doc_id     TEXT COLLATE "C",
+	round      BIGINT,
+	block_time BIGINT,
+	pending    BOOLEAN,
+	revision   INT,
+	doc        JSONB,
+
+	PRIMARY KEY (doc_id)
+);
+CREATE INDEX blocks_round_idx ON blocks (round, doc_id) WHERE NOT pending;
+
+-- Version: 1.5
+-- Description: Create table transactions
+CREATE TABLE transactions (
+	transaction_id     TEXT COLLATE "C",
+	round              BIGINT,
+	intra_round_offset BIGINT,
+	round_time         BIGINT,
+	tx_type            TEXT,
+	pending            BOOLEAN,
+	revision           INT,
+	doc                JSONB,
+
+	PRIMARY KEY (transaction_id)
+);
+CREATE INDEX transactions_round_idx ON transactions (round, intra_round_offset, transaction_id);
+CREATE INDEX transactions_type_idx ON transactions (tx_type, round, intra_round_offset, transaction_id);
+
+-- Version: 1.6
+-- Description: Create tables listing the accounts, assets and applications of transactions
+CREATE TABLE transaction_accounts (
+	address            TEXT COLLATE "C",
+	transaction_id     TEXT COLLATE "C",
+	round              BIGINT,
+	intra_round_offset BIGINT,
+
+	PRIMARY KEY (address, transaction_id),
+	FOREIGN KEY (transaction_id) REFERENCES transactions(transaction_id) ON DELETE CASCADE
+);
+CREATE INDEX transaction_accounts_round_idx ON transaction_accounts (address, round, intra_round_offset, transaction_id);
+CREATE INDEX transaction_accounts_transaction_idx ON transaction_accounts (transaction_id);
+
+CREATE TABLE transaction_assets (
+	asset_id           BIGINT,
+	transaction_id     TEXT COLLATE "C",
+	round              BIGINT,
+	intra_round_offset BIGINT,
+
+	PRIMARY KEY (asset_id, transaction_id),
+	FOREIGN KEY (transaction_id) REFERENCES transactions(transaction_id) ON DELETE CASCADE
+);
+CREATE INDEX transaction_assets_round_idx ON transaction_assets (asset_id, round, intra_round_offset, transaction_id);
+CREATE INDEX transaction_assets_transaction_idx ON transaction_assets (transaction_id);
+
+CREATE TABLE transaction_applications (
+	app_id             BIGINT,
+	transaction_id     TEXT COLLATE "C",
+	round              BIGINT,
+	intra_round_offset BIGINT,
+
+	PRIMARY KEY (app_id, transaction_id),
+	FOREIGN KEY (transaction_id) REFERENCES transactions(transaction_id) ON DELETE CASCADE
+);
+CREATE INDEX transaction_applications_round_idx ON transaction_applications (app_id, round, intra_round_offset, transaction_id);
+CREATE INDEX transaction_applications_transaction_idx ON transaction_applications (transaction_id);
+
+-- Version: 1.7
+-- Description: Create tables accounts, assets and applications
+CREATE TABLE accounts (
+	address  TEXT COLLATE "C",
+	revision INT,
+	doc      JSONB,
+
+	PRIMARY KEY (address)
+);
+
+CREATE TABLE assets (
+	asset_id BIGINT,
+	revision INT,
+	doc      JSONB,
+
+	PRIMARY KEY (asset_id)
+);
+
+CREATE TABLE applications (
+	app_id   BIGINT,
+	revision INT,
+	doc      JSONB,
+
+	PRIMARY KEY (app_id)
+);
+
+-- Version: 1.8
+-- Description: Create tables sync_state, failed_rounds and sync_lease
+CREATE TABLE sync_state (
+	state_id          TEXT,
+	last_synced_round BIGINT,
+	nothing_synced    BOOLEAN,
+	revision          INT,
+	date_updated      TIMESTAMP,
+
+	PRIMARY KEY (state_id)
+);
+
+CREATE TABLE failed_rounds (
+	round           BIGINT,
+	error           TEXT,
+	attempts        INT,
+	revision        INT,
+	first_failed_at TIMESTAMP,
+	last_failed_at  TIMESTAMP,
+
+	PRIMARY KEY (round)
+);
+
+CREATE TABLE sync_lease (
+	lease_id    TEXT,
+	holder      TEXT,
+	revision    INT,
+	acquired_at TIMESTAMP,
+	renewed_at  TIMESTAMP,
+	expires_at  TIMESTAMP,
+
+	PRIMARY KEY (lease_id)
+);
+
+-- Version: 1.9
+-- Description: Index transactions by atomic group
+CREATE INDEX transactions_group_idx ON transactions ((doc->>'group'), round, intra_round_offset, transaction_id);
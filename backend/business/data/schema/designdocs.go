@@ -0,0 +1,244 @@
+package schema
+
+// DesignDocs are the design documents the database needs, with the views the
+// stores query. Bump the Version of a design document whenever one of its
+// views changes, so Migrate brings existing databases up to date.
+var DesignDocs = []DesignDoc{
+	{
+		// The views on blocks.
+		ID:      BlockDDoc,
+		Version: 1,
+		Views: map[string]View{
+			// https://docs.couchdb.org/en/main/ddocs/views/joins.html
+			BlockViewByRoundNo: {
+				Map: `function(doc) { 
+						// Pending blocks belong to rounds that are not fully saved yet.
+						if (doc.doc_type === 'block' && !doc.pending)  {
+							// emit(doc.round, {_id: doc.BlockHash});
+							emit(doc.round, null);
+						}
+					}`,
+			},
+			BlockViewByRoundCount: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'block' && !doc.pending) {
+							emit(doc.round, 1);
+						}
+					}`,
+				Reduce: "_sum",
+			},
+		},
+	},
+	{
+		// The views on transactions.
+		ID:      TransactionDDoc,
+		Version: 5,
+		Views: map[string]View{
+			// Pending transactions belong to rounds that are not fully saved
+			// yet, so only TransactionViewPendingByRound lists them.
+			TransactionViewInLatest: {
+				Map: `function(doc) { 
+						if (doc.doc_type === 'txn' && !doc.pending) {
+							// Transactions are ordered by round, then by their position in the round.
+							emit([doc["confirmed-round"] || 0, doc["intra-round-offset"] || 0, doc.id], null);
+						}
+					}`,
+			},
+			TransactionViewByID: {
+				Map: `function(doc) { 
+						if (doc.doc_type === 'txn' && !doc.pending) {
+							// emit(doc.id, {_id: doc.id});
+							emit(doc.id, null);
+						}
+					}`,
+			},
+			// https://stackoverflow.com/questions/11284383/couchdb-count-unique-document-field
+			TransactionViewByIDCount: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'txn' && !doc.pending) {
+							// emit(doc.id, 1);
+							emit([doc["confirmed-round"] || 0, doc["intra-round-offset"] || 0, doc.id], 1);
+						}
+					}`,
+				Reduce: "_sum",
+			},
+			TransactionViewByAccount: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'acct') {
+							emit([doc._id, "0"], null);
+						} else if (doc.doc_type === 'txn' && !doc.pending) {
+							doc.associated_accounts.forEach(acct => {
+								emit([acct, "1", doc["confirmed-round"] || 0, doc["intra-round-offset"] || 0, doc.id], null);
+							})
+						}
+					}`,
+			},
+			// https://stackoverflow.com/questions/13216640/couchdb-getting-number-of-keys-in-given-key-range
+			TransactionViewByAccountCount: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'txn' && !doc.pending) {
+							doc.associated_accounts.forEach(acct => {
+								emit([acct, doc["confirmed-round"] || 0, doc["intra-round-offset"] || 0, doc.id], 1);
+							})
+						}
+					}`,
+				Reduce: `function(keys, values, rereduce) {
+						return sum(values);
+					}`,
+			},
+			TransactionViewByAsset: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'asset') {
+							emit([doc._id, "0"], null);
+						} else if (doc.doc_type === 'txn' && !doc.pending) {
+							doc.associated_assets.forEach(asset => {
+								emit([asset, "1", doc["confirmed-round"] || 0, doc["intra-round-offset"] || 0, doc.id], null);
+							})
+						}
+					}`,
+			},
+			TransactionViewByAssetCount: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'txn' && !doc.pending) {
+							doc.associated_assets.forEach(asset => {
+								emit([asset, doc["confirmed-round"] || 0, doc["intra-round-offset"] || 0, doc.id], 1);
+							})
+						}
+					}`,
+				Reduce: `function(keys, values, rereduce) {
+						return sum(values);
+					}`,
+			},
+			TransactionViewByApplication: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'app') {
+							emit([doc._id, "0"], null);
+						} else if (doc.doc_type === 'txn' && !doc.pending) {
+							doc.associated_applications.forEach(app => {
+								emit([app, "1", doc["confirmed-round"] || 0, doc["intra-round-offset"] || 0, doc.id], null);
+							})
+						}
+					}`,
+			},
+			TransactionViewByType: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'txn' && !doc.pending) {
+							emit([doc["tx-type"], doc["confirmed-round"] || 0, doc["intra-round-offset"] || 0, doc.id], null);
+						}
+					}`,
+			},
+			// Only transactions of atomic groups have a group, the base64 of
+			// the group ID.
+			TransactionViewByGroup: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'txn' && !doc.pending && doc.group) {
+							emit([doc.group, doc["confirmed-round"] || 0, doc["intra-round-offset"] || 0, doc.id], null);
+						}
+					}`,
+			},
+			TransactionViewByApplicationCount: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'txn' && !doc.pending) {
+							doc.associated_applications.forEach(app => {
+								emit([app, doc["confirmed-round"] || 0, doc["intra-round-offset"] || 0, doc.id], 1);
+							})
+						}
+					}`,
+				Reduce: `function(keys, values, rereduce) {
+						return sum(values);
+					}`,
+			},
+			TransactionViewPendingByRound: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'txn' && doc.pending) {
+							emit(doc["confirmed-round"] || 0, null);
+						}
+					}`,
+			},
+		},
+	},
+	{
+		// The views on accounts.
+		ID:      AccountDDoc,
+		Version: 1,
+		Views: map[string]View{
+			AccountViewByIDInLatest: {
+				Map: `function(doc) { 
+					if (doc.doc_type === 'acct') {
+						// emit(doc.id, {_id: doc.id});
+						emit(doc._id, null);
+					}
+				}`,
+			},
+			AccountViewByIDInCount: {
+				Map: `function(doc) {
+					if (doc.doc_type === 'acct') {
+						emit(doc._id, 1);
+					}
+				}`,
+				Reduce: "_sum",
+			},
+		},
+	},
+	{
+		// The views on assets.
+		ID:      AssetDDoc,
+		Version: 2,
+		Views: map[string]View{
+			// Assets have no id field: they are keyed by index, the number
+			// their _id holds as a string.
+			AssetViewByIDInLatest: {
+				Map: `function(doc) { 
+					if (doc.doc_type === 'asset') {
+						emit(doc.index, null);
+					}
+				}`,
+			},
+			AssetViewByIDInCount: {
+				Map: `function(doc) {
+					if (doc.doc_type === 'asset') {
+						emit(doc.index, 1);
+					}
+				}`,
+				Reduce: "_sum",
+			},
+		},
+	},
+	{
+		// The views on applications.
+		ID:      ApplicationDDoc,
+		Version: 1,
+		Views: map[string]View{
+			ApplicationViewByIDInLatest: {
+				Map: `function(doc) {
+					if (doc.doc_type === 'app') {
+						// emit(doc.id, {_id: doc.id});
+						emit(doc.id, null);
+					}
+				}`,
+			},
+			ApplicationViewByIDInCount: {
+				Map: `function(doc) {
+					if (doc.doc_type === 'app') {
+						emit(doc.id, 1);
+					}
+				}`,
+				Reduce: "_sum",
+			},
+		},
+	},
+	{
+		// The views on the bookkeeping documents of the block synchronizer.
+		ID:      SyncDDoc,
+		Version: 1,
+		Views: map[string]View{
+			SyncViewFailedRoundByRoundNo: {
+				Map: `function(doc) {
+						if (doc.doc_type === 'failed_round') {
+							emit(doc.round, null);
+						}
+					}`,
+			},
+		},
+	},
+}
@@ -0,0 +1,265 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	kivik "github.com/go-kivik/kivik/v4"
+)
+
+// stagingSuffix is appended to the ID of a design document to get the ID of the
+// design document its new views are built in before they replace the old ones.
+const stagingSuffix = "_staging"
+
+// DesignDoc is the definition of a design document.
+type DesignDoc struct {
+	ID      string
+	Version int
+	Views   map[string]View
+}
+
+// View is the definition of a view, its map function and its optional reduce
+// function.
+type View struct {
+	Map    string `json:"map"`
+	Reduce string `json:"reduce,omitempty"`
+}
+
+// designDocument is a design document as stored in the database. Design
+// documents written before they were versioned have no version.
+type designDocument struct {
+	ID      string          `json:"_id"`
+	Rev     string          `json:"_rev,omitempty"`
+	Views   map[string]View `json:"views"`
+	Version int             `json:"schema_version,omitempty"`
+}
+
+// State is the state of a stored design document compared to its definition.
+type State string
+
+// The states of a stored design document.
+const (
+	StateMissing  State = "missing"
+	StateOutdated State = "outdated"
+	StateDrifted  State = "drifted"
+	StateCurrent  State = "current"
+	StateNewer    State = "newer"
+)
+
+// DesignDocStatus reports how a stored design document compares to its
+// definition.
+type DesignDocStatus struct {
+	ID            string
+	Version       int
+	StoredVersion int
+	State         State
+
+	// Staging is set while new views are built in the staging design
+	// document, before they replace the stored ones.
+	Staging bool
+}
+
+// Action describes what Migrate does with the design document.
+func (s DesignDocStatus) Action() string {
+	switch s.State {
+	case StateMissing:
+		return "create"
+	case StateOutdated, StateDrifted:
+		return "update"
+	case StateNewer:
+		return "skip, written by a newer release"
+	}
+	return "none"
+}
+
+// stateOf compares a stored design document to its definition. Design documents
+// of an older version are outdated, and the ones of the same version with
+// different views have been changed by hand and have drifted.
+func stateOf(def DesignDoc, stored designDocument) State {
+	switch {
+	case stored.Version < def.Version:
+		return StateOutdated
+	case stored.Version > def.Version:
+		return StateNewer
+	case !reflect.DeepEqual(stored.Views, def.Views):
+		return StateDrifted
+	}
+	return StateCurrent
+}
+
+// Status reports how the design documents stored in dbName compare to
+// DesignDocs, in the same order. It doesn't change the database.
+func Status(ctx context.Context, client *kivik.Client, dbName string) ([]DesignDocStatus, error) {
+	exist, err := client.DBExists(ctx, dbName)
+	if err != nil {
+		return nil, fmt.Errorf("%s database check fails: %w", dbName, err)
+	}
+	db := client.DB(dbName)
+
+	statuses := make([]DesignDocStatus, 0, len(DesignDocs))
+	for _, def := range DesignDocs {
+		status := DesignDocStatus{
+			ID:      def.ID,
+			Version: def.Version,
+			State:   StateMissing,
+		}
+
+		if exist {
+			stored, found, err := getDesignDoc(ctx, db, def.ID)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				status.StoredVersion = stored.Version
+				status.State = stateOf(def, stored)
+			}
+
+			_, status.Staging, err = getDesignDoc(ctx, db, def.ID+stagingSuffix)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// migrateDesignDoc brings a stored design document up to date with its
+// definition, given its status.
+//
+// Changing the views of a design document makes CouchDB rebuild their index
+// from scratch on the next query, which blocks every query on them until it is
+// done. Unless inPlace is set, the new views are built in a staging design
+// document first. CouchDB shares the index of design documents with the same
+// views, so once the stored design document is swapped for the new views it
+// uses the index already built. If building the staged views times out,
+// running the migration again picks up where it left off.
+func migrateDesignDoc(ctx context.Context, db *kivik.DB, def DesignDoc, status DesignDocStatus, inPlace bool) error {
+	switch status.State {
+	case StateMissing:
+		return putDesignDoc(ctx, db, def.ID, "", def)
+
+	case StateCurrent, StateNewer:
+		if status.Staging {
+			return deleteDesignDoc(ctx, db, def.ID+stagingSuffix)
+		}
+		return nil
+	}
+
+	stored, _, err := getDesignDoc(ctx, db, def.ID)
+	if err != nil {
+		return err
+	}
+
+	// Only the version changes when the views are the same, which doesn't
+	// affect the index.
+	staged := !inPlace && !reflect.DeepEqual(stored.Views, def.Views)
+	if staged {
+		if err := buildStaged(ctx, db, def); err != nil {
+			return err
+		}
+	}
+
+	if err := putDesignDoc(ctx, db, def.ID, stored.Rev, def); err != nil {
+		return err
+	}
+
+	if staged || status.Staging {
+		if err := deleteDesignDoc(ctx, db, def.ID+stagingSuffix); err != nil {
+			return err
+		}
+	}
+	if err := db.ViewCleanup(ctx); err != nil {
+		return fmt.Errorf("cleaning up old indexes: %w", err)
+	}
+
+	return nil
+}
+
+// buildStaged writes the views of a design document to its staging design
+// document and waits for CouchDB to build their index.
+func buildStaged(ctx context.Context, db *kivik.DB, def DesignDoc) error {
+	stagingID := def.ID + stagingSuffix
+
+	staging, found, err := getDesignDoc(ctx, db, stagingID)
+	if err != nil {
+		return err
+	}
+	if !found || !reflect.DeepEqual(staging.Views, def.Views) {
+		if err := putDesignDoc(ctx, db, stagingID, staging.Rev, def); err != nil {
+			return err
+		}
+	}
+
+	// Querying any view waits for the index of every view of the design
+	// document to be up to date.
+	var names []string
+	for name := range def.Views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	options := kivik.Options{"limit": 0}
+	if def.Views[names[0]].Reduce != "" {
+		options["reduce"] = false
+	}
+
+	rows, err := db.Query(ctx, stagingID, "_view/"+names[0], options)
+	if err != nil {
+		return fmt.Errorf("building the staged views of %s, run the migration again to resume: %w", def.ID, err)
+	}
+	rows.Close()
+
+	return nil
+}
+
+// getDesignDoc retrieves a stored design document. The boolean returned is
+// false when it doesn't exist.
+func getDesignDoc(ctx context.Context, db *kivik.DB, docID string) (designDocument, bool, error) {
+	var doc designDocument
+	err := db.Get(ctx, docID).ScanDoc(&doc)
+	if kivik.StatusCode(err) == http.StatusNotFound {
+		return designDocument{}, false, nil
+	}
+	if err != nil {
+		return designDocument{}, false, fmt.Errorf("fetching %s: %w", docID, err)
+	}
+
+	return doc, true, nil
+}
+
+// putDesignDoc writes the views and version of def under docID, over the
+// revision rev.
+func putDesignDoc(ctx context.Context, db *kivik.DB, docID, rev string, def DesignDoc) error {
+	doc := designDocument{
+		ID:      docID,
+		Rev:     rev,
+		Views:   def.Views,
+		Version: def.Version,
+	}
+
+	if _, err := db.Put(ctx, docID, doc); err != nil {
+		return fmt.Errorf("writing %s: %w", docID, err)
+	}
+
+	return nil
+}
+
+// deleteDesignDoc deletes a stored design document, if it exists.
+func deleteDesignDoc(ctx context.Context, db *kivik.DB, docID string) error {
+	doc, found, err := getDesignDoc(ctx, db, docID)
+	if err != nil || !found {
+		return err
+	}
+
+	if _, err := db.Delete(ctx, docID, doc.Rev); err != nil {
+		return fmt.Errorf("deleting %s: %w", docID, err)
+	}
+
+	return nil
+}
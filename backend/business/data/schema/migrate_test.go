@@ -0,0 +1,44 @@
+package schema
+
+import "testing"
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestStateOf(t *testing.T) {
+	def := DesignDoc{
+		ID:      "_design/test",
+		Version: 2,
+		Views: map[string]View{
+			"byRound": {Map: "function(doc) { emit(doc.round, null) }"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		stored designDocument
+		state  State
+	}{
+		{"written before versioning", designDocument{Views: def.Views}, StateOutdated},
+		{"of an older version", designDocument{Version: 1, Views: def.Views}, StateOutdated},
+		{"of the same version and views", designDocument{Version: 2, Views: map[string]View{"byRound": def.Views["byRound"]}}, StateCurrent},
+		{"of the same version with changed views", designDocument{Version: 2, Views: map[string]View{"byRound": {Map: "function(doc) {}"}}}, StateDrifted},
+		{"of a newer version", designDocument{Version: 3}, StateNewer},
+	}
+
+	t.Log("Given the need to compare stored design documents to their definition.")
+	{
+		for testID, tt := range tests {
+			t.Logf("\tTest %d:\tWhen the design document is %s.", testID, tt.name)
+			{
+				if state := stateOf(def, tt.stored); state != tt.state {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be %s : got %s.", failed, testID, tt.state, state)
+				}
+				t.Logf("\t\t%s\tTest %d:\tShould be %s.", success, testID, tt.state)
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	accountdb "github.com/kevguy/algosearch/backend/business/core/account/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// AddAccount adds or replaces an account, stored under its address.
+func (s *Store) AddAccount(ctx context.Context, account models.Account) (string, string, error) {
+	var rev string
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		rev, err = putAccount(tx, account)
+		return err
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("inserting account %s: %w", account.Address, err)
+	}
+
+	return account.Address, rev, nil
+}
+
+// AddAccounts adds or replaces a batch of accounts.
+func (s *Store) AddAccounts(ctx context.Context, accounts []models.Account) (bool, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, account := range accounts {
+			if _, err := putAccount(tx, account); err != nil {
+				return fmt.Errorf("inserting account %s: %w", account.Address, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// putAccount stores an account document and returns its new revision.
+func putAccount(tx *bolt.Tx, account models.Account) (string, error) {
+	b := tx.Bucket(accountsBucket)
+	key := []byte(account.Address)
+
+	var old accountdb.Account
+	if _, err := get(b, key, &old); err != nil {
+		return "", err
+	}
+
+	doc := accountdb.Account{
+		NewAccount: accountdb.NewAccount{Account: account, DocType: accountdb.DocType},
+		ID:         account.Address,
+	}
+	var err error
+	if doc.Rev, err = nextRev(b, old.Rev); err != nil {
+		return "", err
+	}
+	if err := put(b, key, doc); err != nil {
+		return "", err
+	}
+
+	return doc.Rev, nil
+}
+
+// GetAccount retrieves an account based upon its address.
+func (s *Store) GetAccount(ctx context.Context, accountAddr string) (models.Account, error) {
+	var doc accountdb.Account
+	err := s.db.View(func(tx *bolt.Tx) error {
+		found, err := get(tx.Bucket(accountsBucket), []byte(accountAddr), &doc)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return store.NotFound("account %s not found", accountAddr)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return doc.Account, nil
+}
+
+// GetEarliestAccountID retrieves the lowest account address.
+func (s *Store) GetEarliestAccountID(ctx context.Context) (string, error) {
+	return s.accountAddrAt(false)
+}
+
+// GetLatestAccountID retrieves the highest account address.
+func (s *Store) GetLatestAccountID(ctx context.Context) (string, error) {
+	return s.accountAddrAt(true)
+}
+
+// accountAddrAt retrieves the lowest account address, or the highest one when
+// latest is set.
+func (s *Store) accountAddrAt(latest bool) (string, error) {
+	var addr string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(accountsBucket).Cursor()
+		k, _ := c.First()
+		if latest {
+			k, _ = c.Last()
+		}
+		if k == nil {
+			return store.NotFound("no account found")
+		}
+		addr = string(k)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return addr, nil
+}
+
+// GetAccountCountBtnKeys retrieves the number of accounts with addresses from
+// startKey to endKey, both included.
+func (s *Store) GetAccountCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	var count int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		count = countRange(tx.Bucket(accountsBucket), []byte(startKey), []byte(endKey))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetAccountsPagination retrieves a page of the accounts up to the address
+// latestAccountID, ordered by address. Descending pages start at
+// latestAccountID.
+func (s *Store) GetAccountsPagination(ctx context.Context, latestAccountID string, order string, pageNo, limit int64) ([]accountdb.Account, int64, int64, error) {
+	var fetchedAccounts = []accountdb.Account{}
+	var numOfPages, numOfAccounts int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		docs, pages, n, err := pageOfKeys(tx.Bucket(accountsBucket), []byte(latestAccountID), order == "desc", pageNo, limit)
+		if err != nil {
+			return err
+		}
+		numOfPages, numOfAccounts = pages, n
+
+		for _, data := range docs {
+			var account accountdb.Account
+			if err := json.Unmarshal(data, &account); err != nil {
+				return fmt.Errorf("decoding account: %w", err)
+			}
+			fetchedAccounts = append(fetchedAccounts, account)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return fetchedAccounts, numOfPages, numOfAccounts, nil
+}
+
+// GetAccountsFrom retrieves up to limit accounts read from cur, or from the
+// start of the list when cur is nil, ordered by address in the given order. The
+// boolean returned reports whether accounts are left past them in the
+// direction they were read in.
+func (s *Store) GetAccountsFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]accountdb.Account, bool, error) {
+	var fetchedAccounts = []accountdb.Account{}
+	var more bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		key := func(p cursor.Position) []byte {
+			return []byte(p.ID)
+		}
+		docs, m, err := readFrom(tx.Bucket(accountsBucket), nil, cur, key, order, limit)
+		if err != nil {
+			return err
+		}
+		more = m
+
+		for _, data := range docs {
+			var account accountdb.Account
+			if err := json.Unmarshal(data, &account); err != nil {
+				return fmt.Errorf("decoding account: %w", err)
+			}
+			fetchedAccounts = append(fetchedAccounts, account)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return fetchedAccounts, more, nil
+}
@@ -0,0 +1,199 @@
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	appdb "github.com/kevguy/algosearch/backend/business/core/application/db"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// AddApplication adds or replaces an application, stored under its index.
+func (s *Store) AddApplication(ctx context.Context, application models.Application) (string, string, error) {
+	var rev string
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		rev, err = putApplication(tx, application)
+		return err
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("inserting application %d: %w", application.Id, err)
+	}
+
+	return strconv.FormatUint(application.Id, 10), rev, nil
+}
+
+// AddApplications adds or replaces a batch of applications.
+func (s *Store) AddApplications(ctx context.Context, applications []models.Application) (bool, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, application := range applications {
+			if _, err := putApplication(tx, application); err != nil {
+				return fmt.Errorf("inserting application %d: %w", application.Id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// putApplication stores an application document and returns its new revision.
+func putApplication(tx *bolt.Tx, application models.Application) (string, error) {
+	b := tx.Bucket(appsBucket)
+	key := uint64Key(application.Id)
+
+	var old appdb.Application
+	if _, err := get(b, key, &old); err != nil {
+		return "", err
+	}
+
+	doc := appdb.Application{
+		NewApplication: appdb.NewApplication{Application: application, DocType: appdb.DocType},
+		ID:             strconv.FormatUint(application.Id, 10),
+	}
+	var err error
+	if doc.Rev, err = nextRev(b, old.Rev); err != nil {
+		return "", err
+	}
+	if err := put(b, key, doc); err != nil {
+		return "", err
+	}
+
+	return doc.Rev, nil
+}
+
+// GetApplication retrieves an application based upon its ID.
+func (s *Store) GetApplication(ctx context.Context, applicationID string) (models.Application, error) {
+	index, err := store.ParseID(applicationID)
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	var doc appdb.Application
+	err = s.db.View(func(tx *bolt.Tx) error {
+		found, err := get(tx.Bucket(appsBucket), uint64Key(index), &doc)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return store.NotFound("application %s not found", applicationID)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	return doc.Application, nil
+}
+
+// GetEarliestApplicationID retrieves the ID of the application with the lowest index.
+func (s *Store) GetEarliestApplicationID(ctx context.Context) (string, error) {
+	return s.applicationIDAt(false)
+}
+
+// GetLatestApplicationID retrieves the ID of the application with the highest index.
+func (s *Store) GetLatestApplicationID(ctx context.Context) (string, error) {
+	return s.applicationIDAt(true)
+}
+
+// applicationIDAt retrieves the ID of the application with the lowest index, or the highest
+// one when latest is set.
+func (s *Store) applicationIDAt(latest bool) (string, error) {
+	var id string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(appsBucket).Cursor()
+		k, _ := c.First()
+		if latest {
+			k, _ = c.Last()
+		}
+		if k == nil {
+			return store.NotFound("no application found")
+		}
+		id = strconv.FormatUint(binary.BigEndian.Uint64(k), 10)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// GetApplicationCount retrieves the number of applications.
+func (s *Store) GetApplicationCount(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		count = int64(tx.Bucket(appsBucket).Stats().KeyN)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetApplicationCountBtnKeys retrieves the number of applications with IDs from startKey to
+// endKey, both included.
+func (s *Store) GetApplicationCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	first, err := store.ParseID(startKey)
+	if err != nil {
+		return 0, err
+	}
+	last, err := store.ParseID(endKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = s.db.View(func(tx *bolt.Tx) error {
+		count = countRange(tx.Bucket(appsBucket), uint64Key(first), uint64Key(last))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetApplicationsPagination retrieves a page of the applications up to the ID
+// latestApplicationID, ordered by index. Descending pages start at latestApplicationID.
+func (s *Store) GetApplicationsPagination(ctx context.Context, latestApplicationID string, order string, pageNo, limit int64) ([]appdb.Application, int64, int64, error) {
+	latest, err := store.ParseID(latestApplicationID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var fetchedApplications = []appdb.Application{}
+	var numOfPages, numOfApplications int64
+	err = s.db.View(func(tx *bolt.Tx) error {
+		docs, pages, n, err := pageOfKeys(tx.Bucket(appsBucket), uint64Key(latest), order == "desc", pageNo, limit)
+		if err != nil {
+			return err
+		}
+		numOfPages, numOfApplications = pages, n
+
+		for _, data := range docs {
+			var application appdb.Application
+			if err := json.Unmarshal(data, &application); err != nil {
+				return fmt.Errorf("decoding application: %w", err)
+			}
+			fetchedApplications = append(fetchedApplications, application)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return fetchedApplications, numOfPages, numOfApplications, nil
+}
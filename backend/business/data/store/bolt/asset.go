@@ -0,0 +1,199 @@
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	assetdb "github.com/kevguy/algosearch/backend/business/core/asset/db"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// AddAsset adds or replaces an asset, stored under its index.
+func (s *Store) AddAsset(ctx context.Context, asset models.Asset) (string, string, error) {
+	var rev string
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		rev, err = putAsset(tx, asset)
+		return err
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("inserting asset %d: %w", asset.Index, err)
+	}
+
+	return strconv.FormatUint(asset.Index, 10), rev, nil
+}
+
+// AddAssets adds or replaces a batch of assets.
+func (s *Store) AddAssets(ctx context.Context, assets []models.Asset) (bool, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, asset := range assets {
+			if _, err := putAsset(tx, asset); err != nil {
+				return fmt.Errorf("inserting asset %d: %w", asset.Index, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// putAsset stores an asset document and returns its new revision.
+func putAsset(tx *bolt.Tx, asset models.Asset) (string, error) {
+	b := tx.Bucket(assetsBucket)
+	key := uint64Key(asset.Index)
+
+	var old assetdb.Asset
+	if _, err := get(b, key, &old); err != nil {
+		return "", err
+	}
+
+	doc := assetdb.Asset{
+		NewAsset: assetdb.NewAsset{Asset: asset, DocType: assetdb.DocType},
+		ID:       strconv.FormatUint(asset.Index, 10),
+	}
+	var err error
+	if doc.Rev, err = nextRev(b, old.Rev); err != nil {
+		return "", err
+	}
+	if err := put(b, key, doc); err != nil {
+		return "", err
+	}
+
+	return doc.Rev, nil
+}
+
+// GetAsset retrieves an asset based upon its ID.
+func (s *Store) GetAsset(ctx context.Context, assetID string) (models.Asset, error) {
+	index, err := store.ParseID(assetID)
+	if err != nil {
+		return models.Asset{}, err
+	}
+
+	var doc assetdb.Asset
+	err = s.db.View(func(tx *bolt.Tx) error {
+		found, err := get(tx.Bucket(assetsBucket), uint64Key(index), &doc)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return store.NotFound("asset %s not found", assetID)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Asset{}, err
+	}
+
+	return doc.Asset, nil
+}
+
+// GetEarliestAssetID retrieves the ID of the asset with the lowest index.
+func (s *Store) GetEarliestAssetID(ctx context.Context) (string, error) {
+	return s.assetIDAt(false)
+}
+
+// GetLatestAssetID retrieves the ID of the asset with the highest index.
+func (s *Store) GetLatestAssetID(ctx context.Context) (string, error) {
+	return s.assetIDAt(true)
+}
+
+// assetIDAt retrieves the ID of the asset with the lowest index, or the highest
+// one when latest is set.
+func (s *Store) assetIDAt(latest bool) (string, error) {
+	var id string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(assetsBucket).Cursor()
+		k, _ := c.First()
+		if latest {
+			k, _ = c.Last()
+		}
+		if k == nil {
+			return store.NotFound("no asset found")
+		}
+		id = strconv.FormatUint(binary.BigEndian.Uint64(k), 10)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// GetAssetCount retrieves the number of assets.
+func (s *Store) GetAssetCount(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		count = int64(tx.Bucket(assetsBucket).Stats().KeyN)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAssetCountBtnKeys retrieves the number of assets with IDs from startKey to
+// endKey, both included.
+func (s *Store) GetAssetCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	first, err := store.ParseID(startKey)
+	if err != nil {
+		return 0, err
+	}
+	last, err := store.ParseID(endKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = s.db.View(func(tx *bolt.Tx) error {
+		count = countRange(tx.Bucket(assetsBucket), uint64Key(first), uint64Key(last))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetAssetsPagination retrieves a page of the assets up to the ID
+// latestAssetID, ordered by index. Descending pages start at latestAssetID.
+func (s *Store) GetAssetsPagination(ctx context.Context, latestAssetID string, order string, pageNo, limit int64) ([]assetdb.Asset, int64, int64, error) {
+	latest, err := store.ParseID(latestAssetID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var fetchedAssets = []assetdb.Asset{}
+	var numOfPages, numOfAssets int64
+	err = s.db.View(func(tx *bolt.Tx) error {
+		docs, pages, n, err := pageOfKeys(tx.Bucket(assetsBucket), uint64Key(latest), order == "desc", pageNo, limit)
+		if err != nil {
+			return err
+		}
+		numOfPages, numOfAssets = pages, n
+
+		for _, data := range docs {
+			var asset assetdb.Asset
+			if err := json.Unmarshal(data, &asset); err != nil {
+				return fmt.Errorf("decoding asset: %w", err)
+			}
+			fetchedAssets = append(fetchedAssets, asset)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return fetchedAssets, numOfPages, numOfAssets, nil
+}
@@ -0,0 +1,415 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"strconv"
+
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// blockTxnSpeedBlocks is the number of latest blocks the block speed is
+// averaged over.
+const blockTxnSpeedBlocks = 10
+
+// AddBlock adds or replaces a block, pending until it is committed. Blocks are
+// stored under their hash, or their round when they have none. A committed
+// block stays committed when it is replaced.
+func (s *Store) AddBlock(ctx context.Context, block blockdb.NewBlock) (string, string, error) {
+	docID := block.BlockHash
+	if docID == "" {
+		docID = strconv.FormatUint(block.Round, 10)
+	}
+
+	var rev string
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var old blockdb.Block
+		found, err := get(tx.Bucket(blocksBucket), []byte(docID), &old)
+		if err != nil {
+			return err
+		}
+
+		doc := blockdb.Block{
+			NewBlockDoc: blockdb.NewBlockDoc{
+				NewBlock: block,
+				DocType:  blockdb.DocType,
+				Pending:  !found || old.Pending,
+			},
+			ID: docID,
+		}
+
+		rev, err = putBlock(tx, doc)
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return docID, rev, nil
+}
+
+// AddBlocks adds or replaces a batch of blocks, stored under their hash.
+func (s *Store) AddBlocks(ctx context.Context, blocks []blockdb.Block) (bool, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, block := range blocks {
+			block.ID = block.BlockHash
+			if _, err := putBlock(tx, block); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CommitBlock marks the block stored under docID as committed, which makes its
+// round visible.
+func (s *Store) CommitBlock(ctx context.Context, docID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		var doc blockdb.Block
+		found, err := get(tx.Bucket(blocksBucket), []byte(docID), &doc)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return store.NotFound("block %s not found", docID)
+		}
+		if !doc.Pending {
+			return nil
+		}
+
+		doc.Pending = false
+		_, err = putBlock(tx, doc)
+		return err
+	})
+}
+
+// putBlock stores a block document under its ID, indexing its round when it is
+// committed, and returns its new revision.
+func putBlock(tx *bolt.Tx, doc blockdb.Block) (string, error) {
+	blocks := tx.Bucket(blocksBucket)
+	rounds := tx.Bucket(blockRoundsBucket)
+
+	var old blockdb.Block
+	found, err := get(blocks, []byte(doc.ID), &old)
+	if err != nil {
+		return "", err
+	}
+	if found {
+		if err := rounds.Delete(roundKey(old)); err != nil {
+			return "", err
+		}
+	}
+
+	if doc.Rev, err = nextRev(blocks, old.Rev); err != nil {
+		return "", err
+	}
+	if err := put(blocks, []byte(doc.ID), doc); err != nil {
+		return "", err
+	}
+	if !doc.Pending {
+		if err := rounds.Put(roundKey(doc), []byte(doc.ID)); err != nil {
+			return "", err
+		}
+	}
+
+	return doc.Rev, nil
+}
+
+// GetBlockByHash retrieves a block based upon its hash.
+func (s *Store) GetBlockByHash(ctx context.Context, blockHash string) (blockdb.Block, error) {
+	var block blockdb.Block
+	err := s.db.View(func(tx *bolt.Tx) error {
+		found, err := get(tx.Bucket(blocksBucket), []byte(blockHash), &block)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return store.NotFound("block %s not found", blockHash)
+		}
+		if block.Pending {
+			return blockdb.ErrNotCommitted
+		}
+		return nil
+	})
+	if err != nil {
+		return blockdb.Block{}, err
+	}
+
+	return block, nil
+}
+
+// GetBlockByNum retrieves the committed block of a round.
+func (s *Store) GetBlockByNum(ctx context.Context, blockNum uint64) (blockdb.Block, error) {
+	var block blockdb.Block
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var docID []byte
+		err := scan(tx.Bucket(blockRoundsBucket), uint64Key(blockNum), false, func(k, v []byte) (bool, error) {
+			docID = v
+			return false, nil
+		})
+		if err != nil {
+			return err
+		}
+		if docID == nil {
+			return store.NotFound("block of round %d not found", blockNum)
+		}
+
+		_, err = get(tx.Bucket(blocksBucket), docID, &block)
+		return err
+	})
+	if err != nil {
+		return blockdb.Block{}, err
+	}
+
+	return block, nil
+}
+
+// GetEarliestSyncedRoundNumber retrieves the lowest committed round.
+func (s *Store) GetEarliestSyncedRoundNumber(ctx context.Context) (uint64, error) {
+	var round uint64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		k, _ := tx.Bucket(blockRoundsBucket).Cursor().First()
+		if k == nil {
+			return store.NotFound("no block found")
+		}
+		round = binary.BigEndian.Uint64(k)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return round, nil
+}
+
+// GetLastSyncedRoundNumber retrieves the highest committed round. The boolean
+// returned is false when no block is stored.
+func (s *Store) GetLastSyncedRoundNumber(ctx context.Context) (uint64, bool, error) {
+	var round uint64
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		k, _ := tx.Bucket(blockRoundsBucket).Cursor().Last()
+		if k != nil {
+			round, found = binary.BigEndian.Uint64(k), true
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return round, found, nil
+}
+
+// GetLatestBlock retrieves the committed block of the highest round.
+func (s *Store) GetLatestBlock(ctx context.Context) (blockdb.Block, error) {
+	var block blockdb.Block
+	err := s.db.View(func(tx *bolt.Tx) error {
+		_, docID := tx.Bucket(blockRoundsBucket).Cursor().Last()
+		if docID == nil {
+			return store.NotFound("no block found")
+		}
+
+		_, err := get(tx.Bucket(blocksBucket), docID, &block)
+		return err
+	})
+	if err != nil {
+		return blockdb.Block{}, err
+	}
+
+	return block, nil
+}
+
+// GetBlocksPagination retrieves a page of committed blocks. Like the CouchDB
+// store, the number of blocks is counted as the rounds between the earliest
+// one and latestBlockNum, and descending pages start at latestBlockNum.
+func (s *Store) GetBlocksPagination(ctx context.Context, latestBlockNum int64, order string, pageNo int64, limit int64) ([]blockdb.Block, int64, int64, error) {
+	var fetchedBlocks = []blockdb.Block{}
+	var numOfPages, numOfBlks int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		rounds := tx.Bucket(blockRoundsBucket)
+		k, _ := rounds.Cursor().First()
+		if k == nil {
+			return store.NotFound("no block found")
+		}
+
+		numOfBlks = latestBlockNum - int64(binary.BigEndian.Uint64(k)) + 1
+		from, to, pages, err := store.Page(numOfBlks, pageNo, limit)
+		if err != nil {
+			return err
+		}
+		numOfPages = pages
+
+		blocks := tx.Bucket(blocksBucket)
+		var i int64
+		return scan(rounds, nil, order == "desc", func(k, v []byte) (bool, error) {
+			if order == "desc" && int64(binary.BigEndian.Uint64(k)) > latestBlockNum {
+				return true, nil
+			}
+			if i >= to {
+				return false, nil
+			}
+			if i >= from {
+				var block blockdb.Block
+				if _, err := get(blocks, v, &block); err != nil {
+					return false, err
+				}
+				fetchedBlocks = append(fetchedBlocks, block)
+			}
+			i++
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return fetchedBlocks, numOfPages, numOfBlks, nil
+}
+
+// GetBlocksFrom retrieves up to limit committed blocks read from cur, or from
+// the start of the list when cur is nil, in the given order. The boolean
+// returned reports whether blocks are left past them in the direction they
+// were read in.
+func (s *Store) GetBlocksFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]blockdb.Block, bool, error) {
+	var fetchedBlocks = []blockdb.Block{}
+	var more bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		key := func(p cursor.Position) []byte {
+			return join(uint64Key(p.Round), []byte(p.ID))
+		}
+		docIDs, m, err := readFrom(tx.Bucket(blockRoundsBucket), nil, cur, key, order, limit)
+		if err != nil {
+			return err
+		}
+		more = m
+
+		blocks := tx.Bucket(blocksBucket)
+		for _, docID := range docIDs {
+			var block blockdb.Block
+			if _, err := get(blocks, docID, &block); err != nil {
+				return err
+			}
+			fetchedBlocks = append(fetchedBlocks, block)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return fetchedBlocks, more, nil
+}
+
+// GetNumOfBlocks retrieves the number of committed blocks.
+func (s *Store) GetNumOfBlocks(ctx context.Context) (int64, error) {
+	var n int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		n = int64(tx.Bucket(blockRoundsBucket).Stats().KeyN)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// GetBlockTxnSpeed retrieves the average number of seconds between the latest
+// committed blocks.
+func (s *Store) GetBlockTxnSpeed(ctx context.Context) (float64, error) {
+	var timestamps []uint64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		blocks := tx.Bucket(blocksBucket)
+		return scan(tx.Bucket(blockRoundsBucket), nil, true, func(k, v []byte) (bool, error) {
+			var block blockdb.Block
+			if _, err := get(blocks, v, &block); err != nil {
+				return false, err
+			}
+			timestamps = append(timestamps, block.Timestamp)
+			return len(timestamps) < blockTxnSpeedBlocks, nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(timestamps) <= 1 {
+		return 0.0, nil
+	}
+
+	// The timestamps are from the latest block backwards.
+	var sum uint64
+	for i := 1; i < len(timestamps); i++ {
+		sum += timestamps[i-1] - timestamps[i]
+	}
+	return float64(sum) / float64(len(timestamps)-1), nil
+}
+
+// GetRoundGaps retrieves the ranges of rounds missing between the lowest and
+// the highest committed round.
+func (s *Store) GetRoundGaps(ctx context.Context) ([]blockdb.RoundGap, error) {
+	var gaps []blockdb.RoundGap
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var prev uint64
+		var seen bool
+		return scan(tx.Bucket(blockRoundsBucket), nil, false, func(k, v []byte) (bool, error) {
+			round := binary.BigEndian.Uint64(k)
+			if seen && round > prev+1 {
+				gaps = append(gaps, blockdb.RoundGap{From: prev + 1, To: round - 1})
+			}
+			prev, seen = round, true
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return gaps, nil
+}
+
+// DeleteBlocksBefore deletes every committed block older than round and returns
+// how many were deleted.
+func (s *Store) DeleteBlocksBefore(ctx context.Context, round uint64) (int, error) {
+	var deleted int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		blocks := tx.Bucket(blocksBucket)
+		c := tx.Bucket(blockRoundsBucket).Cursor()
+		for k, v := c.First(); k != nil && binary.BigEndian.Uint64(k) < round; k, v = c.First() {
+			if err := blocks.Delete(append([]byte(nil), v...)); err != nil {
+				return err
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			deleted++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// roundKey returns the key of a block in the index on rounds, which orders
+// blocks by round and then by ID like the CouchDB view on round numbers.
+func roundKey(block blockdb.Block) []byte {
+	return join(uint64Key(block.Round), []byte(block.ID))
+}
+
+// roundCommitted reports whether a committed block of round is stored.
+func roundCommitted(tx *bolt.Tx, round uint64) bool {
+	k, _ := tx.Bucket(blockRoundsBucket).Cursor().Seek(uint64Key(round))
+	return k != nil && bytes.HasPrefix(k, uint64Key(round))
+}
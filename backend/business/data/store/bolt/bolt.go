@@ -0,0 +1,332 @@
+// Package bolt provides a storage backend keeping blocks, transactions,
+// accounts, assets, applications and the sync state in a single bbolt file. It
+// orders and pages through them the way the CouchDB views do, so algosearch can
+// run as one process with a data directory, without a database server.
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kevguy/algosearch/backend/business/core/account"
+	"github.com/kevguy/algosearch/backend/business/core/application"
+	"github.com/kevguy/algosearch/backend/business/core/asset"
+	"github.com/kevguy/algosearch/backend/business/core/block"
+	"github.com/kevguy/algosearch/backend/business/core/syncstate"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store has to be usable as the store of every core.
+var (
+	_ block.Storer       = (*Store)(nil)
+	_ transaction.Storer = (*Store)(nil)
+	_ account.Storer     = (*Store)(nil)
+	_ asset.Storer       = (*Store)(nil)
+	_ application.Storer = (*Store)(nil)
+	_ syncstate.Storer   = (*Store)(nil)
+)
+
+// FileName is the name of the file the store keeps in its data directory.
+const FileName = "algosearch.db"
+
+// The buckets of the store. Documents are stored as JSON under their ID, and
+// the other buckets are indexes holding keys that sort the way the CouchDB
+// views do.
+var (
+	blocksBucket      = []byte("blocks")
+	blockRoundsBucket = []byte("block_rounds")
+
+	txnsBucket        = []byte("transactions")
+	txnOrderBucket    = []byte("transaction_order")
+	txnTypesBucket    = []byte("transaction_types")
+	txnAccountsBucket = []byte("transaction_accounts")
+	txnAssetsBucket   = []byte("transaction_assets")
+	txnAppsBucket     = []byte("transaction_applications")
+	txnGroupsBucket   = []byte("transaction_groups")
+	txnPendingBucket  = []byte("transaction_pending")
+
+	accountsBucket = []byte("accounts")
+	assetsBucket   = []byte("assets")
+	appsBucket     = []byte("applications")
+
+	syncBucket         = []byte("sync")
+	failedRoundsBucket = []byte("failed_rounds")
+)
+
+// Store keeps every document in a bbolt file. It satisfies the Storer interface
+// of the block, transaction, account, asset, application and sync state cores,
+// and is safe for concurrent use.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the store kept in dataDir, creating the directory and the store
+// when they don't exist yet. Only one process can have the store open at a time.
+func Open(dataDir string) (*Store, error) {
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating data directory: %w", err)
+	}
+
+	db, err := bolt.Open(filepath.Join(dataDir, FileName), 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+
+	buckets := [][]byte{
+		blocksBucket, blockRoundsBucket,
+		txnsBucket, txnOrderBucket, txnTypesBucket, txnAccountsBucket, txnAssetsBucket, txnAppsBucket, txnGroupsBucket, txnPendingBucket,
+		accountsBucket, assetsBucket, appsBucket,
+		syncBucket, failedRoundsBucket,
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		// Stores opened before groups were indexed need their transactions
+		// indexed by group once.
+		indexGroups := tx.Bucket(txnsBucket) != nil && tx.Bucket(txnGroupsBucket) == nil
+
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("creating bucket %s: %w", name, err)
+			}
+		}
+
+		if indexGroups {
+			if err := indexTransactionGroups(tx); err != nil {
+				return fmt.Errorf("indexing transaction groups: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the store file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// nextRev returns the revision a document of bucket gets when it is written
+// over rev. Like CouchDB revisions it starts with the number of times the
+// document was written.
+func nextRev(b *bolt.Bucket, rev string) (string, error) {
+	seq, err := b.NextSequence()
+	if err != nil {
+		return "", err
+	}
+
+	var gen int
+	fmt.Sscanf(rev, "%d-", &gen)
+	return fmt.Sprintf("%d-%016x", gen+1, seq), nil
+}
+
+// get decodes the document stored under key into dst. The boolean returned is
+// false when there is no such document.
+func get(b *bolt.Bucket, key []byte, dst interface{}) (bool, error) {
+	data := b.Get(key)
+	if data == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return false, fmt.Errorf("decoding %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// put stores the document src under key.
+func put(b *bolt.Bucket, key []byte, src interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", key, err)
+	}
+	return b.Put(key, data)
+}
+
+// uint64Key encodes n so keys sort in numeric order.
+func uint64Key(n uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, n)
+	return key
+}
+
+// join concatenates the parts of a key.
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// scan calls fn with the keys and values of b starting with prefix, in
+// ascending order or descending order when descending is set, until fn returns
+// false.
+func scan(b *bolt.Bucket, prefix []byte, descending bool, fn func(k, v []byte) (bool, error)) error {
+	c := b.Cursor()
+
+	var k, v []byte
+	switch end := successor(prefix); {
+	case !descending:
+		k, v = c.Seek(prefix)
+	case end == nil:
+		k, v = c.Last()
+	default:
+		if k, v = c.Seek(end); k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+	}
+
+	for k != nil && bytes.HasPrefix(k, prefix) {
+		more, err := fn(k, v)
+		if err != nil || !more {
+			return err
+		}
+		if descending {
+			k, v = c.Prev()
+		} else {
+			k, v = c.Next()
+		}
+	}
+	return nil
+}
+
+// readFrom returns the values of up to limit keys of b starting with prefix,
+// read from cur in the given order, where key returns the key of b a position
+// is kept under. The values are in the order of the list, and the boolean
+// returned reports whether keys are left past them in the direction they were
+// read in.
+func readFrom(b *bolt.Bucket, prefix []byte, cur *cursor.Cursor, key func(cursor.Position) []byte, order string, limit int64) ([][]byte, bool, error) {
+	if limit < 1 {
+		return nil, false, cursor.ErrLimit
+	}
+	descending := cur.Descending(order)
+
+	var values [][]byte
+	var more bool
+	collect := func(k, v []byte) (bool, error) {
+		if int64(len(values)) == limit {
+			more = true
+			return false, nil
+		}
+		values = append(values, v)
+		return true, nil
+	}
+
+	if cur == nil {
+		if err := scan(b, prefix, descending, collect); err != nil {
+			return nil, false, err
+		}
+	} else {
+		at := join(prefix, key(cur.Position))
+
+		// Seek lands on the first key from at, so the key at the cursor is
+		// stepped over in either direction.
+		c := b.Cursor()
+		k, v := c.Seek(at)
+		switch {
+		case !descending && bytes.Equal(k, at):
+			k, v = c.Next()
+		case descending && k == nil:
+			k, v = c.Last()
+		case descending:
+			k, v = c.Prev()
+		}
+
+		for k != nil && bytes.HasPrefix(k, prefix) {
+			next, err := collect(k, v)
+			if err != nil {
+				return nil, false, err
+			}
+			if !next {
+				break
+			}
+			if descending {
+				k, v = c.Prev()
+			} else {
+				k, v = c.Next()
+			}
+		}
+	}
+
+	if cur != nil && cur.Before {
+		for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+
+	return values, more, nil
+}
+
+// successor returns the lowest key sorting after every key starting with
+// prefix, or nil when there is none.
+func successor(prefix []byte) []byte {
+	next := append([]byte(nil), prefix...)
+	for i := len(next) - 1; i >= 0; i-- {
+		if next[i] < 0xff {
+			next[i]++
+			return next[:i+1]
+		}
+	}
+	return nil
+}
+
+// countRange counts the keys of b from first to last, both included.
+func countRange(b *bolt.Bucket, first, last []byte) int64 {
+	var count int64
+	c := b.Cursor()
+	for k, _ := c.Seek(first); k != nil && bytes.Compare(k, last) <= 0; k, _ = c.Next() {
+		count++
+	}
+	return count
+}
+
+// pageOfKeys returns the values of page pageNo of the keys of b up to latest,
+// counted from latest when descending is set, along with the number of pages
+// and of keys.
+func pageOfKeys(b *bolt.Bucket, latest []byte, descending bool, pageNo, limit int64) ([][]byte, int64, int64, error) {
+	var n int64
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k, latest) <= 0; k, _ = c.Next() {
+		n++
+	}
+
+	from, to, numOfPages, err := store.Page(n, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var k, v []byte
+	if descending {
+		k, v = c.Seek(latest)
+		if k == nil {
+			k, v = c.Last()
+		} else if !bytes.Equal(k, latest) {
+			k, v = c.Prev()
+		}
+	} else {
+		k, v = c.First()
+	}
+
+	var values [][]byte
+	for i := int64(0); k != nil && i < to; i++ {
+		if i >= from {
+			values = append(values, v)
+		}
+		if descending {
+			k, v = c.Prev()
+		} else {
+			k, v = c.Next()
+		}
+	}
+
+	return values, numOfPages, n, nil
+}
@@ -0,0 +1,321 @@
+package bolt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/core/application"
+	"github.com/kevguy/algosearch/backend/business/core/asset"
+	"github.com/kevguy/algosearch/backend/business/core/block"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store/bolt"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestBlocks(t *testing.T) {
+	t.Log("Given the need to page through blocks kept in a data directory.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen rounds 10 to 14 are stored and round 14 is not committed.", testID)
+		{
+			ctx := context.Background()
+			dataDir := t.TempDir()
+
+			store, err := bolt.Open(dataDir)
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to open the store : %v.", failed, testID, err)
+			}
+			core := block.NewCoreWithStore(store)
+
+			for round := uint64(10); round <= 14; round++ {
+				docID, _, err := core.AddBlock(ctx, blockdb.NewBlock{
+					Block:     models.Block{Round: round, Timestamp: 1000 + 4*round},
+					BlockHash: fmt.Sprintf("HASH%d", round),
+				})
+				if err != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to add block %d : %v.", failed, testID, round, err)
+				}
+				if round == 14 {
+					continue
+				}
+				if err := core.CommitBlock(ctx, docID); err != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to commit block %d : %v.", failed, testID, round, err)
+				}
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould be able to add and commit blocks.", success, testID)
+
+			if err := store.Close(); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to close the store : %v.", failed, testID, err)
+			}
+			if store, err = bolt.Open(dataDir); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to reopen the store : %v.", failed, testID, err)
+			}
+			t.Cleanup(func() { store.Close() })
+			core = block.NewCoreWithStore(store)
+			t.Logf("\t\t%s\tTest %d:\tShould be able to reopen the store.", success, testID)
+
+			last, found, err := core.GetLastSyncedRoundNumber(ctx)
+			if err != nil || !found || last != 13 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould leave the pending round out : got %d, %v, %v.", failed, testID, last, found, err)
+			}
+			if _, err := core.GetBlockByHash(ctx, "HASH14"); !errors.Is(err, blockdb.ErrNotCommitted) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould report the pending block as not committed : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould leave the pending round out.", success, testID)
+
+			if _, err := core.GetBlockByNum(ctx, 9); kivik.StatusCode(err) != http.StatusNotFound {
+				t.Fatalf("\t\t%s\tTest %d:\tShould report a missing block as not found : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould report a missing block as not found.", success, testID)
+
+			blocks, numOfPages, numOfBlks, err := core.GetBlocksPagination(ctx, 13, "desc", 2, 3)
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to get the second page : %v.", failed, testID, err)
+			}
+			if numOfPages != 2 || numOfBlks != 4 || len(blocks) != 1 || blocks[0].Round != 10 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould get round 10 alone on the second descending page : got %d pages, %d blocks, %+v.", failed, testID, numOfPages, numOfBlks, blocks)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould get round 10 alone on the second descending page.", success, testID)
+
+			speed, err := core.GetBlockTxnSpeed(ctx)
+			if err != nil || speed != 4 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould average the time between blocks : got %v, %v.", failed, testID, speed, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould average the time between blocks.", success, testID)
+
+			deleted, err := core.DeleteBlocksBefore(ctx, 12)
+			if err != nil || deleted != 2 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould prune rounds 10 and 11 : got %d, %v.", failed, testID, deleted, err)
+			}
+			earliest, err := core.GetEarliestSyncedRoundNumber(ctx)
+			if err != nil || earliest != 12 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould start at round 12 once pruned : got %d, %v.", failed, testID, earliest, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould prune rounds 10 and 11.", success, testID)
+		}
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	t.Log("Given the need to list the transactions of an account kept in a data directory.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen an account sends transactions over two rounds.", testID)
+		{
+			ctx := context.Background()
+			store, err := bolt.Open(t.TempDir())
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to open the store : %v.", failed, testID, err)
+			}
+			t.Cleanup(func() { store.Close() })
+			core := transaction.NewCoreWithStore(store)
+
+			var txns []models.Transaction
+			for i, round := range []uint64{20, 20, 21} {
+				txns = append(txns, models.Transaction{
+					Id:               fmt.Sprintf("TXN%d", 3-i),
+					Type:             "pay",
+					Sender:           "SENDER",
+					ConfirmedRound:   round,
+					IntraRoundOffset: uint64(i),
+					PaymentTransaction: models.TransactionPayment{
+						Receiver: "RECEIVER",
+					},
+				})
+			}
+			if err := addCommitted(ctx, core, txns); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the transactions : %v.", failed, testID, err)
+			}
+			if err := addCommitted(ctx, core, txns); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the transactions again : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould be able to add the transactions twice.", success, testID)
+
+			page, numOfPages, numOfTxns, err := core.GetTransactionsByAcctPagination(ctx, "RECEIVER", "desc", 1, 2)
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to page through the transactions of the receiver : %v.", failed, testID, err)
+			}
+			if numOfPages != 2 || numOfTxns != 3 || len(page) != 2 || page[0].ID != "TXN1" || page[1].ID != "TXN2" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould order the transactions by round and position : got %d pages, %d transactions, %+v.", failed, testID, numOfPages, numOfTxns, page)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould order the transactions by round and position.", success, testID)
+
+			count, err := core.GetTransactionCountByAcct(ctx, "SENDER", "TXN2", "TXN1")
+			if err != nil || count != 2 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould count the transactions between two of them : got %d, %v.", failed, testID, count, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould count the transactions between two of them.", success, testID)
+
+			deleted, err := core.DeleteTransactionsBefore(ctx, 21)
+			if err != nil || deleted != 2 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould prune the transactions of round 20 : got %d, %v.", failed, testID, deleted, err)
+			}
+			acctTxns, err := core.GetTransactionsByAcct(ctx, "RECEIVER", "asc")
+			if err != nil || len(acctTxns) != 1 || acctTxns[0].ID != "TXN1" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould keep the transaction of round 21 alone : got %+v, %v.", failed, testID, acctTxns, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould prune the transactions of round 20.", success, testID)
+		}
+	}
+}
+
+func TestTransactionsByCursor(t *testing.T) {
+	t.Log("Given the need to page through the transactions of an account with cursors.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen two accounts send transactions over the same rounds.", testID)
+		{
+			ctx := context.Background()
+			store, err := bolt.Open(t.TempDir())
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to open the store : %v.", failed, testID, err)
+			}
+			t.Cleanup(func() { store.Close() })
+			core := transaction.NewCoreWithStore(store)
+
+			var txns []models.Transaction
+			for i := 0; i < 10; i++ {
+				sender := "ALICE"
+				if i%2 == 1 {
+					sender = "BOB"
+				}
+				txns = append(txns, models.Transaction{
+					Id:               fmt.Sprintf("TXN%d", i),
+					Type:             "pay",
+					Sender:           sender,
+					ConfirmedRound:   uint64(30 + i/3),
+					IntraRoundOffset: uint64(i % 3),
+				})
+			}
+			if err := addCommitted(ctx, core, txns); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the transactions : %v.", failed, testID, err)
+			}
+
+			read := func(token string, want string) cursor.Links {
+				var cur *cursor.Cursor
+				if token != "" {
+					var err error
+					if cur, err = cursor.Decode(token); err != nil {
+						t.Fatalf("\t\t%s\tTest %d:\tShould be able to decode the cursor : %v.", failed, testID, err)
+					}
+				}
+				page, links, err := core.GetTransactionsByAcctCursor(ctx, "ALICE", cur, "asc", 2)
+				if err != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to read transactions : %v.", failed, testID, err)
+				}
+				var got []string
+				for _, txn := range page {
+					got = append(got, txn.ID)
+				}
+				if fmt.Sprint(got) != want {
+					t.Fatalf("\t\t%s\tTest %d:\tShould read %s : got %v.", failed, testID, want, got)
+				}
+				return links
+			}
+
+			first := read("", "[TXN0 TXN2]")
+			second := read(first.Next, "[TXN4 TXN6]")
+			last := read(second.Next, "[TXN8]")
+			if last.Next != "" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould not link the last page to a next one : %+v.", failed, testID, last)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould read the transactions of the account alone.", success, testID)
+
+			back := read(last.Prev, "[TXN4 TXN6]")
+			back = read(back.Prev, "[TXN0 TXN2]")
+			if back.Prev != "" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould not link the first page to a previous one : %+v.", failed, testID, back)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould read the pages back to the first one.", success, testID)
+		}
+	}
+}
+
+func TestAssetCount(t *testing.T) {
+	t.Log("Given the need to count the assets kept in a data directory.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen assets are added to an empty store.", testID)
+		{
+			ctx := context.Background()
+
+			store, err := bolt.Open(t.TempDir())
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to open the store : %v.", failed, testID, err)
+			}
+			defer store.Close()
+			core := asset.NewCoreWithStore(store)
+
+			if count, err := core.GetAssetCount(ctx); err != nil || count != 0 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould count no assets : got %d, %v.", failed, testID, count, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould count no assets in an empty store.", success, testID)
+
+			if _, err := core.AddAssets(ctx, []models.Asset{{Index: 7}, {Index: 31566704}}); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the assets : %v.", failed, testID, err)
+			}
+			if count, err := core.GetAssetCount(ctx); err != nil || count != 2 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould count the two assets : got %d, %v.", failed, testID, count, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould count the assets added.", success, testID)
+		}
+	}
+}
+
+func TestApplicationCount(t *testing.T) {
+	t.Log("Given the need to count the applications kept in a data directory.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen applications are added to an empty store.", testID)
+		{
+			ctx := context.Background()
+
+			store, err := bolt.Open(t.TempDir())
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to open the store : %v.", failed, testID, err)
+			}
+			defer store.Close()
+			core := application.NewCoreWithStore(store)
+
+			if count, err := core.GetApplicationCount(ctx); err != nil || count != 0 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould count no applications : got %d, %v.", failed, testID, count, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould count no applications in an empty store.", success, testID)
+
+			if _, err := core.AddApplications(ctx, []models.Application{{Id: 1200}, {Id: 1201}}); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the applications : %v.", failed, testID, err)
+			}
+			if count, err := core.GetApplicationCount(ctx); err != nil || count != 2 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould count the two applications : got %d, %v.", failed, testID, count, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould count the applications added.", success, testID)
+		}
+	}
+}
+
+// addCommitted adds transactions and commits their rounds, the way the ingester
+// does once everything else of a round is saved.
+func addCommitted(ctx context.Context, core transaction.Core, txns []models.Transaction) error {
+	if _, err := core.AddTransactions(ctx, txns, nil, types.Block{}); err != nil {
+		return err
+	}
+	for _, txn := range txns {
+		if err := core.CommitTransactions(ctx, txn.ConfirmedRound); err != nil {
+			return err
+		}
+	}
+	return nil
+}
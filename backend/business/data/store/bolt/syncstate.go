@@ -0,0 +1,231 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	syncdb "github.com/kevguy/algosearch/backend/business/core/syncstate/db"
+	bolt "go.etcd.io/bbolt"
+)
+
+// GetSyncState retrieves the sync state. The boolean returned is false when no
+// sync state has been recorded yet.
+func (s *Store) GetSyncState(ctx context.Context) (syncdb.SyncState, bool, error) {
+	var state syncdb.SyncState
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		found, err = get(tx.Bucket(syncBucket), []byte(syncdb.SyncStateDocID), &state)
+		return err
+	})
+	if err != nil {
+		return syncdb.SyncState{}, false, err
+	}
+
+	return state, found, nil
+}
+
+// SetLastSyncedRound records round as the highest round for which it and every
+// round before it have been synced.
+func (s *Store) SetLastSyncedRound(ctx context.Context, round uint64) error {
+	return s.putSyncState(round, false)
+}
+
+// ResetSyncState records that no round has been synced, so syncing starts over
+// from the first round.
+func (s *Store) ResetSyncState(ctx context.Context) error {
+	return s.putSyncState(0, true)
+}
+
+// putSyncState writes the sync state document.
+func (s *Store) putSyncState(round uint64, nothingSynced bool) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(syncBucket)
+		key := []byte(syncdb.SyncStateDocID)
+
+		var doc syncdb.SyncState
+		if _, err := get(b, key, &doc); err != nil {
+			return err
+		}
+
+		var err error
+		if doc.Rev, err = nextRev(b, doc.Rev); err != nil {
+			return err
+		}
+		doc.ID = syncdb.SyncStateDocID
+		doc.DocType = syncdb.DocType
+		doc.LastSyncedRound = round
+		doc.NothingSynced = nothingSynced
+		doc.UpdatedAt = time.Now().UTC()
+
+		return put(b, key, doc)
+	})
+}
+
+// RecordFailedRound adds or updates the record of a round that could not be
+// ingested. Attempts are added to the ones already recorded for the round.
+func (s *Store) RecordFailedRound(ctx context.Context, round uint64, attempts int, failure error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(failedRoundsBucket)
+		key := uint64Key(round)
+
+		now := time.Now().UTC()
+		var doc syncdb.FailedRound
+		found, err := get(b, key, &doc)
+		if err != nil {
+			return err
+		}
+		if !found {
+			doc = syncdb.FailedRound{
+				ID:            fmt.Sprintf("%s.%d", syncdb.FailedRoundDocType, round),
+				DocType:       syncdb.FailedRoundDocType,
+				Round:         round,
+				FirstFailedAt: now,
+			}
+		}
+
+		if doc.Rev, err = nextRev(b, doc.Rev); err != nil {
+			return err
+		}
+		doc.Attempts += attempts
+		doc.LastFailedAt = now
+		if failure != nil {
+			doc.Error = failure.Error()
+		}
+
+		return put(b, key, doc)
+	})
+}
+
+// GetFailedRound retrieves the record of a failed round. The boolean returned is
+// false when the round is not recorded as failed.
+func (s *Store) GetFailedRound(ctx context.Context, round uint64) (syncdb.FailedRound, bool, error) {
+	var doc syncdb.FailedRound
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		found, err = get(tx.Bucket(failedRoundsBucket), uint64Key(round), &doc)
+		return err
+	})
+	if err != nil {
+		return syncdb.FailedRound{}, false, err
+	}
+
+	return doc, found, nil
+}
+
+// GetFailedRounds retrieves every round recorded as failed, in ascending round
+// order.
+func (s *Store) GetFailedRounds(ctx context.Context) ([]syncdb.FailedRound, error) {
+	var fetchedRounds = []syncdb.FailedRound{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return scan(tx.Bucket(failedRoundsBucket), nil, false, func(k, v []byte) (bool, error) {
+			var doc syncdb.FailedRound
+			if err := json.Unmarshal(v, &doc); err != nil {
+				return false, fmt.Errorf("decoding failed round: %w", err)
+			}
+			fetchedRounds = append(fetchedRounds, doc)
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchedRounds, nil
+}
+
+// DeleteFailedRound removes the record of a failed round. It is not an error if
+// the round is not recorded.
+func (s *Store) DeleteFailedRound(ctx context.Context, round uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(failedRoundsBucket).Delete(uint64Key(round))
+	})
+}
+
+// GetLease retrieves the sync lease. The boolean returned is false when no
+// instance has ever taken the lease.
+func (s *Store) GetLease(ctx context.Context) (syncdb.SyncLease, bool, error) {
+	var lease syncdb.SyncLease
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		found, err = get(tx.Bucket(syncBucket), []byte(syncdb.SyncLeaseDocID), &lease)
+		return err
+	})
+	if err != nil {
+		return syncdb.SyncLease{}, false, err
+	}
+
+	return lease, found, nil
+}
+
+// AcquireLease takes or renews the sync lease for holder, for ttl from now. The
+// lease can only be taken when it is free or has expired. The boolean returned
+// reports whether holder holds the lease, along with the lease as last seen.
+func (s *Store) AcquireLease(ctx context.Context, holder string, ttl time.Duration) (syncdb.SyncLease, bool, error) {
+	var lease syncdb.SyncLease
+	var acquired bool
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(syncBucket)
+		key := []byte(syncdb.SyncLeaseDocID)
+
+		found, err := get(b, key, &lease)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if found && lease.Holder != holder && now.Before(lease.ExpiresAt) {
+			return nil
+		}
+
+		if !found || lease.Holder != holder {
+			lease.AcquiredAt = now
+		}
+		if lease.Rev, err = nextRev(b, lease.Rev); err != nil {
+			return err
+		}
+		lease.ID = syncdb.SyncLeaseDocID
+		lease.DocType = syncdb.LeaseDocType
+		lease.Holder = holder
+		lease.RenewedAt = now
+		lease.ExpiresAt = now.Add(ttl)
+
+		acquired = true
+		return put(b, key, lease)
+	})
+	if err != nil {
+		return syncdb.SyncLease{}, false, err
+	}
+
+	return lease, acquired, nil
+}
+
+// ReleaseLease gives up the sync lease if holder holds it.
+func (s *Store) ReleaseLease(ctx context.Context, holder string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(syncBucket)
+		key := []byte(syncdb.SyncLeaseDocID)
+
+		var lease syncdb.SyncLease
+		found, err := get(b, key, &lease)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if !found || !lease.HeldBy(holder, now) {
+			return nil
+		}
+
+		if lease.Rev, err = nextRev(b, lease.Rev); err != nil {
+			return err
+		}
+		lease.ExpiresAt = now
+
+		return put(b, key, lease)
+	})
+}
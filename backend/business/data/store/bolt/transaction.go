@@ -0,0 +1,669 @@
+package bolt
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+	txndb "github.com/kevguy/algosearch/backend/business/core/transaction/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// txnScope describes a set of transactions: the index bucket listing them and
+// the prefix of their keys in it. Every index maps its keys to transaction IDs.
+type txnScope struct {
+	bucket []byte
+	prefix []byte
+}
+
+// allTxns lists every transaction by round, position within the round and ID,
+// like the CouchDB view on transactions.
+var allTxns = txnScope{bucket: txnOrderBucket}
+
+// typeTxns lists the transactions of a type in the order of allTxns.
+func typeTxns(txnType string) txnScope {
+	return txnScope{bucket: txnTypesBucket, prefix: join([]byte(txnType), []byte{0})}
+}
+
+// roundTxns lists the transactions of a round in the order of allTxns.
+func roundTxns(round uint64) txnScope {
+	return txnScope{bucket: txnOrderBucket, prefix: uint64Key(round)}
+}
+
+// groupTxns lists the transactions of an atomic group, given the base64 of its
+// ID, in the order of allTxns.
+func groupTxns(groupID string) txnScope {
+	return txnScope{bucket: txnGroupsBucket, prefix: join([]byte(groupID), []byte{0})}
+}
+
+// acctTxns lists the transactions an account is involved in, in the order of
+// allTxns.
+func acctTxns(acctID string) txnScope {
+	return txnScope{bucket: txnAccountsBucket, prefix: join([]byte(acctID), []byte{0})}
+}
+
+// assetTxns lists the transactions an asset is involved in, in the order of
+// allTxns.
+func assetTxns(assetID string) (txnScope, error) {
+	id, err := store.ParseID(assetID)
+	if err != nil {
+		return txnScope{}, err
+	}
+	return txnScope{bucket: txnAssetsBucket, prefix: uint64Key(id)}, nil
+}
+
+// appTxns lists the transactions an application is involved in, in the order
+// of allTxns.
+func appTxns(appID string) (txnScope, error) {
+	id, err := store.ParseID(appID)
+	if err != nil {
+		return txnScope{}, err
+	}
+	return txnScope{bucket: txnAppsBucket, prefix: uint64Key(id)}, nil
+}
+
+// AddTransaction adds or replaces a transaction.
+func (s *Store) AddTransaction(ctx context.Context, transaction models.Transaction, blockInfo types.Block) (string, string, error) {
+	var rev string
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		rev, err = putTransaction(tx, txndb.NewTransactionDoc(transaction, txnfields.TransactionExtras{}, blockInfo))
+		return err
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("inserting transaction %s: %w", transaction.Id, err)
+	}
+
+	return transaction.Id, rev, nil
+}
+
+// AddTransactions adds or replaces the transactions of a block, along with the
+// fields of each the SDK's model has no room for.
+func (s *Store) AddTransactions(ctx context.Context, transactions []models.Transaction, extras []txnfields.TransactionExtras, blockInfo types.Block) (bool, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for i := range transactions {
+			var txnExtras txnfields.TransactionExtras
+			if i < len(extras) {
+				txnExtras = extras[i]
+			}
+			if _, err := putTransaction(tx, txndb.NewTransactionDoc(transactions[i], txnExtras, blockInfo)); err != nil {
+				return fmt.Errorf("inserting transaction %s: %w", transactions[i].Id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// putTransaction stores a transaction document under the transaction ID,
+// replacing its index entries, and returns its new revision. The transaction is
+// pending until CommitTransactions is called for its round, unless the block of
+// the round is already committed.
+func putTransaction(tx *bolt.Tx, newDoc txndb.NewTransaction) (string, error) {
+	txns := tx.Bucket(txnsBucket)
+	newDoc.Pending = !roundCommitted(tx, newDoc.ConfirmedRound)
+
+	var old txndb.Transaction
+	found, err := get(txns, []byte(newDoc.Id), &old)
+	if err != nil {
+		return "", err
+	}
+	if found {
+		if err := indexTransaction(tx, old, false); err != nil {
+			return "", err
+		}
+	}
+
+	doc := txndb.Transaction{NewTransaction: newDoc, ID: newDoc.Id}
+	if doc.Rev, err = nextRev(txns, old.Rev); err != nil {
+		return "", err
+	}
+	if err := put(txns, []byte(doc.ID), doc); err != nil {
+		return "", err
+	}
+	if err := indexTransaction(tx, doc, true); err != nil {
+		return "", err
+	}
+
+	return doc.Rev, nil
+}
+
+// indexEntry is the key of a transaction in one of the index buckets.
+type indexEntry struct {
+	bucket []byte
+	key    []byte
+}
+
+// indexTransaction adds the index entries of a transaction, or removes them
+// when add isn't set. Pending transactions are only indexed by round, so they
+// are left out of every list until they are committed.
+func indexTransaction(tx *bolt.Tx, txn txndb.Transaction, add bool) error {
+	var entries []indexEntry
+	if txn.Pending {
+		entries = []indexEntry{{txnPendingBucket, orderKey(txn)}}
+	} else {
+		entries = committedEntries(txn)
+	}
+
+	for _, entry := range entries {
+		b := tx.Bucket(entry.bucket)
+		var err error
+		if add {
+			err = b.Put(entry.key, []byte(txn.ID))
+		} else {
+			err = b.Delete(entry.key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// committedEntries returns the index entries of a committed transaction.
+func committedEntries(txn txndb.Transaction) []indexEntry {
+	order := orderKey(txn)
+
+	entries := []indexEntry{
+		{txnOrderBucket, order},
+		{txnTypesBucket, join(typeTxns(txn.Type).prefix, order)},
+	}
+	if len(txn.Group) > 0 {
+		entries = append(entries, indexEntry{txnGroupsBucket, join(groupTxns(base64.StdEncoding.EncodeToString(txn.Group)).prefix, order)})
+	}
+	for _, acct := range txn.AssociatedAccounts {
+		entries = append(entries, indexEntry{txnAccountsBucket, join(acctTxns(acct).prefix, order)})
+	}
+	for _, id := range txn.AssociatedAssets {
+		entries = append(entries, indexEntry{txnAssetsBucket, join(uint64Key(id), order)})
+	}
+	for _, id := range txn.AssociatedApplications {
+		entries = append(entries, indexEntry{txnAppsBucket, join(uint64Key(id), order)})
+	}
+	return entries
+}
+
+// indexTransactionGroups indexes every stored transaction of an atomic group
+// by group.
+func indexTransactionGroups(tx *bolt.Tx) error {
+	txns := tx.Bucket(txnsBucket)
+	groups := tx.Bucket(txnGroupsBucket)
+	return txns.ForEach(func(k, _ []byte) error {
+		var txn txndb.Transaction
+		if _, err := get(txns, k, &txn); err != nil {
+			return err
+		}
+		if len(txn.Group) == 0 {
+			return nil
+		}
+		key := join(groupTxns(base64.StdEncoding.EncodeToString(txn.Group)).prefix, orderKey(txn))
+		return groups.Put(key, []byte(txn.ID))
+	})
+}
+
+// orderKey returns the key ordering a transaction by round, position within
+// the round and ID.
+func orderKey(txn txndb.Transaction) []byte {
+	return join(uint64Key(txn.ConfirmedRound), uint64Key(txn.IntraRoundOffset), []byte(txn.ID))
+}
+
+// GetTransaction retrieves a transaction based upon its ID.
+func (s *Store) GetTransaction(ctx context.Context, transactionID string) (txndb.Transaction, error) {
+	var doc txndb.Transaction
+	err := s.db.View(func(tx *bolt.Tx) error {
+		found, err := get(tx.Bucket(txnsBucket), []byte(transactionID), &doc)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return store.NotFound("transaction %s not found", transactionID)
+		}
+		if doc.Pending {
+			return txndb.ErrNotCommitted
+		}
+		return nil
+	})
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+
+	return doc, nil
+}
+
+// GetInnerTransaction retrieves an inner transaction based upon the ID of the
+// top-level transaction it belongs to and its path.
+func (s *Store) GetInnerTransaction(ctx context.Context, parentID string, path []int) (models.Transaction, error) {
+	parent, err := s.GetTransaction(ctx, parentID)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	txn, ok := txndb.InnerTxnAt(parent.Transaction, path)
+	if !ok {
+		return models.Transaction{}, fmt.Errorf("transaction %s, path %v: %w", parentID, path, txndb.ErrInnerTxnNotFound)
+	}
+	return txn, nil
+}
+
+// GetTransactionCountBtnKeys retrieves the number of transactions from the one
+// with ID startKey to the one with ID endKey, both included.
+func (s *Store) GetTransactionCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	return s.countBetween(allTxns, startKey, endKey)
+}
+
+// GetEarliestTransaction retrieves the first transaction of the lowest round.
+func (s *Store) GetEarliestTransaction(ctx context.Context) (txndb.Transaction, error) {
+	return s.firstOf(allTxns, false)
+}
+
+// GetLatestTransaction retrieves the last transaction of the highest round.
+func (s *Store) GetLatestTransaction(ctx context.Context) (txndb.Transaction, error) {
+	return s.firstOf(allTxns, true)
+}
+
+// GetTransactionsPagination retrieves a page of transactions. Like the CouchDB
+// store, pages are counted from the latest transaction whatever the starting
+// transaction given.
+func (s *Store) GetTransactionsPagination(ctx context.Context, startTransactionID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	return s.pageOf(allTxns, order, pageNo, limit)
+}
+
+// GetTransactionsFrom retrieves up to limit transactions read from cur, or from
+// the start of the list when cur is nil, in the given order. The boolean
+// returned reports whether transactions are left past them in the direction
+// they were read in.
+func (s *Store) GetTransactionsFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]txndb.Transaction, bool, error) {
+	return s.transactionsFrom(allTxns, cur, order, limit)
+}
+
+// GetTransactionCountByRound retrieves the number of transactions confirmed in
+// a round.
+func (s *Store) GetTransactionCountByRound(ctx context.Context, round uint64) (int64, error) {
+	return s.countOf(roundTxns(round))
+}
+
+// GetTransactionsByRoundPagination retrieves a page of the transactions
+// confirmed in a round.
+func (s *Store) GetTransactionsByRoundPagination(ctx context.Context, round uint64, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	return s.pageOf(roundTxns(round), order, pageNo, limit)
+}
+
+// GetEarliestAcctTransaction retrieves the earliest transaction of an account.
+func (s *Store) GetEarliestAcctTransaction(ctx context.Context, acctID string) (txndb.Transaction, error) {
+	return s.firstOf(acctTxns(acctID), false)
+}
+
+// GetLatestAcctTransaction retrieves the latest transaction of an account.
+func (s *Store) GetLatestAcctTransaction(ctx context.Context, acctID string) (txndb.Transaction, error) {
+	return s.firstOf(acctTxns(acctID), true)
+}
+
+// GetTransactionCountByAcct retrieves the number of transactions of an account
+// from the one with ID startKey to the one with ID endKey, both included.
+func (s *Store) GetTransactionCountByAcct(ctx context.Context, acctID, startKey, endKey string) (int64, error) {
+	return s.countBetween(acctTxns(acctID), startKey, endKey)
+}
+
+// GetTransactionsByAcctPagination retrieves a page of the transactions of an
+// account.
+func (s *Store) GetTransactionsByAcctPagination(ctx context.Context, acctID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	return s.pageOf(acctTxns(acctID), order, pageNo, limit)
+}
+
+// GetTransactionsByAcctFrom retrieves up to limit transactions of an account
+// read from cur, or from the start of the list when cur is nil, in the given
+// order. The boolean returned reports whether transactions are left past them
+// in the direction they were read in.
+func (s *Store) GetTransactionsByAcctFrom(ctx context.Context, acctID string, cur *cursor.Cursor, order string, limit int64) ([]txndb.Transaction, bool, error) {
+	return s.transactionsFrom(acctTxns(acctID), cur, order, limit)
+}
+
+// GetTransactionsByAcct retrieves every transaction of an account, in ascending
+// order when order is "asc" and descending order otherwise.
+func (s *Store) GetTransactionsByAcct(ctx context.Context, acctID string, order string) ([]txndb.Transaction, error) {
+	return s.transactionsOf(acctTxns(acctID), order != "asc", 0, -1)
+}
+
+// GetEarliestAppTransaction retrieves the earliest transaction of an application.
+func (s *Store) GetEarliestAppTransaction(ctx context.Context, appID string) (txndb.Transaction, error) {
+	sc, err := appTxns(appID)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	return s.firstOf(sc, false)
+}
+
+// GetLatestAppTransaction retrieves the latest transaction of an application.
+func (s *Store) GetLatestAppTransaction(ctx context.Context, appID string) (txndb.Transaction, error) {
+	sc, err := appTxns(appID)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	return s.firstOf(sc, true)
+}
+
+// GetTransactionsByApp retrieves every transaction of an application, in
+// ascending order when order is "asc" and descending order otherwise.
+func (s *Store) GetTransactionsByApp(ctx context.Context, appID string, order string) ([]txndb.Transaction, error) {
+	sc, err := appTxns(appID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transactionsOf(sc, order != "asc", 0, -1)
+}
+
+// GetTransactionCountByApp retrieves the number of transactions of an
+// application.
+func (s *Store) GetTransactionCountByApp(ctx context.Context, appID string) (int64, error) {
+	sc, err := appTxns(appID)
+	if err != nil {
+		return 0, err
+	}
+	return s.countOf(sc)
+}
+
+// GetTransactionsByAppPagination retrieves a page of the transactions of an
+// application.
+func (s *Store) GetTransactionsByAppPagination(ctx context.Context, appID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	sc, err := appTxns(appID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return s.pageOf(sc, order, pageNo, limit)
+}
+
+// GetEarliestAssetTransaction retrieves the earliest transaction of an asset.
+func (s *Store) GetEarliestAssetTransaction(ctx context.Context, assetID string) (txndb.Transaction, error) {
+	sc, err := assetTxns(assetID)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	return s.firstOf(sc, false)
+}
+
+// GetLatestAssetTransaction retrieves the latest transaction of an asset.
+func (s *Store) GetLatestAssetTransaction(ctx context.Context, assetID string) (txndb.Transaction, error) {
+	sc, err := assetTxns(assetID)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	return s.firstOf(sc, true)
+}
+
+// GetTransactionsByAsset retrieves every transaction of an asset, in ascending
+// order when order is "asc" and descending order otherwise.
+func (s *Store) GetTransactionsByAsset(ctx context.Context, assetID string, order string) ([]txndb.Transaction, error) {
+	sc, err := assetTxns(assetID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transactionsOf(sc, order != "asc", 0, -1)
+}
+
+// GetTransactionCountByAsset retrieves the number of transactions of an asset.
+func (s *Store) GetTransactionCountByAsset(ctx context.Context, assetID string) (int64, error) {
+	sc, err := assetTxns(assetID)
+	if err != nil {
+		return 0, err
+	}
+	return s.countOf(sc)
+}
+
+// GetTransactionsByAssetPagination retrieves a page of the transactions of an
+// asset.
+func (s *Store) GetTransactionsByAssetPagination(ctx context.Context, assetID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	sc, err := assetTxns(assetID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return s.pageOf(sc, order, pageNo, limit)
+}
+
+// GetTransactionsByType retrieves up to limit transactions of a type, in
+// ascending order when order is "asc" and descending order otherwise.
+func (s *Store) GetTransactionsByType(ctx context.Context, txnType string, order string, limit int64) ([]txndb.Transaction, error) {
+	return s.transactionsOf(typeTxns(txnType), order != "asc", 0, limit)
+}
+
+// GetTransactionsByGroup retrieves the transactions of an atomic group, given
+// the base64 of its ID, ordered by round and by position within the round.
+func (s *Store) GetTransactionsByGroup(ctx context.Context, groupID string) ([]txndb.Transaction, error) {
+	return s.transactionsOf(groupTxns(groupID), false, 0, -1)
+}
+
+// DeleteTransactionsBefore deletes every transaction confirmed before round and
+// returns how many were deleted.
+func (s *Store) DeleteTransactionsBefore(ctx context.Context, round uint64) (int, error) {
+	var deleted int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		txns := tx.Bucket(txnsBucket)
+
+		// Pending transactions are only indexed by round, so those of rounds
+		// that were never committed are deleted as well.
+		for _, bucket := range [][]byte{txnOrderBucket, txnPendingBucket} {
+			c := tx.Bucket(bucket).Cursor()
+			for k, v := c.First(); k != nil; k, v = c.First() {
+				var txn txndb.Transaction
+				if _, err := get(txns, v, &txn); err != nil {
+					return err
+				}
+				if txn.ConfirmedRound >= round {
+					break
+				}
+				if err := indexTransaction(tx, txn, false); err != nil {
+					return err
+				}
+				if err := txns.Delete([]byte(txn.ID)); err != nil {
+					return err
+				}
+				deleted++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// CommitTransactions marks the pending transactions of round as committed,
+// indexing them like every other transaction.
+func (s *Store) CommitTransactions(ctx context.Context, round uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		var ids [][]byte
+		err := scan(tx.Bucket(txnPendingBucket), uint64Key(round), false, func(k, v []byte) (bool, error) {
+			ids = append(ids, append([]byte(nil), v...))
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		txns := tx.Bucket(txnsBucket)
+		for _, id := range ids {
+			var txn txndb.Transaction
+			if _, err := get(txns, id, &txn); err != nil {
+				return err
+			}
+			if err := indexTransaction(tx, txn, false); err != nil {
+				return err
+			}
+
+			txn.Pending = false
+			if txn.Rev, err = nextRev(txns, txn.Rev); err != nil {
+				return err
+			}
+			if err := put(txns, id, txn); err != nil {
+				return err
+			}
+			if err := indexTransaction(tx, txn, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// transactionsOf retrieves the transactions of a scope in ascending order, or
+// descending order when descending is set, skipping the first skip of them and
+// returning up to limit of them. A negative limit returns all of them.
+func (s *Store) transactionsOf(sc txnScope, descending bool, skip, limit int64) ([]txndb.Transaction, error) {
+	var fetchedTxns = []txndb.Transaction{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		txns := tx.Bucket(txnsBucket)
+		var i int64
+		return scan(tx.Bucket(sc.bucket), sc.prefix, descending, func(k, v []byte) (bool, error) {
+			if limit >= 0 && i >= skip+limit {
+				return false, nil
+			}
+			if i >= skip {
+				var txn txndb.Transaction
+				if _, err := get(txns, v, &txn); err != nil {
+					return false, err
+				}
+				fetchedTxns = append(fetchedTxns, txn)
+			}
+			i++
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchedTxns, nil
+}
+
+// transactionsFrom retrieves the page of the transactions of a scope read from
+// cur, along with whether transactions are left past it in the direction it
+// was read in. The scope has to be ordered by round.
+func (s *Store) transactionsFrom(sc txnScope, cur *cursor.Cursor, order string, limit int64) ([]txndb.Transaction, bool, error) {
+	var fetchedTxns = []txndb.Transaction{}
+	var more bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		key := func(p cursor.Position) []byte {
+			return join(uint64Key(p.Round), uint64Key(p.Offset), []byte(p.ID))
+		}
+		txnIDs, m, err := readFrom(tx.Bucket(sc.bucket), sc.prefix, cur, key, order, limit)
+		if err != nil {
+			return err
+		}
+		more = m
+
+		txns := tx.Bucket(txnsBucket)
+		for _, txnID := range txnIDs {
+			var txn txndb.Transaction
+			if _, err := get(txns, txnID, &txn); err != nil {
+				return err
+			}
+			fetchedTxns = append(fetchedTxns, txn)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return fetchedTxns, more, nil
+}
+
+// firstOf retrieves the first transaction of a scope, or the last one when
+// latest is set.
+func (s *Store) firstOf(sc txnScope, latest bool) (txndb.Transaction, error) {
+	txns, err := s.transactionsOf(sc, latest, 0, 1)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	if len(txns) == 0 {
+		return txndb.Transaction{}, store.NotFound("no transaction found")
+	}
+
+	return txns[0], nil
+}
+
+// pageOf retrieves a page of the transactions of a scope, counted from the
+// latest one when order is "desc", along with the number of pages and of
+// transactions.
+func (s *Store) pageOf(sc txnScope, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	numOfTransactions, err := s.countOf(sc)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if numOfTransactions == 0 {
+		return nil, 0, 0, store.NotFound("no transaction found")
+	}
+
+	from, to, numOfPages, err := store.Page(numOfTransactions, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	txns, err := s.transactionsOf(sc, order == "desc", from, to-from)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return txns, numOfPages, numOfTransactions, nil
+}
+
+// countOf counts the transactions of a scope.
+func (s *Store) countOf(sc txnScope) (int64, error) {
+	var count int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return scan(tx.Bucket(sc.bucket), sc.prefix, false, func(k, v []byte) (bool, error) {
+			count++
+			return true, nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// countBetween counts the transactions of a scope from the one with ID startKey
+// to the one with ID endKey, both included. Both have to exist. The scope has
+// to be ordered by round.
+func (s *Store) countBetween(sc txnScope, startKey, endKey string) (int64, error) {
+	var count int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		txns := tx.Bucket(txnsBucket)
+
+		var first, last txndb.Transaction
+		found, err := get(txns, []byte(startKey), &first)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("fetch earliest transaction error: %w", store.NotFound("transaction %s not found", startKey))
+		}
+		found, err = get(txns, []byte(endKey), &last)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("fetch latest transaction error: %w", store.NotFound("transaction %s not found", endKey))
+		}
+
+		count = countRange(tx.Bucket(sc.bucket), join(sc.prefix, orderKey(first)), join(sc.prefix, orderKey(last)))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	accountdb "github.com/kevguy/algosearch/backend/business/core/account/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+)
+
+// AddAccount adds or replaces an account, stored under its address.
+func (s *Store) AddAccount(ctx context.Context, account models.Account) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return account.Address, s.putAccount(account), nil
+}
+
+// AddAccounts adds or replaces a batch of accounts.
+func (s *Store) AddAccounts(ctx context.Context, accounts []models.Account) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, account := range accounts {
+		s.putAccount(account)
+	}
+	return true, nil
+}
+
+// putAccount stores an account document and returns its new revision.
+func (s *Store) putAccount(account models.Account) string {
+	var doc accountdb.Account
+	clone(&doc, accountdb.NewAccount{Account: account, DocType: accountdb.DocType})
+	doc.ID = account.Address
+	doc.Rev = s.nextRev(s.accounts[doc.ID].Rev)
+	s.accounts[doc.ID] = doc
+	return doc.Rev
+}
+
+// GetAccount retrieves an account based upon its address.
+func (s *Store) GetAccount(ctx context.Context, accountAddr string) (models.Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	doc, ok := s.accounts[accountAddr]
+	if !ok {
+		return models.Account{}, store.NotFound("account %s not found", accountAddr)
+	}
+
+	var account models.Account
+	clone(&account, doc.Account)
+	return account, nil
+}
+
+// GetEarliestAccountID retrieves the lowest account address.
+func (s *Store) GetEarliestAccountID(ctx context.Context) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	addrs := s.accountAddrs()
+	if len(addrs) == 0 {
+		return "", store.NotFound("no account found")
+	}
+	return addrs[0], nil
+}
+
+// GetLatestAccountID retrieves the highest account address.
+func (s *Store) GetLatestAccountID(ctx context.Context) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	addrs := s.accountAddrs()
+	if len(addrs) == 0 {
+		return "", store.NotFound("no account found")
+	}
+	return addrs[len(addrs)-1], nil
+}
+
+// GetAccountCountBtnKeys retrieves the number of accounts with addresses from
+// startKey to endKey, both included.
+func (s *Store) GetAccountCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for addr := range s.accounts {
+		if addr >= startKey && addr <= endKey {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetAccountsPagination retrieves a page of the accounts up to the address
+// latestAccountID, ordered by address. Descending pages start at
+// latestAccountID.
+func (s *Store) GetAccountsPagination(ctx context.Context, latestAccountID string, order string, pageNo, limit int64) ([]accountdb.Account, int64, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var addrs []string
+	for _, addr := range s.accountAddrs() {
+		if addr <= latestAccountID {
+			addrs = append(addrs, addr)
+		}
+	}
+	if order == "desc" {
+		sort.Sort(sort.Reverse(sort.StringSlice(addrs)))
+	}
+
+	var numOfAccounts = int64(len(addrs))
+	from, to, numOfPages, err := store.Page(numOfAccounts, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var fetchedAccounts = []accountdb.Account{}
+	for _, addr := range addrs[from:to] {
+		var account accountdb.Account
+		clone(&account, s.accounts[addr])
+		fetchedAccounts = append(fetchedAccounts, account)
+	}
+
+	return fetchedAccounts, numOfPages, numOfAccounts, nil
+}
+
+// GetAccountsFrom retrieves up to limit accounts read from cur, or from the
+// start of the list when cur is nil, ordered by address in the given order. The
+// boolean returned reports whether accounts are left past them in the
+// direction they were read in.
+func (s *Store) GetAccountsFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]accountdb.Account, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	addrs := s.accountAddrs()
+	indexes, more, err := readFrom(len(addrs), func(i int) cursor.Position { return cursor.Position{ID: addrs[i]} }, cur, order, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var fetchedAccounts = []accountdb.Account{}
+	for _, i := range indexes {
+		var account accountdb.Account
+		clone(&account, s.accounts[addrs[i]])
+		fetchedAccounts = append(fetchedAccounts, account)
+	}
+
+	return fetchedAccounts, more, nil
+}
+
+// accountAddrs returns the addresses of the stored accounts in ascending order.
+func (s *Store) accountAddrs() []string {
+	addrs := make([]string, 0, len(s.accounts))
+	for addr := range s.accounts {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	return addrs
+}
@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	appdb "github.com/kevguy/algosearch/backend/business/core/application/db"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+)
+
+// AddApplication adds or replaces an application, stored under its ID.
+func (s *Store) AddApplication(ctx context.Context, app models.Application) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return strconv.FormatUint(app.Id, 10), s.putApp(app), nil
+}
+
+// AddApplications adds or replaces a batch of applications.
+func (s *Store) AddApplications(ctx context.Context, apps []models.Application) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, app := range apps {
+		s.putApp(app)
+	}
+	return true, nil
+}
+
+// putApp stores an application document and returns its new revision.
+func (s *Store) putApp(app models.Application) string {
+	var doc appdb.Application
+	clone(&doc, appdb.NewApplication{Application: app, DocType: appdb.DocType})
+	doc.ID = strconv.FormatUint(app.Id, 10)
+	doc.Rev = s.nextRev(s.apps[app.Id].Rev)
+	s.apps[app.Id] = doc
+	return doc.Rev
+}
+
+// GetApplication retrieves an application based upon its ID.
+func (s *Store) GetApplication(ctx context.Context, applicationID string) (models.Application, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	index, err := store.ParseID(applicationID)
+	if err != nil {
+		return models.Application{}, err
+	}
+	doc, ok := s.apps[index]
+	if !ok {
+		return models.Application{}, store.NotFound("application %s not found", applicationID)
+	}
+
+	var app models.Application
+	clone(&app, doc.Application)
+	return app, nil
+}
+
+// GetEarliestApplicationID retrieves the ID of the application with the lowest
+// index.
+func (s *Store) GetEarliestApplicationID(ctx context.Context) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.appIDs(false)
+	if len(ids) == 0 {
+		return "", store.NotFound("no application found")
+	}
+	return strconv.FormatUint(ids[0], 10), nil
+}
+
+// GetLatestApplicationID retrieves the ID of the application with the highest
+// index.
+func (s *Store) GetLatestApplicationID(ctx context.Context) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.appIDs(true)
+	if len(ids) == 0 {
+		return "", store.NotFound("no application found")
+	}
+	return strconv.FormatUint(ids[0], 10), nil
+}
+
+// GetApplicationCount retrieves the number of applications.
+func (s *Store) GetApplicationCount(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.apps)), nil
+}
+
+// GetApplicationCountBtnKeys retrieves the number of applications with IDs from
+// startKey to endKey, both included.
+func (s *Store) GetApplicationCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	first, err := store.ParseID(startKey)
+	if err != nil {
+		return 0, err
+	}
+	last, err := store.ParseID(endKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for index := range s.apps {
+		if index >= first && index <= last {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetApplicationsPagination retrieves a page of the applications up to the ID
+// latestApplicationID, ordered by index. Descending pages start at
+// latestApplicationID.
+func (s *Store) GetApplicationsPagination(ctx context.Context, latestApplicationID string, order string, pageNo, limit int64) ([]appdb.Application, int64, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	latest, err := store.ParseID(latestApplicationID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var ids []uint64
+	for _, index := range s.appIDs(order == "desc") {
+		if index <= latest {
+			ids = append(ids, index)
+		}
+	}
+
+	var numOfApps = int64(len(ids))
+	from, to, numOfPages, err := store.Page(numOfApps, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var fetchedApps = []appdb.Application{}
+	for _, index := range ids[from:to] {
+		var app appdb.Application
+		clone(&app, s.apps[index])
+		fetchedApps = append(fetchedApps, app)
+	}
+
+	return fetchedApps, numOfPages, numOfApps, nil
+}
+
+// appIDs returns the indexes of the stored applications in ascending order, or
+// descending order when descending is set.
+func (s *Store) appIDs(descending bool) []uint64 {
+	ids := make([]uint64, 0, len(s.apps))
+	for index := range s.apps {
+		ids = append(ids, index)
+	}
+	sortIDs(ids, descending)
+	return ids
+}
@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	assetdb "github.com/kevguy/algosearch/backend/business/core/asset/db"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+)
+
+// AddAsset adds or replaces an asset, stored under its index.
+func (s *Store) AddAsset(ctx context.Context, asset models.Asset) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return strconv.FormatUint(asset.Index, 10), s.putAsset(asset), nil
+}
+
+// AddAssets adds or replaces a batch of assets.
+func (s *Store) AddAssets(ctx context.Context, assets []models.Asset) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, asset := range assets {
+		s.putAsset(asset)
+	}
+	return true, nil
+}
+
+// putAsset stores an asset document and returns its new revision.
+func (s *Store) putAsset(asset models.Asset) string {
+	var doc assetdb.Asset
+	clone(&doc, assetdb.NewAsset{Asset: asset, DocType: assetdb.DocType})
+	doc.ID = strconv.FormatUint(asset.Index, 10)
+	doc.Rev = s.nextRev(s.assets[asset.Index].Rev)
+	s.assets[asset.Index] = doc
+	return doc.Rev
+}
+
+// GetAsset retrieves an asset based upon its ID.
+func (s *Store) GetAsset(ctx context.Context, assetID string) (models.Asset, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	index, err := store.ParseID(assetID)
+	if err != nil {
+		return models.Asset{}, err
+	}
+	doc, ok := s.assets[index]
+	if !ok {
+		return models.Asset{}, store.NotFound("asset %s not found", assetID)
+	}
+
+	var asset models.Asset
+	clone(&asset, doc.Asset)
+	return asset, nil
+}
+
+// GetEarliestAssetID retrieves the ID of the asset with the lowest index.
+func (s *Store) GetEarliestAssetID(ctx context.Context) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.assetIDs(false)
+	if len(ids) == 0 {
+		return "", store.NotFound("no asset found")
+	}
+	return strconv.FormatUint(ids[0], 10), nil
+}
+
+// GetLatestAssetID retrieves the ID of the asset with the highest index.
+func (s *Store) GetLatestAssetID(ctx context.Context) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.assetIDs(true)
+	if len(ids) == 0 {
+		return "", store.NotFound("no asset found")
+	}
+	return strconv.FormatUint(ids[0], 10), nil
+}
+
+// GetAssetCount retrieves the number of assets.
+func (s *Store) GetAssetCount(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.assets)), nil
+}
+
+// GetAssetCountBtnKeys retrieves the number of assets with IDs from startKey to
+// endKey, both included.
+func (s *Store) GetAssetCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	first, err := store.ParseID(startKey)
+	if err != nil {
+		return 0, err
+	}
+	last, err := store.ParseID(endKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for index := range s.assets {
+		if index >= first && index <= last {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetAssetsPagination retrieves a page of the assets up to the ID
+// latestAssetID, ordered by index. Descending pages start at latestAssetID.
+func (s *Store) GetAssetsPagination(ctx context.Context, latestAssetID string, order string, pageNo, limit int64) ([]assetdb.Asset, int64, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	latest, err := store.ParseID(latestAssetID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var ids []uint64
+	for _, index := range s.assetIDs(order == "desc") {
+		if index <= latest {
+			ids = append(ids, index)
+		}
+	}
+
+	var numOfAssets = int64(len(ids))
+	from, to, numOfPages, err := store.Page(numOfAssets, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var fetchedAssets = []assetdb.Asset{}
+	for _, index := range ids[from:to] {
+		var asset assetdb.Asset
+		clone(&asset, s.assets[index])
+		fetchedAssets = append(fetchedAssets, asset)
+	}
+
+	return fetchedAssets, numOfPages, numOfAssets, nil
+}
+
+// assetIDs returns the indexes of the stored assets in ascending order, or
+// descending order when descending is set.
+func (s *Store) assetIDs(descending bool) []uint64 {
+	ids := make([]uint64, 0, len(s.assets))
+	for index := range s.assets {
+		ids = append(ids, index)
+	}
+	sortIDs(ids, descending)
+	return ids
+}
@@ -0,0 +1,307 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+)
+
+// blockTxnSpeedBlocks is the number of latest blocks the block speed is
+// averaged over.
+const blockTxnSpeedBlocks = 10
+
+// AddBlock adds or replaces a block, pending until it is committed. Blocks are
+// stored under their hash, or their round when they have none. A committed
+// block stays committed when it is replaced.
+func (s *Store) AddBlock(ctx context.Context, block blockdb.NewBlock) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	docID := block.BlockHash
+	if docID == "" {
+		docID = strconv.FormatUint(block.Round, 10)
+	}
+
+	old, found := s.blocks[docID]
+
+	var doc blockdb.Block
+	clone(&doc, blockdb.NewBlockDoc{
+		NewBlock: block,
+		DocType:  blockdb.DocType,
+		Pending:  !found || old.Pending,
+	})
+	doc.ID = docID
+	doc.Rev = s.nextRev(old.Rev)
+	s.blocks[docID] = doc
+
+	return docID, doc.Rev, nil
+}
+
+// AddBlocks adds or replaces a batch of blocks, stored under their hash.
+func (s *Store) AddBlocks(ctx context.Context, blocks []blockdb.Block) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, block := range blocks {
+		var doc blockdb.Block
+		clone(&doc, block)
+		doc.ID = block.BlockHash
+		doc.Rev = s.nextRev(s.blocks[doc.ID].Rev)
+		s.blocks[doc.ID] = doc
+	}
+
+	return true, nil
+}
+
+// CommitBlock marks the block stored under docID as committed, which makes its
+// round visible.
+func (s *Store) CommitBlock(ctx context.Context, docID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.blocks[docID]
+	if !ok {
+		return store.NotFound("block %s not found", docID)
+	}
+	if !doc.Pending {
+		return nil
+	}
+	doc.Pending = false
+	doc.Rev = s.nextRev(doc.Rev)
+	s.blocks[docID] = doc
+
+	return nil
+}
+
+// GetBlockByHash retrieves a block based upon its hash.
+func (s *Store) GetBlockByHash(ctx context.Context, blockHash string) (blockdb.Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	doc, ok := s.blocks[blockHash]
+	if !ok {
+		return blockdb.Block{}, store.NotFound("block %s not found", blockHash)
+	}
+	if doc.Pending {
+		return blockdb.Block{}, blockdb.ErrNotCommitted
+	}
+
+	var block blockdb.Block
+	clone(&block, doc)
+	return block, nil
+}
+
+// GetBlockByNum retrieves the committed block of a round.
+func (s *Store) GetBlockByNum(ctx context.Context, blockNum uint64) (blockdb.Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, doc := range s.committedBlocks() {
+		if doc.Round == blockNum {
+			var block blockdb.Block
+			clone(&block, doc)
+			return block, nil
+		}
+	}
+	return blockdb.Block{}, store.NotFound("block of round %d not found", blockNum)
+}
+
+// GetEarliestSyncedRoundNumber retrieves the lowest committed round.
+func (s *Store) GetEarliestSyncedRoundNumber(ctx context.Context) (uint64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blocks := s.committedBlocks()
+	if len(blocks) == 0 {
+		return 0, store.NotFound("no block found")
+	}
+	return blocks[0].Round, nil
+}
+
+// GetLastSyncedRoundNumber retrieves the highest committed round. The boolean
+// returned is false when no block is stored.
+func (s *Store) GetLastSyncedRoundNumber(ctx context.Context) (uint64, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blocks := s.committedBlocks()
+	if len(blocks) == 0 {
+		return 0, false, nil
+	}
+	return blocks[len(blocks)-1].Round, true, nil
+}
+
+// GetLatestBlock retrieves the committed block of the highest round.
+func (s *Store) GetLatestBlock(ctx context.Context) (blockdb.Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blocks := s.committedBlocks()
+	if len(blocks) == 0 {
+		return blockdb.Block{}, store.NotFound("no block found")
+	}
+
+	var block blockdb.Block
+	clone(&block, blocks[len(blocks)-1])
+	return block, nil
+}
+
+// GetBlocksPagination retrieves a page of committed blocks. Like the CouchDB
+// store, the number of blocks is counted as the rounds between the earliest
+// one and latestBlockNum, and descending pages start at latestBlockNum.
+func (s *Store) GetBlocksPagination(ctx context.Context, latestBlockNum int64, order string, pageNo int64, limit int64) ([]blockdb.Block, int64, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blocks := s.committedBlocks()
+	if len(blocks) == 0 {
+		return nil, 0, 0, store.NotFound("no block found")
+	}
+
+	var numOfBlks = latestBlockNum - int64(blocks[0].Round) + 1
+	from, to, numOfPages, err := store.Page(numOfBlks, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if order == "desc" {
+		var descending []blockdb.Block
+		for i := len(blocks) - 1; i >= 0; i-- {
+			if int64(blocks[i].Round) <= latestBlockNum {
+				descending = append(descending, blocks[i])
+			}
+		}
+		blocks = descending
+	}
+
+	var fetchedBlocks = []blockdb.Block{}
+	for i := from; i < to && i < int64(len(blocks)); i++ {
+		var block blockdb.Block
+		clone(&block, blocks[i])
+		fetchedBlocks = append(fetchedBlocks, block)
+	}
+
+	return fetchedBlocks, numOfPages, numOfBlks, nil
+}
+
+// GetBlocksFrom retrieves up to limit committed blocks read from cur, or from
+// the start of the list when cur is nil, in the given order. The boolean
+// returned reports whether blocks are left past them in the direction they
+// were read in.
+func (s *Store) GetBlocksFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]blockdb.Block, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blocks := s.committedBlocks()
+	indexes, more, err := readFrom(len(blocks), func(i int) cursor.Position { return blocks[i].Position() }, cur, order, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var fetchedBlocks = []blockdb.Block{}
+	for _, i := range indexes {
+		var block blockdb.Block
+		clone(&block, blocks[i])
+		fetchedBlocks = append(fetchedBlocks, block)
+	}
+
+	return fetchedBlocks, more, nil
+}
+
+// GetNumOfBlocks retrieves the number of committed blocks.
+func (s *Store) GetNumOfBlocks(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.committedBlocks())), nil
+}
+
+// GetBlockTxnSpeed retrieves the average number of seconds between the latest
+// committed blocks.
+func (s *Store) GetBlockTxnSpeed(ctx context.Context) (float64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blocks := s.committedBlocks()
+	if len(blocks) > blockTxnSpeedBlocks {
+		blocks = blocks[len(blocks)-blockTxnSpeedBlocks:]
+	}
+	if len(blocks) <= 1 {
+		return 0.0, nil
+	}
+
+	var sum uint64
+	for i := 1; i < len(blocks); i++ {
+		sum += blocks[i].Timestamp - blocks[i-1].Timestamp
+	}
+	return float64(sum) / float64(len(blocks)-1), nil
+}
+
+// GetRoundGaps retrieves the ranges of rounds missing between the lowest and
+// the highest committed round.
+func (s *Store) GetRoundGaps(ctx context.Context) ([]blockdb.RoundGap, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blocks := s.committedBlocks()
+
+	var gaps []blockdb.RoundGap
+	for i := 1; i < len(blocks); i++ {
+		prev, round := blocks[i-1].Round, blocks[i].Round
+		if round > prev+1 {
+			gaps = append(gaps, blockdb.RoundGap{From: prev + 1, To: round - 1})
+		}
+	}
+
+	return gaps, nil
+}
+
+// DeleteBlocksBefore deletes every committed block older than round and returns
+// how many were deleted.
+func (s *Store) DeleteBlocksBefore(ctx context.Context, round uint64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int
+	for _, block := range s.committedBlocks() {
+		if block.Round >= round {
+			break
+		}
+		delete(s.blocks, block.ID)
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// committedBlocks returns the committed blocks in the order of the CouchDB view
+// on round numbers, by round and then by ID.
+func (s *Store) committedBlocks() []blockdb.Block {
+	var blocks []blockdb.Block
+	for _, doc := range s.blocks {
+		if !doc.Pending {
+			blocks = append(blocks, doc)
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].Round != blocks[j].Round {
+			return blocks[i].Round < blocks[j].Round
+		}
+		return blocks[i].ID < blocks[j].ID
+	})
+	return blocks
+}
+
+// roundCommitted reports whether a committed block of round is stored.
+func (s *Store) roundCommitted(round uint64) bool {
+	for _, doc := range s.blocks {
+		if doc.Round == round && !doc.Pending {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+// Package memory provides a storage backend keeping blocks, transactions,
+// accounts, assets, applications and the sync state in memory. It orders and
+// pages through them the way the CouchDB views do, so the cores, handlers and
+// the block synchronizer can run without a database, as in tests.
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kevguy/algosearch/backend/business/core/account"
+	accountdb "github.com/kevguy/algosearch/backend/business/core/account/db"
+	"github.com/kevguy/algosearch/backend/business/core/application"
+	appdb "github.com/kevguy/algosearch/backend/business/core/application/db"
+	"github.com/kevguy/algosearch/backend/business/core/asset"
+	assetdb "github.com/kevguy/algosearch/backend/business/core/asset/db"
+	"github.com/kevguy/algosearch/backend/business/core/block"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/core/syncstate"
+	syncdb "github.com/kevguy/algosearch/backend/business/core/syncstate/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	txndb "github.com/kevguy/algosearch/backend/business/core/transaction/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+)
+
+// Store has to be usable as the store of every core.
+var (
+	_ block.Storer       = (*Store)(nil)
+	_ transaction.Storer = (*Store)(nil)
+	_ account.Storer     = (*Store)(nil)
+	_ asset.Storer       = (*Store)(nil)
+	_ application.Storer = (*Store)(nil)
+	_ syncstate.Storer   = (*Store)(nil)
+)
+
+// Store keeps every document in memory. It satisfies the Storer interface of
+// the block, transaction, account, asset, application and sync state cores, and
+// is safe for concurrent use.
+type Store struct {
+	mu  sync.RWMutex
+	seq uint64
+
+	blocks       map[string]blockdb.Block
+	transactions map[string]txndb.Transaction
+	accounts     map[string]accountdb.Account
+	assets       map[uint64]assetdb.Asset
+	apps         map[uint64]appdb.Application
+
+	syncState    *syncdb.SyncState
+	failedRounds map[uint64]syncdb.FailedRound
+	lease        *syncdb.SyncLease
+}
+
+// New constructs an empty store.
+func New() *Store {
+	return &Store{
+		blocks:       make(map[string]blockdb.Block),
+		transactions: make(map[string]txndb.Transaction),
+		accounts:     make(map[string]accountdb.Account),
+		assets:       make(map[uint64]assetdb.Asset),
+		apps:         make(map[uint64]appdb.Application),
+		failedRounds: make(map[uint64]syncdb.FailedRound),
+	}
+}
+
+// nextRev returns the revision a document gets when it is written over rev.
+// Like CouchDB revisions it starts with the number of times the document was
+// written.
+func (s *Store) nextRev(rev string) string {
+	var gen int
+	fmt.Sscanf(rev, "%d-", &gen)
+	s.seq++
+	return fmt.Sprintf("%d-%016x", gen+1, s.seq)
+}
+
+// clone copies src into dst through their JSON encoding, so documents are stored
+// and returned the way CouchDB would, and never share memory with the caller.
+func clone(dst, src interface{}) {
+	data, err := json.Marshal(src)
+	if err != nil {
+		panic(fmt.Sprintf("memory: encoding document: %v", err))
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		panic(fmt.Sprintf("memory: decoding document: %v", err))
+	}
+}
+
+// readFrom works out which of n entries, sorted in ascending order with
+// position returning where entry i stands, make up the page of up to limit
+// entries read from cur in the given order. It returns their indexes in the
+// order of the list, and whether entries are left past them in the direction
+// they were read in.
+func readFrom(n int, position func(i int) cursor.Position, cur *cursor.Cursor, order string, limit int64) ([]int, bool, error) {
+	if limit < 1 {
+		return nil, false, cursor.ErrLimit
+	}
+
+	descending := cur.Descending(order)
+	var indexes []int
+	for k := 0; k < n && int64(len(indexes)) <= limit; k++ {
+		i := k
+		if descending {
+			i = n - 1 - k
+		}
+		if cur.Includes(position(i), descending) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	more := int64(len(indexes)) > limit
+	if more {
+		indexes = indexes[:limit]
+	}
+	if cur != nil && cur.Before {
+		for i, j := 0, len(indexes)-1; i < j; i, j = i+1, j-1 {
+			indexes[i], indexes[j] = indexes[j], indexes[i]
+		}
+	}
+
+	return indexes, more, nil
+}
+
+// sortIDs sorts the indexes of assets or applications in ascending order, or
+// descending order when descending is set.
+func sortIDs(ids []uint64, descending bool) {
+	sort.Slice(ids, func(i, j int) bool {
+		if descending {
+			return ids[i] > ids[j]
+		}
+		return ids[i] < ids[j]
+	})
+}
@@ -0,0 +1,593 @@
+package memory_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/core/account"
+	"github.com/kevguy/algosearch/backend/business/core/block"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/core/syncstate"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	txndb "github.com/kevguy/algosearch/backend/business/core/transaction/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store/memory"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestBlocks(t *testing.T) {
+	t.Log("Given the need to page through blocks without CouchDB.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen rounds 10 to 14 are stored and round 14 is not committed.", testID)
+		{
+			ctx := context.Background()
+			core := block.NewCoreWithStore(memory.New())
+
+			for round := uint64(10); round <= 14; round++ {
+				docID, _, err := core.AddBlock(ctx, blockdb.NewBlock{
+					Block:     models.Block{Round: round, Timestamp: 1000 + 4*round},
+					BlockHash: fmt.Sprintf("HASH%d", round),
+				})
+				if err != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to add block %d : %v.", failed, testID, round, err)
+				}
+				if round == 14 {
+					continue
+				}
+				if err := core.CommitBlock(ctx, docID); err != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to commit block %d : %v.", failed, testID, round, err)
+				}
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould be able to add and commit blocks.", success, testID)
+
+			last, found, err := core.GetLastSyncedRoundNumber(ctx)
+			if err != nil || !found || last != 13 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould leave the pending round out : got %d, %v, %v.", failed, testID, last, found, err)
+			}
+			if _, err := core.GetBlockByHash(ctx, "HASH14"); !errors.Is(err, blockdb.ErrNotCommitted) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould report the pending block as not committed : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould leave the pending round out.", success, testID)
+
+			if _, err := core.GetBlockByNum(ctx, 9); kivik.StatusCode(err) != http.StatusNotFound {
+				t.Fatalf("\t\t%s\tTest %d:\tShould report a missing block as not found : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould report a missing block as not found.", success, testID)
+
+			blocks, numOfPages, numOfBlks, err := core.GetBlocksPagination(ctx, 13, "desc", 2, 3)
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to get the second page : %v.", failed, testID, err)
+			}
+			if numOfPages != 2 || numOfBlks != 4 || len(blocks) != 1 || blocks[0].Round != 10 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould get round 10 alone on the second descending page : got %d pages, %d blocks, %+v.", failed, testID, numOfPages, numOfBlks, blocks)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould get round 10 alone on the second descending page.", success, testID)
+
+			if _, _, _, err := core.GetBlocksPagination(ctx, 13, "desc", 3, 3); err == nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould fail for a page out of range.", failed, testID)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould fail for a page out of range.", success, testID)
+
+			speed, err := core.GetBlockTxnSpeed(ctx)
+			if err != nil || speed != 4 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould average the time between blocks : got %v, %v.", failed, testID, speed, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould average the time between blocks.", success, testID)
+		}
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	t.Log("Given the need to list the transactions of an account without CouchDB.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen an account sends transactions over two rounds.", testID)
+		{
+			ctx := context.Background()
+			store := memory.New()
+			core := transaction.NewCoreWithStore(store)
+
+			var txns []models.Transaction
+			for i, round := range []uint64{20, 20, 21} {
+				txns = append(txns, models.Transaction{
+					Id:               fmt.Sprintf("TXN%d", 3-i),
+					Type:             "pay",
+					Sender:           "SENDER",
+					ConfirmedRound:   round,
+					IntraRoundOffset: uint64(i),
+					PaymentTransaction: models.TransactionPayment{
+						Receiver: "RECEIVER",
+					},
+				})
+			}
+			if err := addCommitted(ctx, core, txns, nil); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the transactions : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould be able to add the transactions.", success, testID)
+
+			page, numOfPages, numOfTxns, err := core.GetTransactionsByAcctPagination(ctx, "RECEIVER", "desc", 1, 2)
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to page through the transactions of the receiver : %v.", failed, testID, err)
+			}
+			if numOfPages != 2 || numOfTxns != 3 || len(page) != 2 || page[0].ID != "TXN1" || page[1].ID != "TXN2" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould order the transactions by round and position : got %d pages, %d transactions, %+v.", failed, testID, numOfPages, numOfTxns, page)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould order the transactions by round and position.", success, testID)
+
+			deleted, err := core.DeleteTransactionsBefore(ctx, 21)
+			if err != nil || deleted != 2 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould prune the transactions of round 20 : got %d, %v.", failed, testID, deleted, err)
+			}
+			earliest, err := core.GetEarliestTransaction(ctx)
+			if err != nil || earliest.ID != "TXN1" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould keep the transaction of round 21 : got %q, %v.", failed, testID, earliest.ID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould prune the transactions of round 20.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a stored account is changed by its caller.", testID)
+		{
+			ctx := context.Background()
+			core := account.NewCoreWithStore(memory.New())
+
+			acct := models.Account{Address: "ADDR", Assets: []models.AssetHolding{{AssetId: 1, Amount: 5}}}
+			if _, _, err := core.AddAccount(ctx, acct); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the account : %v.", failed, testID, err)
+			}
+			acct.Assets[0].Amount = 0
+
+			stored, err := core.GetAccount(ctx, "ADDR")
+			if err != nil || stored.Assets[0].Amount != 5 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould keep its own copy of the account : got %+v, %v.", failed, testID, stored, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould keep its own copy of the account.", success, testID)
+		}
+	}
+}
+
+func TestTransactionExtras(t *testing.T) {
+	t.Log("Given the need to keep the fields of the newer transaction types without CouchDB.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen an account sends a heartbeat for itself.", testID)
+		{
+			ctx := context.Background()
+			core := transaction.NewCoreWithStore(memory.New())
+
+			txns := []models.Transaction{{Id: "HB", Type: "hb", Sender: "NODE", ConfirmedRound: 60}}
+			extras := []txnfields.TransactionExtras{{HeartbeatTransaction: &txnfields.TransactionHeartbeat{HbAddress: "NODE"}}}
+			if err := addCommitted(ctx, core, txns, extras); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the heartbeat : %v.", failed, testID, err)
+			}
+
+			txn, err := core.GetTransaction(ctx, "HB")
+			if err != nil || txn.HeartbeatTransaction == nil || txn.HeartbeatTransaction.HbAddress != "NODE" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould return the heartbeat fields with the transaction : %+v, %v.", failed, testID, txn.TransactionExtras, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould return the heartbeat fields with the transaction.", success, testID)
+
+			count, err := core.GetTransactionCountByAcct(ctx, "NODE", "HB", "HB")
+			if err != nil || count != 1 || len(txn.AssociatedAccounts) != 1 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list the heartbeat once for the account : got %d, %v, %v.", failed, testID, count, txn.AssociatedAccounts, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould list the heartbeat once for the account.", success, testID)
+		}
+	}
+}
+
+func TestTransactionsByAsset(t *testing.T) {
+	t.Log("Given the need to page through the transactions of an asset without CouchDB.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen an asset is transferred three times alongside a payment.", testID)
+		{
+			ctx := context.Background()
+			core := transaction.NewCoreWithStore(memory.New())
+
+			var txns []models.Transaction
+			for i, roundTime := range []uint64{300, 100, 200} {
+				txns = append(txns, models.Transaction{
+					Id:             fmt.Sprintf("AXFER%d", i),
+					Type:           "axfer",
+					Sender:         "SENDER",
+					ConfirmedRound: 30 + uint64(i),
+					RoundTime:      roundTime,
+					AssetTransferTransaction: models.TransactionAssetTransfer{
+						AssetId:  7,
+						Receiver: "RECEIVER",
+					},
+				})
+			}
+			txns = append(txns, models.Transaction{Id: "PAY", Type: "pay", Sender: "SENDER", ConfirmedRound: 33, RoundTime: 400})
+			if err := addCommitted(ctx, core, txns, nil); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the transactions : %v.", failed, testID, err)
+			}
+
+			count, err := core.GetTransactionCountByAsset(ctx, "7")
+			if err != nil || count != 3 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould count the three transfers : got %d, %v.", failed, testID, count, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould count the three transfers.", success, testID)
+
+			page, numOfPages, numOfTxns, err := core.GetTransactionsByAssetPagination(ctx, "7", "desc", 1, 2)
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to page through the transfers : %v.", failed, testID, err)
+			}
+			if numOfPages != 2 || numOfTxns != 3 || len(page) != 2 || page[0].ID != "AXFER2" || page[1].ID != "AXFER1" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould order the transfers by round : got %d pages, %d transactions, %+v.", failed, testID, numOfPages, numOfTxns, page)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould order the transfers by round.", success, testID)
+		}
+	}
+}
+
+func TestTransactionsByRound(t *testing.T) {
+	t.Log("Given the need to page through the transactions of a round without CouchDB.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a round holds three transactions and the next one a single one.", testID)
+		{
+			ctx := context.Background()
+			core := transaction.NewCoreWithStore(memory.New())
+
+			txns := []models.Transaction{
+				{Id: "PAY2", Type: "pay", Sender: "SENDER", ConfirmedRound: 40, IntraRoundOffset: 2},
+				{Id: "PAY0", Type: "pay", Sender: "SENDER", ConfirmedRound: 40, IntraRoundOffset: 0},
+				{Id: "PAY1", Type: "pay", Sender: "SENDER", ConfirmedRound: 40, IntraRoundOffset: 1},
+				{Id: "NEXT", Type: "pay", Sender: "SENDER", ConfirmedRound: 41},
+			}
+			if err := addCommitted(ctx, core, txns, nil); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the transactions : %v.", failed, testID, err)
+			}
+
+			count, err := core.GetTransactionCountByRound(ctx, 40)
+			if err != nil || count != 3 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould count the three transactions of the round : got %d, %v.", failed, testID, count, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould count the three transactions of the round.", success, testID)
+
+			page, numOfPages, numOfTxns, err := core.GetTransactionsByRoundPagination(ctx, 40, "asc", 2, 2)
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to page through the round : %v.", failed, testID, err)
+			}
+			if numOfPages != 2 || numOfTxns != 3 || len(page) != 1 || page[0].ID != "PAY2" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould order the round by offset : got %d pages, %d transactions, %+v.", failed, testID, numOfPages, numOfTxns, page)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould order the round by offset.", success, testID)
+		}
+	}
+}
+
+func TestGroups(t *testing.T) {
+	t.Log("Given the need to look up atomic groups without CouchDB.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen two payments of a round are grouped and a third is not.", testID)
+		{
+			ctx := context.Background()
+			core := transaction.NewCoreWithStore(memory.New())
+
+			// Every byte set makes the standard and URL-safe base64 differ.
+			groupID := bytes.Repeat([]byte{0xff}, 32)
+			payment := func(id, sender, receiver string, offset, fee uint64, group []byte) models.Transaction {
+				return models.Transaction{
+					Id:                 id,
+					Type:               "pay",
+					Sender:             sender,
+					Fee:                fee,
+					Group:              group,
+					ConfirmedRound:     50,
+					IntraRoundOffset:   offset,
+					PaymentTransaction: models.TransactionPayment{Receiver: receiver},
+				}
+			}
+			txns := []models.Transaction{
+				payment("SECOND", "BOB", "ALICE", 1, 2000, groupID),
+				payment("FIRST", "ALICE", "BOB", 0, 1000, groupID),
+				payment("ALONE", "ALICE", "CAROL", 2, 1000, nil),
+			}
+			if err := addCommitted(ctx, core, txns, nil); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to add the transactions : %v.", failed, testID, err)
+			}
+
+			group, err := core.GetGroup(ctx, base64.RawURLEncoding.EncodeToString(groupID))
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to get the group by its URL-safe ID : %v.", failed, testID, err)
+			}
+			if group.ID != base64.StdEncoding.EncodeToString(groupID) || group.Round != 50 || group.NumOfTxns != 2 ||
+				group.Transactions[0].ID != "FIRST" || group.Transactions[1].ID != "SECOND" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould list the members in order : got %+v.", failed, testID, group)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould list the members in order.", success, testID)
+
+			if group.TotalFees != 3000 || len(group.Accounts) != 2 || group.Accounts[0] != "ALICE" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould sum the fees and list each account once : got %d, %v.", failed, testID, group.TotalFees, group.Accounts)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould sum the fees and list each account once.", success, testID)
+
+			if _, err := core.GetGroup(ctx, base64.StdEncoding.EncodeToString(make([]byte, 32))); !errors.Is(err, transaction.ErrGroupNotFound) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould report an unknown group as not found : %v.", failed, testID, err)
+			}
+			if _, err := core.GetGroup(ctx, "FIRST"); !errors.Is(err, transaction.ErrInvalidGroupID) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould reject an ID that is not a group ID : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould tell unknown groups from invalid IDs.", success, testID)
+		}
+	}
+}
+
+func TestBlocksByCursor(t *testing.T) {
+	t.Log("Given the need to page through blocks with cursors without CouchDB.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen rounds are read two at a time in descending order while more are added.", testID)
+		{
+			ctx := context.Background()
+			core := block.NewCoreWithStore(memory.New())
+
+			addBlock := func(round uint64) {
+				docID, _, err := core.AddBlock(ctx, blockdb.NewBlock{
+					Block:     models.Block{Round: round},
+					BlockHash: fmt.Sprintf("HASH%d", round),
+				})
+				if err != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to add block %d : %v.", failed, testID, round, err)
+				}
+				if err := core.CommitBlock(ctx, docID); err != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to commit block %d : %v.", failed, testID, round, err)
+				}
+			}
+			for round := uint64(10); round <= 14; round++ {
+				addBlock(round)
+			}
+
+			read := func(token string, want string) cursor.Links {
+				var cur *cursor.Cursor
+				if token != "" {
+					var err error
+					if cur, err = cursor.Decode(token); err != nil {
+						t.Fatalf("\t\t%s\tTest %d:\tShould be able to decode the cursor : %v.", failed, testID, err)
+					}
+				}
+				blocks, links, err := core.GetBlocksByCursor(ctx, cur, "desc", 2)
+				if err != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to read blocks : %v.", failed, testID, err)
+				}
+				var got []uint64
+				for _, block := range blocks {
+					got = append(got, block.Round)
+				}
+				if fmt.Sprint(got) != want {
+					t.Fatalf("\t\t%s\tTest %d:\tShould read rounds %s : got %v.", failed, testID, want, got)
+				}
+				return links
+			}
+
+			first := read("", "[14 13]")
+			if first.Prev != "" || first.Next == "" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould link the first page to the next one only : %+v.", failed, testID, first)
+			}
+			addBlock(15)
+			second := read(first.Next, "[12 11]")
+			last := read(second.Next, "[10]")
+			if last.Prev == "" || last.Next != "" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould link the last page to the previous one only : %+v.", failed, testID, last)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould read every round once while rounds are added.", success, testID)
+
+			read(last.Prev, "[12 11]")
+			back := read(second.Prev, "[14 13]")
+			read(back.Prev, "[15]")
+			t.Logf("\t\t%s\tTest %d:\tShould read the pages back up to the round added.", success, testID)
+		}
+	}
+}
+
+func TestPendingTransactions(t *testing.T) {
+	t.Log("Given the need to hide the transactions of rounds not fully saved without CouchDB.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a round is saved, committed and saved again.", testID)
+		{
+			ctx := context.Background()
+			store := memory.New()
+			blockCore := block.NewCoreWithStore(store)
+			txnCore := transaction.NewCoreWithStore(store)
+
+			newBlock := blockdb.NewBlock{Block: models.Block{Round: 70}, BlockHash: "HASH70"}
+			txns := []models.Transaction{{Id: "PAY", Type: "pay", Sender: "SENDER", ConfirmedRound: 70}}
+			save := func() (string, error) {
+				docID, _, err := blockCore.AddBlock(ctx, newBlock)
+				if err != nil {
+					return "", err
+				}
+				_, err = txnCore.AddTransactions(ctx, txns, nil, types.Block{})
+				return docID, err
+			}
+
+			docID, err := save()
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to save the round : %v.", failed, testID, err)
+			}
+			if _, err := txnCore.GetTransaction(ctx, "PAY"); !errors.Is(err, txndb.ErrNotCommitted) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould report the transaction as not committed : got %v.", failed, testID, err)
+			}
+			if acctTxns, err := txnCore.GetTransactionsByAcct(ctx, "SENDER", "asc"); err != nil || len(acctTxns) != 0 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould leave the pending transaction out : got %d, %v.", failed, testID, len(acctTxns), err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould hide the transaction until the round is committed.", success, testID)
+
+			if err := txnCore.CommitTransactions(ctx, 70); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to commit the transactions : %v.", failed, testID, err)
+			}
+			if err := blockCore.CommitBlock(ctx, docID); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to commit the block : %v.", failed, testID, err)
+			}
+			if _, err := txnCore.GetTransaction(ctx, "PAY"); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould return the committed transaction : %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould show the transaction once the round is committed.", success, testID)
+
+			if _, err := save(); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to save the round again : %v.", failed, testID, err)
+			}
+			if _, err := blockCore.GetBlockByHash(ctx, "HASH70"); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould keep the block committed : %v.", failed, testID, err)
+			}
+			if acctTxns, err := txnCore.GetTransactionsByAcct(ctx, "SENDER", "asc"); err != nil || len(acctTxns) != 1 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould keep the transaction committed : got %d, %v.", failed, testID, len(acctTxns), err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould keep the round visible when it is saved again.", success, testID)
+		}
+	}
+}
+
+func TestLease(t *testing.T) {
+	t.Log("Given the need to let a single instance hold the sync lease without CouchDB.")
+	{
+		ctx := context.Background()
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen an instance takes a free lease and renews it.", testID)
+		{
+			core := syncstate.NewCoreWithStore(memory.New())
+
+			lease, held, err := core.AcquireLease(ctx, "A", time.Minute)
+			if err != nil || !held || lease.Holder != "A" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould take the free lease : got %q, %t, %v.", failed, testID, lease.Holder, held, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould take the free lease.", success, testID)
+
+			renewed, held, err := core.AcquireLease(ctx, "A", time.Minute)
+			if err != nil || !held {
+				t.Fatalf("\t\t%s\tTest %d:\tShould renew the lease : got %t, %v.", failed, testID, held, err)
+			}
+			if !renewed.AcquiredAt.Equal(lease.AcquiredAt) || renewed.ExpiresAt.Before(lease.ExpiresAt) || renewed.Rev == lease.Rev {
+				t.Fatalf("\t\t%s\tTest %d:\tShould extend the lease under a new revision : got %+v after %+v.", failed, testID, renewed, lease)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould extend the lease under a new revision.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen another instance tries to take the lease.", testID)
+		{
+			core := syncstate.NewCoreWithStore(memory.New())
+			if _, _, err := core.AcquireLease(ctx, "A", time.Minute); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to take the lease : %v.", failed, testID, err)
+			}
+
+			lease, held, err := core.AcquireLease(ctx, "B", time.Minute)
+			if err != nil || held || lease.Holder != "A" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould leave the lease to its holder : got %q, %t, %v.", failed, testID, lease.Holder, held, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould leave an unexpired lease to its holder.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the lease expires or is released.", testID)
+		{
+			core := syncstate.NewCoreWithStore(memory.New())
+			if _, _, err := core.AcquireLease(ctx, "A", time.Millisecond); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to take the lease : %v.", failed, testID, err)
+			}
+			time.Sleep(5 * time.Millisecond)
+
+			lease, held, err := core.AcquireLease(ctx, "B", time.Minute)
+			if err != nil || !held || lease.Holder != "B" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould take over the expired lease : got %q, %t, %v.", failed, testID, lease.Holder, held, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould take over the expired lease.", success, testID)
+
+			if err := core.ReleaseLease(ctx, "A"); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould ignore a release by a former holder : %v.", failed, testID, err)
+			}
+			if lease, _, err := core.GetLease(ctx); err != nil || !lease.HeldBy("B", time.Now()) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould keep the lease held after a release by a former holder : got %+v, %v.", failed, testID, lease, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould ignore a release by a former holder.", success, testID)
+
+			if err := core.ReleaseLease(ctx, "B"); err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to release the lease : %v.", failed, testID, err)
+			}
+			if lease, held, err := core.AcquireLease(ctx, "A", time.Minute); err != nil || !held {
+				t.Fatalf("\t\t%s\tTest %d:\tShould take the released lease right away : got %q, %t, %v.", failed, testID, lease.Holder, held, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould take the released lease right away.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen instances race for the free lease.", testID)
+		{
+			core := syncstate.NewCoreWithStore(memory.New())
+
+			const racers = 20
+			var wg sync.WaitGroup
+			won := make([]bool, racers)
+			holders := make([]string, racers)
+			errs := make([]error, racers)
+			wg.Add(racers)
+			for i := 0; i < racers; i++ {
+				go func(i int) {
+					defer wg.Done()
+					lease, held, err := core.AcquireLease(ctx, fmt.Sprintf("instance-%d", i), time.Minute)
+					won[i], holders[i], errs[i] = held, lease.Holder, err
+				}(i)
+			}
+			wg.Wait()
+
+			var winner string
+			for i := 0; i < racers; i++ {
+				if errs[i] != nil {
+					t.Fatalf("\t\t%s\tTest %d:\tShould be able to race for the lease : %v.", failed, testID, errs[i])
+				}
+				if won[i] {
+					if winner != "" {
+						t.Fatalf("\t\t%s\tTest %d:\tShould let a single instance win : %s and %s both won.", failed, testID, winner, holders[i])
+					}
+					winner = holders[i]
+				}
+			}
+			if winner == "" {
+				t.Fatalf("\t\t%s\tTest %d:\tShould let an instance win.", failed, testID)
+			}
+			for i := 0; i < racers; i++ {
+				if holders[i] != winner {
+					t.Fatalf("\t\t%s\tTest %d:\tShould report the winner to every loser : got %s, want %s.", failed, testID, holders[i], winner)
+				}
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould let a single instance win.", success, testID)
+		}
+	}
+}
+
+// addCommitted adds transactions and commits their rounds, the way the ingester
+// does once everything else of a round is saved.
+func addCommitted(ctx context.Context, core transaction.Core, txns []models.Transaction, extras []txnfields.TransactionExtras) error {
+	if _, err := core.AddTransactions(ctx, txns, extras, types.Block{}); err != nil {
+		return err
+	}
+	for _, txn := range txns {
+		if err := core.CommitTransactions(ctx, txn.ConfirmedRound); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	syncdb "github.com/kevguy/algosearch/backend/business/core/syncstate/db"
+)
+
+// GetSyncState retrieves the sync state. The boolean returned is false when no
+// sync state has been recorded yet.
+func (s *Store) GetSyncState(ctx context.Context) (syncdb.SyncState, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.syncState == nil {
+		return syncdb.SyncState{}, false, nil
+	}
+	return *s.syncState, true, nil
+}
+
+// SetLastSyncedRound records round as the highest round for which it and every
+// round before it have been synced.
+func (s *Store) SetLastSyncedRound(ctx context.Context, round uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putSyncState(round, false)
+	return nil
+}
+
+// ResetSyncState records that no round has been synced, so syncing starts over
+// from the first round.
+func (s *Store) ResetSyncState(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putSyncState(0, true)
+	return nil
+}
+
+// putSyncState writes the sync state document.
+func (s *Store) putSyncState(round uint64, nothingSynced bool) {
+	var doc syncdb.SyncState
+	if s.syncState != nil {
+		doc = *s.syncState
+	}
+	doc.ID = syncdb.SyncStateDocID
+	doc.Rev = s.nextRev(doc.Rev)
+	doc.DocType = syncdb.DocType
+	doc.LastSyncedRound = round
+	doc.NothingSynced = nothingSynced
+	doc.UpdatedAt = time.Now().UTC()
+	s.syncState = &doc
+}
+
+// RecordFailedRound adds or updates the record of a round that could not be
+// ingested. Attempts are added to the ones already recorded for the round.
+func (s *Store) RecordFailedRound(ctx context.Context, round uint64, attempts int, failure error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	doc, found := s.failedRounds[round]
+	if !found {
+		doc = syncdb.FailedRound{
+			ID:            fmt.Sprintf("%s.%d", syncdb.FailedRoundDocType, round),
+			DocType:       syncdb.FailedRoundDocType,
+			Round:         round,
+			FirstFailedAt: now,
+		}
+	}
+	doc.Rev = s.nextRev(doc.Rev)
+	doc.Attempts += attempts
+	doc.LastFailedAt = now
+	if failure != nil {
+		doc.Error = failure.Error()
+	}
+	s.failedRounds[round] = doc
+
+	return nil
+}
+
+// GetFailedRound retrieves the record of a failed round. The boolean returned is
+// false when the round is not recorded as failed.
+func (s *Store) GetFailedRound(ctx context.Context, round uint64) (syncdb.FailedRound, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	doc, found := s.failedRounds[round]
+	return doc, found, nil
+}
+
+// GetFailedRounds retrieves every round recorded as failed, in ascending round
+// order.
+func (s *Store) GetFailedRounds(ctx context.Context) ([]syncdb.FailedRound, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var fetchedRounds = []syncdb.FailedRound{}
+	for _, doc := range s.failedRounds {
+		fetchedRounds = append(fetchedRounds, doc)
+	}
+	sort.Slice(fetchedRounds, func(i, j int) bool {
+		return fetchedRounds[i].Round < fetchedRounds[j].Round
+	})
+	return fetchedRounds, nil
+}
+
+// DeleteFailedRound removes the record of a failed round. It is not an error if
+// the round is not recorded.
+func (s *Store) DeleteFailedRound(ctx context.Context, round uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failedRounds, round)
+	return nil
+}
+
+// GetLease retrieves the sync lease. The boolean returned is false when no
+// instance has ever taken the lease.
+func (s *Store) GetLease(ctx context.Context) (syncdb.SyncLease, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.lease == nil {
+		return syncdb.SyncLease{}, false, nil
+	}
+	return *s.lease, true, nil
+}
+
+// AcquireLease takes or renews the sync lease for holder, for ttl from now. The
+// lease can only be taken when it is free or has expired. The boolean returned
+// reports whether holder holds the lease, along with the lease as last seen.
+func (s *Store) AcquireLease(ctx context.Context, holder string, ttl time.Duration) (syncdb.SyncLease, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lease syncdb.SyncLease
+	if s.lease != nil {
+		lease = *s.lease
+	}
+
+	now := time.Now().UTC()
+	if s.lease != nil && lease.Holder != holder && now.Before(lease.ExpiresAt) {
+		return lease, false, nil
+	}
+
+	if s.lease == nil || lease.Holder != holder {
+		lease.AcquiredAt = now
+	}
+	lease.ID = syncdb.SyncLeaseDocID
+	lease.Rev = s.nextRev(lease.Rev)
+	lease.DocType = syncdb.LeaseDocType
+	lease.Holder = holder
+	lease.RenewedAt = now
+	lease.ExpiresAt = now.Add(ttl)
+	s.lease = &lease
+
+	return lease, true, nil
+}
+
+// ReleaseLease gives up the sync lease if holder holds it.
+func (s *Store) ReleaseLease(ctx context.Context, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	if s.lease == nil || !s.lease.HeldBy(holder, now) {
+		return nil
+	}
+
+	lease := *s.lease
+	lease.Rev = s.nextRev(lease.Rev)
+	lease.ExpiresAt = now
+	s.lease = &lease
+
+	return nil
+}
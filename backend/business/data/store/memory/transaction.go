@@ -0,0 +1,508 @@
+package memory
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+	txndb "github.com/kevguy/algosearch/backend/business/core/transaction/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+)
+
+// AddTransaction adds or replaces a transaction.
+func (s *Store) AddTransaction(ctx context.Context, transaction models.Transaction, blockInfo types.Block) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rev := s.putTransaction(txndb.NewTransactionDoc(transaction, txnfields.TransactionExtras{}, blockInfo))
+	return transaction.Id, rev, nil
+}
+
+// AddTransactions adds or replaces the transactions of a block, along with the
+// fields of each the SDK's model has no room for.
+func (s *Store) AddTransactions(ctx context.Context, transactions []models.Transaction, extras []txnfields.TransactionExtras, blockInfo types.Block) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range transactions {
+		var txnExtras txnfields.TransactionExtras
+		if i < len(extras) {
+			txnExtras = extras[i]
+		}
+		s.putTransaction(txndb.NewTransactionDoc(transactions[i], txnExtras, blockInfo))
+	}
+
+	return true, nil
+}
+
+// putTransaction stores a transaction document under the transaction ID and
+// returns its new revision. The transaction is pending until CommitTransactions
+// is called for its round, unless the block of the round is already committed.
+func (s *Store) putTransaction(newDoc txndb.NewTransaction) string {
+	var doc txndb.Transaction
+	clone(&doc, newDoc)
+	doc.ID = newDoc.Id
+	doc.Pending = !s.roundCommitted(doc.ConfirmedRound)
+	doc.Rev = s.nextRev(s.transactions[doc.ID].Rev)
+	s.transactions[doc.ID] = doc
+	return doc.Rev
+}
+
+// GetTransaction retrieves a transaction based upon its ID.
+func (s *Store) GetTransaction(ctx context.Context, transactionID string) (txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	doc, ok := s.transactions[transactionID]
+	if !ok {
+		return txndb.Transaction{}, store.NotFound("transaction %s not found", transactionID)
+	}
+	if doc.Pending {
+		return txndb.Transaction{}, txndb.ErrNotCommitted
+	}
+
+	var transaction txndb.Transaction
+	clone(&transaction, doc)
+	return transaction, nil
+}
+
+// GetInnerTransaction retrieves an inner transaction based upon the ID of the
+// top-level transaction it belongs to and its path.
+func (s *Store) GetInnerTransaction(ctx context.Context, parentID string, path []int) (models.Transaction, error) {
+	parent, err := s.GetTransaction(ctx, parentID)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	txn, ok := txndb.InnerTxnAt(parent.Transaction, path)
+	if !ok {
+		return models.Transaction{}, fmt.Errorf("transaction %s, path %v: %w", parentID, path, txndb.ErrInnerTxnNotFound)
+	}
+	return txn, nil
+}
+
+// GetTransactionCountBtnKeys retrieves the number of transactions from the one
+// with ID startKey to the one with ID endKey, both included.
+func (s *Store) GetTransactionCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.countBetween(s.transactionsWhere(func(txndb.Transaction) bool { return true }), startKey, endKey)
+}
+
+// GetEarliestTransaction retrieves the first transaction of the lowest round.
+func (s *Store) GetEarliestTransaction(ctx context.Context) (txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return firstOf(s.transactionsWhere(func(txndb.Transaction) bool { return true }), false)
+}
+
+// GetLatestTransaction retrieves the last transaction of the highest round.
+func (s *Store) GetLatestTransaction(ctx context.Context) (txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return firstOf(s.transactionsWhere(func(txndb.Transaction) bool { return true }), true)
+}
+
+// GetTransactionsPagination retrieves a page of transactions. Like the CouchDB
+// store, pages are counted from the latest transaction whatever the starting
+// transaction given.
+func (s *Store) GetTransactionsPagination(ctx context.Context, startTransactionID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return pageOf(s.transactionsWhere(func(txndb.Transaction) bool { return true }), order, pageNo, limit)
+}
+
+// GetTransactionsFrom retrieves up to limit transactions read from cur, or from
+// the start of the list when cur is nil, in the given order. The boolean
+// returned reports whether transactions are left past them in the direction
+// they were read in.
+func (s *Store) GetTransactionsFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]txndb.Transaction, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return transactionsFrom(s.transactionsWhere(func(txndb.Transaction) bool { return true }), cur, order, limit)
+}
+
+// GetTransactionCountByRound retrieves the number of transactions confirmed in
+// a round.
+func (s *Store) GetTransactionCountByRound(ctx context.Context, round uint64) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.transactionsWhere(inRound(round)))), nil
+}
+
+// GetTransactionsByRoundPagination retrieves a page of the transactions
+// confirmed in a round.
+func (s *Store) GetTransactionsByRoundPagination(ctx context.Context, round uint64, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return pageOf(s.transactionsWhere(inRound(round)), order, pageNo, limit)
+}
+
+// GetEarliestAcctTransaction retrieves the earliest transaction of an account.
+func (s *Store) GetEarliestAcctTransaction(ctx context.Context, acctID string) (txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return firstOf(s.transactionsWhere(involvesAccount(acctID)), false)
+}
+
+// GetLatestAcctTransaction retrieves the latest transaction of an account.
+func (s *Store) GetLatestAcctTransaction(ctx context.Context, acctID string) (txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return firstOf(s.transactionsWhere(involvesAccount(acctID)), true)
+}
+
+// GetTransactionCountByAcct retrieves the number of transactions of an account
+// from the one with ID startKey to the one with ID endKey, both included.
+func (s *Store) GetTransactionCountByAcct(ctx context.Context, acctID, startKey, endKey string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.countBetween(s.transactionsWhere(involvesAccount(acctID)), startKey, endKey)
+}
+
+// GetTransactionsByAcctPagination retrieves a page of the transactions of an
+// account.
+func (s *Store) GetTransactionsByAcctPagination(ctx context.Context, acctID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return pageOf(s.transactionsWhere(involvesAccount(acctID)), order, pageNo, limit)
+}
+
+// GetTransactionsByAcctFrom retrieves up to limit transactions of an account
+// read from cur, or from the start of the list when cur is nil, in the given
+// order. The boolean returned reports whether transactions are left past them
+// in the direction they were read in.
+func (s *Store) GetTransactionsByAcctFrom(ctx context.Context, acctID string, cur *cursor.Cursor, order string, limit int64) ([]txndb.Transaction, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return transactionsFrom(s.transactionsWhere(involvesAccount(acctID)), cur, order, limit)
+}
+
+// GetTransactionsByAcct retrieves every transaction of an account, in ascending
+// order when order is "asc" and descending order otherwise.
+func (s *Store) GetTransactionsByAcct(ctx context.Context, acctID string, order string) ([]txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return inOrder(s.transactionsWhere(involvesAccount(acctID)), order != "asc"), nil
+}
+
+// GetEarliestAppTransaction retrieves the earliest transaction of an application.
+func (s *Store) GetEarliestAppTransaction(ctx context.Context, appID string) (txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return firstOf(s.transactionsWhere(involvesApp(appID)), false)
+}
+
+// GetLatestAppTransaction retrieves the latest transaction of an application.
+func (s *Store) GetLatestAppTransaction(ctx context.Context, appID string) (txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return firstOf(s.transactionsWhere(involvesApp(appID)), true)
+}
+
+// GetTransactionsByApp retrieves every transaction of an application, in
+// ascending order when order is "asc" and descending order otherwise.
+func (s *Store) GetTransactionsByApp(ctx context.Context, appID string, order string) ([]txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return inOrder(s.transactionsWhere(involvesApp(appID)), order != "asc"), nil
+}
+
+// GetTransactionCountByApp retrieves the number of transactions of an
+// application.
+func (s *Store) GetTransactionCountByApp(ctx context.Context, appID string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.transactionsWhere(involvesApp(appID)))), nil
+}
+
+// GetTransactionsByAppPagination retrieves a page of the transactions of an
+// application.
+func (s *Store) GetTransactionsByAppPagination(ctx context.Context, appID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return pageOf(s.transactionsWhere(involvesApp(appID)), order, pageNo, limit)
+}
+
+// GetEarliestAssetTransaction retrieves the earliest transaction of an asset.
+func (s *Store) GetEarliestAssetTransaction(ctx context.Context, assetID string) (txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return firstOf(s.transactionsWhere(involvesAsset(assetID)), false)
+}
+
+// GetLatestAssetTransaction retrieves the latest transaction of an asset.
+func (s *Store) GetLatestAssetTransaction(ctx context.Context, assetID string) (txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return firstOf(s.transactionsWhere(involvesAsset(assetID)), true)
+}
+
+// GetTransactionsByAsset retrieves every transaction of an asset, in ascending
+// order when order is "asc" and descending order otherwise.
+func (s *Store) GetTransactionsByAsset(ctx context.Context, assetID string, order string) ([]txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return inOrder(s.transactionsWhere(involvesAsset(assetID)), order != "asc"), nil
+}
+
+// GetTransactionCountByAsset retrieves the number of transactions of an asset.
+func (s *Store) GetTransactionCountByAsset(ctx context.Context, assetID string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.transactionsWhere(involvesAsset(assetID)))), nil
+}
+
+// GetTransactionsByAssetPagination retrieves a page of the transactions of an
+// asset.
+func (s *Store) GetTransactionsByAssetPagination(ctx context.Context, assetID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return pageOf(s.transactionsWhere(involvesAsset(assetID)), order, pageNo, limit)
+}
+
+// GetTransactionsByType retrieves up to limit transactions of a type, in
+// ascending order when order is "asc" and descending order otherwise.
+func (s *Store) GetTransactionsByType(ctx context.Context, txnType string, order string, limit int64) ([]txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	txns := inOrder(s.transactionsWhere(func(txn txndb.Transaction) bool { return txn.Type == txnType }), order != "asc")
+	if limit >= 0 && int64(len(txns)) > limit {
+		txns = txns[:limit]
+	}
+	return txns, nil
+}
+
+// GetTransactionsByGroup retrieves the transactions of an atomic group, given
+// the base64 of its ID, ordered by round and by position within the round.
+func (s *Store) GetTransactionsByGroup(ctx context.Context, groupID string) ([]txndb.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return inOrder(s.transactionsWhere(inGroup(groupID)), false), nil
+}
+
+// DeleteTransactionsBefore deletes every transaction confirmed before round and
+// returns how many were deleted.
+func (s *Store) DeleteTransactionsBefore(ctx context.Context, round uint64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int
+	for id, txn := range s.transactions {
+		if txn.ConfirmedRound < round {
+			delete(s.transactions, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// CommitTransactions marks the pending transactions of round as committed.
+func (s *Store) CommitTransactions(ctx context.Context, round uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, txn := range s.transactions {
+		if txn.ConfirmedRound == round && txn.Pending {
+			txn.Pending = false
+			txn.Rev = s.nextRev(txn.Rev)
+			s.transactions[id] = txn
+		}
+	}
+
+	return nil
+}
+
+// transactionsWhere returns the committed transactions matching keep, in the
+// order of the CouchDB view on transactions: by round, position within the
+// round and ID.
+func (s *Store) transactionsWhere(keep func(txndb.Transaction) bool) []txndb.Transaction {
+	var txns []txndb.Transaction
+	for _, txn := range s.transactions {
+		if !txn.Pending && keep(txn) {
+			txns = append(txns, txn)
+		}
+	}
+	sort.Slice(txns, func(i, j int) bool {
+		return txnLess(txns[i], txns[j])
+	})
+	return txns
+}
+
+// txnLess reports whether a comes before b by round, position within the round
+// and ID.
+func txnLess(a, b txndb.Transaction) bool {
+	if a.ConfirmedRound != b.ConfirmedRound {
+		return a.ConfirmedRound < b.ConfirmedRound
+	}
+	if a.IntraRoundOffset != b.IntraRoundOffset {
+		return a.IntraRoundOffset < b.IntraRoundOffset
+	}
+	return a.ID < b.ID
+}
+
+// countBetween counts the transactions of txns from the one with ID startKey to
+// the one with ID endKey, both included. Both have to exist.
+func (s *Store) countBetween(txns []txndb.Transaction, startKey, endKey string) (int64, error) {
+	first, ok := s.transactions[startKey]
+	if !ok {
+		return 0, fmt.Errorf("fetch earliest transaction error: %w", store.NotFound("transaction %s not found", startKey))
+	}
+	last, ok := s.transactions[endKey]
+	if !ok {
+		return 0, fmt.Errorf("fetch latest transaction error: %w", store.NotFound("transaction %s not found", endKey))
+	}
+
+	var count int64
+	for _, txn := range txns {
+		if !txnLess(txn, first) && !txnLess(last, txn) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// firstOf returns a copy of the first of txns, or the last one when latest is
+// set.
+func firstOf(txns []txndb.Transaction, latest bool) (txndb.Transaction, error) {
+	if len(txns) == 0 {
+		return txndb.Transaction{}, store.NotFound("no transaction found")
+	}
+
+	doc := txns[0]
+	if latest {
+		doc = txns[len(txns)-1]
+	}
+
+	var txn txndb.Transaction
+	clone(&txn, doc)
+	return txn, nil
+}
+
+// inOrder returns copies of txns, reversed when descending is set.
+func inOrder(txns []txndb.Transaction, descending bool) []txndb.Transaction {
+	fetched := make([]txndb.Transaction, len(txns))
+	for i := range txns {
+		j := i
+		if descending {
+			j = len(txns) - 1 - i
+		}
+		clone(&fetched[i], txns[j])
+	}
+	return fetched
+}
+
+// pageOf returns a page of txns, counted from the latest one when order is
+// "desc", along with the number of pages and of transactions.
+func pageOf(txns []txndb.Transaction, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	if len(txns) == 0 {
+		return nil, 0, 0, store.NotFound("no transaction found")
+	}
+
+	var numOfTransactions = int64(len(txns))
+	from, to, numOfPages, err := store.Page(numOfTransactions, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return inOrder(txns, order == "desc")[from:to], numOfPages, numOfTransactions, nil
+}
+
+// transactionsFrom returns copies of the page of txns read from cur, along with
+// whether transactions are left past it in the direction it was read in.
+func transactionsFrom(txns []txndb.Transaction, cur *cursor.Cursor, order string, limit int64) ([]txndb.Transaction, bool, error) {
+	indexes, more, err := readFrom(len(txns), func(i int) cursor.Position { return txns[i].Position() }, cur, order, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	fetched := make([]txndb.Transaction, len(indexes))
+	for k, i := range indexes {
+		clone(&fetched[k], txns[i])
+	}
+	return fetched, more, nil
+}
+
+// involvesAccount matches the transactions an account is involved in.
+func involvesAccount(acctID string) func(txndb.Transaction) bool {
+	return func(txn txndb.Transaction) bool {
+		for _, acct := range txn.AssociatedAccounts {
+			if acct == acctID {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// involvesApp matches the transactions an application is involved in.
+func involvesApp(appID string) func(txndb.Transaction) bool {
+	id, err := strconv.ParseUint(appID, 10, 64)
+	return func(txn txndb.Transaction) bool {
+		return err == nil && containsID(txn.AssociatedApplications, id)
+	}
+}
+
+// inRound matches the transactions confirmed in a round.
+func inRound(round uint64) func(txndb.Transaction) bool {
+	return func(txn txndb.Transaction) bool {
+		return txn.ConfirmedRound == round
+	}
+}
+
+// inGroup matches the transactions of an atomic group.
+func inGroup(groupID string) func(txndb.Transaction) bool {
+	return func(txn txndb.Transaction) bool {
+		return len(txn.Group) > 0 && base64.StdEncoding.EncodeToString(txn.Group) == groupID
+	}
+}
+
+// involvesAsset matches the transactions an asset is involved in.
+func involvesAsset(assetID string) func(txndb.Transaction) bool {
+	id, err := strconv.ParseUint(assetID, 10, 64)
+	return func(txn txndb.Transaction) bool {
+		return err == nil && containsID(txn.AssociatedAssets, id)
+	}
+}
+
+// containsID reports whether ids holds id.
+func containsID(ids []uint64, id uint64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/jmoiron/sqlx"
+	accountdb "github.com/kevguy/algosearch/backend/business/core/account/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
+)
+
+// AddAccount adds or replaces an account, stored under its address.
+func (s Store) AddAccount(ctx context.Context, account models.Account) (string, string, error) {
+	rev, err := s.putAccount(ctx, account)
+	if err != nil {
+		return "", "", fmt.Errorf("inserting account %s: %w", account.Address, err)
+	}
+
+	return account.Address, rev, nil
+}
+
+// AddAccounts adds or replaces a batch of accounts.
+func (s Store) AddAccounts(ctx context.Context, accounts []models.Account) (bool, error) {
+	tran := func(tx sqlx.ExtContext) error {
+		st := s.Tran(tx)
+		for _, account := range accounts {
+			if _, err := st.putAccount(ctx, account); err != nil {
+				return fmt.Errorf("inserting account %s: %w", account.Address, err)
+			}
+		}
+		return nil
+	}
+
+	if err := s.WithinTran(ctx, tran); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// putAccount upserts an account document and returns its new revision.
+func (s Store) putAccount(ctx context.Context, account models.Account) (string, error) {
+	doc, err := encode(accountdb.NewAccount{Account: account, DocType: accountdb.DocType})
+	if err != nil {
+		return "", err
+	}
+
+	data := struct {
+		Address string `db:"address"`
+		Doc     string `db:"doc"`
+	}{
+		Address: account.Address,
+		Doc:     doc,
+	}
+
+	const q = `
+	INSERT INTO accounts
+		(address, revision, doc)
+	VALUES
+		(:address, 1, :doc)
+	ON CONFLICT (address) DO UPDATE SET
+		revision = accounts.revision + 1,
+		doc = EXCLUDED.doc
+	RETURNING
+		revision`
+
+	var rev revision
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &rev); err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(rev.Revision), nil
+}
+
+// GetAccount retrieves an account based upon its address.
+func (s Store) GetAccount(ctx context.Context, accountAddr string) (models.Account, error) {
+	data := struct {
+		Address string `db:"address"`
+	}{
+		Address: accountAddr,
+	}
+
+	const q = `
+	SELECT
+		address AS id, revision, doc
+	FROM
+		accounts
+	WHERE
+		address = :address`
+
+	var doc document
+	missing := func() error { return notFound("account %s not found", accountAddr) }
+	if err := s.queryStruct(ctx, q, data, &doc, missing); err != nil {
+		return models.Account{}, fmt.Errorf("selecting account %s: %w", accountAddr, err)
+	}
+
+	account, err := toAccount(doc)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return account.Account, nil
+}
+
+// GetEarliestAccountID retrieves the lowest account address.
+func (s Store) GetEarliestAccountID(ctx context.Context) (string, error) {
+	return s.accountAddrAt(ctx, "asc")
+}
+
+// GetLatestAccountID retrieves the highest account address.
+func (s Store) GetLatestAccountID(ctx context.Context) (string, error) {
+	return s.accountAddrAt(ctx, "desc")
+}
+
+// accountAddrAt retrieves the first account address in the given order.
+func (s Store) accountAddrAt(ctx context.Context, order string) (string, error) {
+	q := fmt.Sprintf(`
+	SELECT
+		address
+	FROM
+		accounts
+	ORDER BY
+		address %s
+	LIMIT 1`, direction(order))
+
+	var row struct {
+		Address string `db:"address"`
+	}
+	if err := s.queryStruct(ctx, q, struct{}{}, &row, func() error { return notFound("no account found") }); err != nil {
+		return "", fmt.Errorf("selecting account address: %w", err)
+	}
+
+	return row.Address, nil
+}
+
+// GetAccountCountBtnKeys retrieves the number of accounts with addresses from
+// startKey to endKey, both included.
+func (s Store) GetAccountCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	data := struct {
+		StartKey string `db:"start_key"`
+		EndKey   string `db:"end_key"`
+	}{
+		StartKey: startKey,
+		EndKey:   endKey,
+	}
+
+	const q = `
+	SELECT
+		count(*) AS count
+	FROM
+		accounts
+	WHERE
+		address BETWEEN :start_key AND :end_key`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &c); err != nil {
+		return 0, fmt.Errorf("counting accounts: %w", err)
+	}
+
+	return c.Count, nil
+}
+
+// GetAccountsPagination retrieves a page of the accounts up to the address
+// latestAccountID, ordered by address. Descending pages start at
+// latestAccountID.
+func (s Store) GetAccountsPagination(ctx context.Context, latestAccountID string, order string, pageNo, limit int64) ([]accountdb.Account, int64, int64, error) {
+	data := struct {
+		Latest string `db:"latest"`
+		Offset int64  `db:"offset"`
+		Limit  int64  `db:"limit"`
+	}{
+		Latest: latestAccountID,
+		Limit:  limit,
+	}
+
+	const qCount = `
+	SELECT
+		count(*) AS count
+	FROM
+		accounts
+	WHERE
+		address <= :latest`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, qCount, data, &c); err != nil {
+		return nil, 0, 0, fmt.Errorf("counting accounts: %w", err)
+	}
+
+	var numOfAccounts = c.Count
+	offset, _, numOfPages, err := store.Page(numOfAccounts, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	data.Offset = offset
+
+	q := fmt.Sprintf(`
+	SELECT
+		address AS id, revision, doc
+	FROM
+		accounts
+	WHERE
+		address <= :latest
+	ORDER BY
+		address %s
+	OFFSET :offset LIMIT :limit`, direction(order))
+
+	var docs []document
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &docs); err != nil {
+		return nil, 0, 0, fmt.Errorf("selecting accounts: %w", err)
+	}
+
+	var fetchedAccounts = []accountdb.Account{}
+	for _, doc := range docs {
+		account, err := toAccount(doc)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		fetchedAccounts = append(fetchedAccounts, account)
+	}
+
+	return fetchedAccounts, numOfPages, numOfAccounts, nil
+}
+
+// GetAccountsFrom retrieves up to limit accounts read from cur, or from the
+// start of the list when cur is nil, ordered by address in the given order. The
+// boolean returned reports whether accounts are left past them in the
+// direction they were read in.
+func (s Store) GetAccountsFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]accountdb.Account, bool, error) {
+	if limit < 1 {
+		return nil, false, cursor.ErrLimit
+	}
+
+	data := map[string]interface{}{
+		"limit": limit + 1,
+	}
+
+	dir, op := readingOrder(cur, order)
+	where := "TRUE"
+	if cur != nil {
+		where = fmt.Sprintf("address %s :address", op)
+		data["address"] = cur.ID
+	}
+
+	q := fmt.Sprintf(`
+	SELECT
+		address AS id, revision, doc
+	FROM
+		accounts
+	WHERE
+		%s
+	ORDER BY
+		address %s
+	LIMIT :limit`, where, dir)
+
+	var docs []document
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &docs); err != nil {
+		return nil, false, fmt.Errorf("selecting accounts: %w", err)
+	}
+	docs, more := pageFrom(docs, cur, limit)
+
+	var fetchedAccounts = []accountdb.Account{}
+	for _, doc := range docs {
+		account, err := toAccount(doc)
+		if err != nil {
+			return nil, false, err
+		}
+		fetchedAccounts = append(fetchedAccounts, account)
+	}
+
+	return fetchedAccounts, more, nil
+}
+
+// toAccount decodes an account document.
+func toAccount(doc document) (accountdb.Account, error) {
+	var account accountdb.Account
+	if err := doc.decode(&account); err != nil {
+		return accountdb.Account{}, err
+	}
+	account.ID = doc.ID
+	account.Rev = doc.rev()
+
+	return account, nil
+}
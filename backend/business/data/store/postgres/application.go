@@ -0,0 +1,274 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/jmoiron/sqlx"
+	appdb "github.com/kevguy/algosearch/backend/business/core/application/db"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
+)
+
+// AddApplication adds or replaces an application, stored under its index.
+func (s Store) AddApplication(ctx context.Context, application models.Application) (string, string, error) {
+	rev, err := s.putApplication(ctx, application)
+	if err != nil {
+		return "", "", fmt.Errorf("inserting application %d: %w", application.Id, err)
+	}
+
+	return strconv.FormatUint(application.Id, 10), rev, nil
+}
+
+// AddApplications adds or replaces a batch of applications.
+func (s Store) AddApplications(ctx context.Context, applications []models.Application) (bool, error) {
+	tran := func(tx sqlx.ExtContext) error {
+		st := s.Tran(tx)
+		for _, application := range applications {
+			if _, err := st.putApplication(ctx, application); err != nil {
+				return fmt.Errorf("inserting application %d: %w", application.Id, err)
+			}
+		}
+		return nil
+	}
+
+	if err := s.WithinTran(ctx, tran); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// putApplication upserts an application document and returns its new revision.
+func (s Store) putApplication(ctx context.Context, application models.Application) (string, error) {
+	doc, err := encode(appdb.NewApplication{Application: application, DocType: appdb.DocType})
+	if err != nil {
+		return "", err
+	}
+
+	data := struct {
+		AppID uint64 `db:"app_id"`
+		Doc   string `db:"doc"`
+	}{
+		AppID: application.Id,
+		Doc:   doc,
+	}
+
+	const q = `
+	INSERT INTO applications
+		(app_id, revision, doc)
+	VALUES
+		(:app_id, 1, :doc)
+	ON CONFLICT (app_id) DO UPDATE SET
+		revision = applications.revision + 1,
+		doc = EXCLUDED.doc
+	RETURNING
+		revision`
+
+	var rev revision
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &rev); err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(rev.Revision), nil
+}
+
+// GetApplication retrieves an application based upon its ID.
+func (s Store) GetApplication(ctx context.Context, applicationID string) (models.Application, error) {
+	index, err := parseID(applicationID)
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	data := struct {
+		AppID int64 `db:"app_id"`
+	}{
+		AppID: index,
+	}
+
+	const q = `
+	SELECT
+		CAST(app_id AS TEXT) AS id, revision, doc
+	FROM
+		applications
+	WHERE
+		app_id = :app_id`
+
+	var doc document
+	missing := func() error { return notFound("application %s not found", applicationID) }
+	if err := s.queryStruct(ctx, q, data, &doc, missing); err != nil {
+		return models.Application{}, fmt.Errorf("selecting application %s: %w", applicationID, err)
+	}
+
+	application, err := toApplication(doc)
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	return application.Application, nil
+}
+
+// GetEarliestApplicationID retrieves the ID of the application with the lowest
+// index.
+func (s Store) GetEarliestApplicationID(ctx context.Context) (string, error) {
+	return s.applicationIDAt(ctx, "asc")
+}
+
+// GetLatestApplicationID retrieves the ID of the application with the highest
+// index.
+func (s Store) GetLatestApplicationID(ctx context.Context) (string, error) {
+	return s.applicationIDAt(ctx, "desc")
+}
+
+// applicationIDAt retrieves the ID of the first application in the given order.
+func (s Store) applicationIDAt(ctx context.Context, order string) (string, error) {
+	q := fmt.Sprintf(`
+	SELECT
+		app_id
+	FROM
+		applications
+	ORDER BY
+		app_id %s
+	LIMIT 1`, direction(order))
+
+	var row struct {
+		AppID uint64 `db:"app_id"`
+	}
+	if err := s.queryStruct(ctx, q, struct{}{}, &row, func() error { return notFound("no application found") }); err != nil {
+		return "", fmt.Errorf("selecting application ID: %w", err)
+	}
+
+	return strconv.FormatUint(row.AppID, 10), nil
+}
+
+// GetApplicationCount retrieves the number of applications.
+func (s Store) GetApplicationCount(ctx context.Context) (int64, error) {
+	const q = `
+	SELECT
+		count(*) AS count
+	FROM
+		applications`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, struct{}{}, &c); err != nil {
+		return 0, fmt.Errorf("counting applications: %w", err)
+	}
+
+	return c.Count, nil
+}
+
+// GetApplicationCountBtnKeys retrieves the number of applications with IDs from
+// startKey to endKey, both included.
+func (s Store) GetApplicationCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	first, err := parseID(startKey)
+	if err != nil {
+		return 0, err
+	}
+	last, err := parseID(endKey)
+	if err != nil {
+		return 0, err
+	}
+
+	data := struct {
+		First int64 `db:"first"`
+		Last  int64 `db:"last"`
+	}{
+		First: first,
+		Last:  last,
+	}
+
+	const q = `
+	SELECT
+		count(*) AS count
+	FROM
+		applications
+	WHERE
+		app_id BETWEEN :first AND :last`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &c); err != nil {
+		return 0, fmt.Errorf("counting applications: %w", err)
+	}
+
+	return c.Count, nil
+}
+
+// GetApplicationsPagination retrieves a page of the applications up to the ID
+// latestApplicationID, ordered by index. Descending pages start at
+// latestApplicationID.
+func (s Store) GetApplicationsPagination(ctx context.Context, latestApplicationID string, order string, pageNo, limit int64) ([]appdb.Application, int64, int64, error) {
+	latest, err := parseID(latestApplicationID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	data := struct {
+		Latest int64 `db:"latest"`
+		Offset int64 `db:"offset"`
+		Limit  int64 `db:"limit"`
+	}{
+		Latest: latest,
+		Limit:  limit,
+	}
+
+	const qCount = `
+	SELECT
+		count(*) AS count
+	FROM
+		applications
+	WHERE
+		app_id <= :latest`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, qCount, data, &c); err != nil {
+		return nil, 0, 0, fmt.Errorf("counting applications: %w", err)
+	}
+
+	var numOfApplications = c.Count
+	offset, _, numOfPages, err := store.Page(numOfApplications, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	data.Offset = offset
+
+	q := fmt.Sprintf(`
+	SELECT
+		CAST(app_id AS TEXT) AS id, revision, doc
+	FROM
+		applications
+	WHERE
+		app_id <= :latest
+	ORDER BY
+		app_id %s
+	OFFSET :offset LIMIT :limit`, direction(order))
+
+	var docs []document
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &docs); err != nil {
+		return nil, 0, 0, fmt.Errorf("selecting applications: %w", err)
+	}
+
+	var fetchedApplications = []appdb.Application{}
+	for _, doc := range docs {
+		application, err := toApplication(doc)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		fetchedApplications = append(fetchedApplications, application)
+	}
+
+	return fetchedApplications, numOfPages, numOfApplications, nil
+}
+
+// toApplication decodes an application document.
+func toApplication(doc document) (appdb.Application, error) {
+	var application appdb.Application
+	if err := doc.decode(&application); err != nil {
+		return appdb.Application{}, err
+	}
+	application.ID = doc.ID
+	application.Rev = doc.rev()
+
+	return application, nil
+}
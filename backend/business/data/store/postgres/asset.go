@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/jmoiron/sqlx"
+	assetdb "github.com/kevguy/algosearch/backend/business/core/asset/db"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
+)
+
+// AddAsset adds or replaces an asset, stored under its index.
+func (s Store) AddAsset(ctx context.Context, asset models.Asset) (string, string, error) {
+	rev, err := s.putAsset(ctx, asset)
+	if err != nil {
+		return "", "", fmt.Errorf("inserting asset %d: %w", asset.Index, err)
+	}
+
+	return strconv.FormatUint(asset.Index, 10), rev, nil
+}
+
+// AddAssets adds or replaces a batch of assets.
+func (s Store) AddAssets(ctx context.Context, assets []models.Asset) (bool, error) {
+	tran := func(tx sqlx.ExtContext) error {
+		st := s.Tran(tx)
+		for _, asset := range assets {
+			if _, err := st.putAsset(ctx, asset); err != nil {
+				return fmt.Errorf("inserting asset %d: %w", asset.Index, err)
+			}
+		}
+		return nil
+	}
+
+	if err := s.WithinTran(ctx, tran); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// putAsset upserts an asset document and returns its new revision.
+func (s Store) putAsset(ctx context.Context, asset models.Asset) (string, error) {
+	doc, err := encode(assetdb.NewAsset{Asset: asset, DocType: assetdb.DocType})
+	if err != nil {
+		return "", err
+	}
+
+	data := struct {
+		AssetID uint64 `db:"asset_id"`
+		Doc     string `db:"doc"`
+	}{
+		AssetID: asset.Index,
+		Doc:     doc,
+	}
+
+	const q = `
+	INSERT INTO assets
+		(asset_id, revision, doc)
+	VALUES
+		(:asset_id, 1, :doc)
+	ON CONFLICT (asset_id) DO UPDATE SET
+		revision = assets.revision + 1,
+		doc = EXCLUDED.doc
+	RETURNING
+		revision`
+
+	var rev revision
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &rev); err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(rev.Revision), nil
+}
+
+// GetAsset retrieves an asset based upon its ID.
+func (s Store) GetAsset(ctx context.Context, assetID string) (models.Asset, error) {
+	index, err := parseID(assetID)
+	if err != nil {
+		return models.Asset{}, err
+	}
+
+	data := struct {
+		AssetID int64 `db:"asset_id"`
+	}{
+		AssetID: index,
+	}
+
+	const q = `
+	SELECT
+		CAST(asset_id AS TEXT) AS id, revision, doc
+	FROM
+		assets
+	WHERE
+		asset_id = :asset_id`
+
+	var doc document
+	missing := func() error { return notFound("asset %s not found", assetID) }
+	if err := s.queryStruct(ctx, q, data, &doc, missing); err != nil {
+		return models.Asset{}, fmt.Errorf("selecting asset %s: %w", assetID, err)
+	}
+
+	asset, err := toAsset(doc)
+	if err != nil {
+		return models.Asset{}, err
+	}
+
+	return asset.Asset, nil
+}
+
+// GetEarliestAssetID retrieves the ID of the asset with the lowest index.
+func (s Store) GetEarliestAssetID(ctx context.Context) (string, error) {
+	return s.assetIDAt(ctx, "asc")
+}
+
+// GetLatestAssetID retrieves the ID of the asset with the highest index.
+func (s Store) GetLatestAssetID(ctx context.Context) (string, error) {
+	return s.assetIDAt(ctx, "desc")
+}
+
+// assetIDAt retrieves the ID of the first asset in the given order.
+func (s Store) assetIDAt(ctx context.Context, order string) (string, error) {
+	q := fmt.Sprintf(`
+	SELECT
+		asset_id
+	FROM
+		assets
+	ORDER BY
+		asset_id %s
+	LIMIT 1`, direction(order))
+
+	var row struct {
+		AssetID uint64 `db:"asset_id"`
+	}
+	if err := s.queryStruct(ctx, q, struct{}{}, &row, func() error { return notFound("no asset found") }); err != nil {
+		return "", fmt.Errorf("selecting asset ID: %w", err)
+	}
+
+	return strconv.FormatUint(row.AssetID, 10), nil
+}
+
+// GetAssetCount retrieves the number of assets.
+func (s Store) GetAssetCount(ctx context.Context) (int64, error) {
+	const q = `
+	SELECT
+		count(*) AS count
+	FROM
+		assets`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, struct{}{}, &c); err != nil {
+		return 0, fmt.Errorf("counting assets: %w", err)
+	}
+
+	return c.Count, nil
+}
+
+// GetAssetCountBtnKeys retrieves the number of assets with IDs from startKey to
+// endKey, both included.
+func (s Store) GetAssetCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	first, err := parseID(startKey)
+	if err != nil {
+		return 0, err
+	}
+	last, err := parseID(endKey)
+	if err != nil {
+		return 0, err
+	}
+
+	data := struct {
+		First int64 `db:"first"`
+		Last  int64 `db:"last"`
+	}{
+		First: first,
+		Last:  last,
+	}
+
+	const q = `
+	SELECT
+		count(*) AS count
+	FROM
+		assets
+	WHERE
+		asset_id BETWEEN :first AND :last`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &c); err != nil {
+		return 0, fmt.Errorf("counting assets: %w", err)
+	}
+
+	return c.Count, nil
+}
+
+// GetAssetsPagination retrieves a page of the assets up to the ID
+// latestAssetID, ordered by index. Descending pages start at latestAssetID.
+func (s Store) GetAssetsPagination(ctx context.Context, latestAssetID string, order string, pageNo, limit int64) ([]assetdb.Asset, int64, int64, error) {
+	latest, err := parseID(latestAssetID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	data := struct {
+		Latest int64 `db:"latest"`
+		Offset int64 `db:"offset"`
+		Limit  int64 `db:"limit"`
+	}{
+		Latest: latest,
+		Limit:  limit,
+	}
+
+	const qCount = `
+	SELECT
+		count(*) AS count
+	FROM
+		assets
+	WHERE
+		asset_id <= :latest`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, qCount, data, &c); err != nil {
+		return nil, 0, 0, fmt.Errorf("counting assets: %w", err)
+	}
+
+	var numOfAssets = c.Count
+	offset, _, numOfPages, err := store.Page(numOfAssets, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	data.Offset = offset
+
+	q := fmt.Sprintf(`
+	SELECT
+		CAST(asset_id AS TEXT) AS id, revision, doc
+	FROM
+		assets
+	WHERE
+		asset_id <= :latest
+	ORDER BY
+		asset_id %s
+	OFFSET :offset LIMIT :limit`, direction(order))
+
+	var docs []document
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &docs); err != nil {
+		return nil, 0, 0, fmt.Errorf("selecting assets: %w", err)
+	}
+
+	var fetchedAssets = []assetdb.Asset{}
+	for _, doc := range docs {
+		asset, err := toAsset(doc)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		fetchedAssets = append(fetchedAssets, asset)
+	}
+
+	return fetchedAssets, numOfPages, numOfAssets, nil
+}
+
+// toAsset decodes an asset document.
+func toAsset(doc document) (assetdb.Asset, error) {
+	var asset assetdb.Asset
+	if err := doc.decode(&asset); err != nil {
+		return assetdb.Asset{}, err
+	}
+	asset.ID = doc.ID
+	asset.Rev = doc.rev()
+
+	return asset, nil
+}
@@ -0,0 +1,507 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
+)
+
+// blockTxnSpeedBlocks is the number of latest blocks the block speed is
+// averaged over.
+const blockTxnSpeedBlocks = 10
+
+// AddBlock adds or replaces a block, pending until it is committed. Blocks are
+// stored under their hash, or their round when they have none.
+func (s Store) AddBlock(ctx context.Context, block blockdb.NewBlock) (string, string, error) {
+	docID := block.BlockHash
+	if docID == "" {
+		docID = strconv.FormatUint(block.Round, 10)
+	}
+
+	doc := blockdb.Block{
+		NewBlockDoc: blockdb.NewBlockDoc{
+			NewBlock: block,
+			DocType:  blockdb.DocType,
+			Pending:  true,
+		},
+	}
+	rev, err := s.putBlock(ctx, docID, doc)
+	if err != nil {
+		return "", "", fmt.Errorf("inserting block %s: %w", docID, err)
+	}
+
+	return docID, rev, nil
+}
+
+// AddBlocks adds or replaces a batch of blocks, stored under their hash.
+func (s Store) AddBlocks(ctx context.Context, blocks []blockdb.Block) (bool, error) {
+	tran := func(tx sqlx.ExtContext) error {
+		st := s.Tran(tx)
+		for _, block := range blocks {
+			if _, err := st.putBlock(ctx, block.BlockHash, block); err != nil {
+				return fmt.Errorf("inserting block %s: %w", block.BlockHash, err)
+			}
+		}
+		return nil
+	}
+
+	if err := s.WithinTran(ctx, tran); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// putBlock upserts a block document under docID and returns its new revision.
+// A committed block stays committed, so saving a round again doesn't hide it.
+func (s Store) putBlock(ctx context.Context, docID string, block blockdb.Block) (string, error) {
+	block.ID = ""
+	block.Rev = ""
+	doc, err := encode(block)
+	if err != nil {
+		return "", err
+	}
+
+	data := struct {
+		DocID     string `db:"doc_id"`
+		Round     uint64 `db:"round"`
+		BlockTime uint64 `db:"block_time"`
+		Pending   bool   `db:"pending"`
+		Doc       string `db:"doc"`
+	}{
+		DocID:     docID,
+		Round:     block.Round,
+		BlockTime: block.Timestamp,
+		Pending:   block.Pending,
+		Doc:       doc,
+	}
+
+	const q = `
+	INSERT INTO blocks
+		(doc_id, round, block_time, pending, revision, doc)
+	VALUES
+		(:doc_id, :round, :block_time, :pending, 1, :doc)
+	ON CONFLICT (doc_id) DO UPDATE SET
+		round = EXCLUDED.round,
+		block_time = EXCLUDED.block_time,
+		pending = blocks.pending AND EXCLUDED.pending,
+		revision = blocks.revision + 1,
+		doc = CASE WHEN blocks.pending THEN EXCLUDED.doc ELSE EXCLUDED.doc - 'pending' END
+	RETURNING
+		revision`
+
+	var rev revision
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &rev); err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(rev.Revision), nil
+}
+
+// CommitBlock marks the block stored under docID as committed, which makes its
+// round visible.
+func (s Store) CommitBlock(ctx context.Context, docID string) error {
+	data := struct {
+		DocID string `db:"doc_id"`
+	}{
+		DocID: docID,
+	}
+
+	const q = `
+	UPDATE
+		blocks
+	SET
+		pending = false,
+		revision = revision + 1,
+		doc = doc - 'pending'
+	WHERE
+		doc_id = :doc_id
+	RETURNING
+		revision`
+
+	var rev revision
+	missing := func() error { return notFound("block %s not found", docID) }
+	if err := s.queryStruct(ctx, q, data, &rev, missing); err != nil {
+		return fmt.Errorf("committing block %s: %w", docID, err)
+	}
+
+	return nil
+}
+
+// GetBlockByHash retrieves a block based upon its hash.
+func (s Store) GetBlockByHash(ctx context.Context, blockHash string) (blockdb.Block, error) {
+	data := struct {
+		DocID string `db:"doc_id"`
+	}{
+		DocID: blockHash,
+	}
+
+	const q = `
+	SELECT
+		doc_id AS id, revision, doc
+	FROM
+		blocks
+	WHERE
+		doc_id = :doc_id`
+
+	block, err := s.queryBlock(ctx, q, data, func() error { return notFound("block %s not found", blockHash) })
+	if err != nil {
+		return blockdb.Block{}, err
+	}
+	if block.Pending {
+		return blockdb.Block{}, blockdb.ErrNotCommitted
+	}
+
+	return block, nil
+}
+
+// GetBlockByNum retrieves the committed block of a round.
+func (s Store) GetBlockByNum(ctx context.Context, blockNum uint64) (blockdb.Block, error) {
+	data := struct {
+		Round uint64 `db:"round"`
+	}{
+		Round: blockNum,
+	}
+
+	const q = `
+	SELECT
+		doc_id AS id, revision, doc
+	FROM
+		blocks
+	WHERE
+		round = :round AND NOT pending
+	ORDER BY
+		doc_id
+	LIMIT 1`
+
+	return s.queryBlock(ctx, q, data, func() error { return notFound("block of round %d not found", blockNum) })
+}
+
+// GetEarliestSyncedRoundNumber retrieves the lowest committed round.
+func (s Store) GetEarliestSyncedRoundNumber(ctx context.Context) (uint64, error) {
+	const q = `
+	SELECT
+		round
+	FROM
+		blocks
+	WHERE
+		NOT pending
+	ORDER BY
+		round
+	LIMIT 1`
+
+	var row struct {
+		Round uint64 `db:"round"`
+	}
+	if err := s.queryStruct(ctx, q, struct{}{}, &row, func() error { return notFound("no block found") }); err != nil {
+		return 0, fmt.Errorf("selecting earliest round: %w", err)
+	}
+
+	return row.Round, nil
+}
+
+// GetLastSyncedRoundNumber retrieves the highest committed round. The boolean
+// returned is false when no block is stored.
+func (s Store) GetLastSyncedRoundNumber(ctx context.Context) (uint64, bool, error) {
+	block, err := s.GetLatestBlock(ctx)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	return block.Round, true, nil
+}
+
+// GetLatestBlock retrieves the committed block of the highest round.
+func (s Store) GetLatestBlock(ctx context.Context) (blockdb.Block, error) {
+	const q = `
+	SELECT
+		doc_id AS id, revision, doc
+	FROM
+		blocks
+	WHERE
+		NOT pending
+	ORDER BY
+		round DESC, doc_id DESC
+	LIMIT 1`
+
+	return s.queryBlock(ctx, q, struct{}{}, func() error { return notFound("no block found") })
+}
+
+// GetBlocksPagination retrieves a page of committed blocks. Like the CouchDB
+// store, the number of blocks is counted as the rounds between the earliest
+// one and latestBlockNum, and descending pages start at latestBlockNum.
+func (s Store) GetBlocksPagination(ctx context.Context, latestBlockNum int64, order string, pageNo int64, limit int64) ([]blockdb.Block, int64, int64, error) {
+	earliest, err := s.GetEarliestSyncedRoundNumber(ctx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var numOfBlks = latestBlockNum - int64(earliest) + 1
+	offset, _, numOfPages, err := store.Page(numOfBlks, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	data := struct {
+		Latest int64 `db:"latest"`
+		Offset int64 `db:"offset"`
+		Limit  int64 `db:"limit"`
+	}{
+		Latest: latestBlockNum,
+		Offset: offset,
+		Limit:  limit,
+	}
+
+	q := fmt.Sprintf(`
+	SELECT
+		doc_id AS id, revision, doc
+	FROM
+		blocks
+	WHERE
+		round <= :latest AND NOT pending
+	ORDER BY
+		round %[1]s, doc_id %[1]s
+	OFFSET :offset LIMIT :limit`, direction(order))
+
+	var docs []document
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &docs); err != nil {
+		return nil, 0, 0, fmt.Errorf("selecting blocks: %w", err)
+	}
+
+	var fetchedBlocks = []blockdb.Block{}
+	for _, doc := range docs {
+		block, err := toBlock(doc)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		fetchedBlocks = append(fetchedBlocks, block)
+	}
+
+	return fetchedBlocks, numOfPages, numOfBlks, nil
+}
+
+// GetBlocksFrom retrieves up to limit committed blocks read from cur, or from
+// the start of the list when cur is nil, in the given order. The boolean
+// returned reports whether blocks are left past them in the direction they
+// were read in.
+func (s Store) GetBlocksFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]blockdb.Block, bool, error) {
+	if limit < 1 {
+		return nil, false, cursor.ErrLimit
+	}
+
+	data := map[string]interface{}{
+		"limit": limit + 1,
+	}
+
+	dir, op := readingOrder(cur, order)
+	where := "NOT pending"
+	if cur != nil {
+		where += fmt.Sprintf(" AND (round, doc_id) %s (:round, :id)", op)
+		data["round"] = cur.Round
+		data["id"] = cur.ID
+	}
+
+	q := fmt.Sprintf(`
+	SELECT
+		doc_id AS id, revision, doc
+	FROM
+		blocks
+	WHERE
+		%[1]s
+	ORDER BY
+		round %[2]s, doc_id %[2]s
+	LIMIT :limit`, where, dir)
+
+	var docs []document
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &docs); err != nil {
+		return nil, false, fmt.Errorf("selecting blocks: %w", err)
+	}
+	docs, more := pageFrom(docs, cur, limit)
+
+	var fetchedBlocks = []blockdb.Block{}
+	for _, doc := range docs {
+		block, err := toBlock(doc)
+		if err != nil {
+			return nil, false, err
+		}
+		fetchedBlocks = append(fetchedBlocks, block)
+	}
+
+	return fetchedBlocks, more, nil
+}
+
+// GetNumOfBlocks retrieves the number of committed blocks.
+func (s Store) GetNumOfBlocks(ctx context.Context) (int64, error) {
+	const q = `
+	SELECT
+		count(*) AS count
+	FROM
+		blocks
+	WHERE
+		NOT pending`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, struct{}{}, &c); err != nil {
+		return 0, fmt.Errorf("counting blocks: %w", err)
+	}
+
+	return c.Count, nil
+}
+
+// GetBlockTxnSpeed retrieves the average number of seconds between the latest
+// committed blocks.
+func (s Store) GetBlockTxnSpeed(ctx context.Context) (float64, error) {
+	data := struct {
+		Limit int `db:"limit"`
+	}{
+		Limit: blockTxnSpeedBlocks,
+	}
+
+	const q = `
+	SELECT
+		block_time
+	FROM
+		blocks
+	WHERE
+		NOT pending
+	ORDER BY
+		round DESC, doc_id DESC
+	LIMIT :limit`
+
+	var rows []struct {
+		BlockTime uint64 `db:"block_time"`
+	}
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &rows); err != nil {
+		return 0.0, fmt.Errorf("selecting block times: %w", err)
+	}
+	if len(rows) <= 1 {
+		return 0.0, nil
+	}
+
+	// The blocks come latest first, so the time between the first and the
+	// last of them is the sum of the times between each of them.
+	elapsed := rows[0].BlockTime - rows[len(rows)-1].BlockTime
+	return float64(elapsed) / float64(len(rows)-1), nil
+}
+
+// GetRoundGaps retrieves the ranges of rounds missing between the lowest and
+// the highest committed round.
+func (s Store) GetRoundGaps(ctx context.Context) ([]blockdb.RoundGap, error) {
+	const q = `
+	SELECT
+		round + 1 AS gap_from,
+		next_round - 1 AS gap_to
+	FROM (
+		SELECT
+			round, LEAD(round) OVER (ORDER BY round) AS next_round
+		FROM
+			(SELECT DISTINCT round FROM blocks WHERE NOT pending) AS rounds
+	) AS consecutive
+	WHERE
+		next_round > round + 1
+	ORDER BY
+		round`
+
+	var rows []struct {
+		From uint64 `db:"gap_from"`
+		To   uint64 `db:"gap_to"`
+	}
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, struct{}{}, &rows); err != nil {
+		return nil, fmt.Errorf("selecting round gaps: %w", err)
+	}
+
+	var gaps []blockdb.RoundGap
+	for _, row := range rows {
+		gaps = append(gaps, blockdb.RoundGap{From: row.From, To: row.To})
+	}
+
+	return gaps, nil
+}
+
+// DeleteBlocksBefore deletes every committed block older than round and returns
+// how many were deleted.
+func (s Store) DeleteBlocksBefore(ctx context.Context, round uint64) (int, error) {
+	data := struct {
+		Round uint64 `db:"round"`
+	}{
+		Round: round,
+	}
+
+	const q = `
+	WITH deleted AS (
+		DELETE FROM
+			blocks
+		WHERE
+			round < :round AND NOT pending
+		RETURNING
+			doc_id
+	)
+	SELECT
+		count(*) AS count
+	FROM
+		deleted`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &c); err != nil {
+		return 0, fmt.Errorf("deleting blocks before round %d: %w", round, err)
+	}
+
+	return int(c.Count), nil
+}
+
+// queryBlock runs a query returning a single block document. A missing block is
+// reported with the error returned by missing.
+func (s Store) queryBlock(ctx context.Context, q string, data interface{}, missing func() error) (blockdb.Block, error) {
+	var doc document
+	if err := s.queryStruct(ctx, q, data, &doc, missing); err != nil {
+		return blockdb.Block{}, fmt.Errorf("selecting block: %w", err)
+	}
+
+	return toBlock(doc)
+}
+
+// toBlock decodes a block document.
+func toBlock(doc document) (blockdb.Block, error) {
+	var block blockdb.Block
+	if err := doc.decode(&block); err != nil {
+		return blockdb.Block{}, err
+	}
+	block.ID = doc.ID
+	block.Rev = doc.rev()
+
+	return block, nil
+}
+
+// roundCommitted reports whether a committed block of round is stored.
+func (s Store) roundCommitted(ctx context.Context, round uint64) (bool, error) {
+	data := struct {
+		Round uint64 `db:"round"`
+	}{
+		Round: round,
+	}
+
+	const q = `
+	SELECT
+		count(*) AS count
+	FROM
+		blocks
+	WHERE
+		round = :round AND NOT pending`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &c); err != nil {
+		return false, fmt.Errorf("checking round %d: %w", round, err)
+	}
+
+	return c.Count > 0, nil
+}
@@ -0,0 +1,184 @@
+// Package postgres provides a storage backend keeping blocks, transactions,
+// accounts, assets, applications and the sync state in PostgreSQL. Documents
+// are stored as JSONB next to indexed columns they are looked up and ordered
+// by, and are paged through the way the CouchDB views do.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevguy/algosearch/backend/business/core/account"
+	"github.com/kevguy/algosearch/backend/business/core/application"
+	"github.com/kevguy/algosearch/backend/business/core/asset"
+	"github.com/kevguy/algosearch/backend/business/core/block"
+	"github.com/kevguy/algosearch/backend/business/core/syncstate"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
+	"go.uber.org/zap"
+)
+
+// Store has to be usable as the store of every core.
+var (
+	_ block.Storer       = Store{}
+	_ transaction.Storer = Store{}
+	_ account.Storer     = Store{}
+	_ asset.Storer       = Store{}
+	_ application.Storer = Store{}
+	_ syncstate.Storer   = Store{}
+)
+
+// Store manages the set of API's for explorer data access. It satisfies the
+// Storer interface of the block, transaction, account, asset, application and
+// sync state cores.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// =============================================================================
+
+// notFound returns the error reported when a row doesn't exist. It answers to
+// errors.Is(err, database.ErrDBNotFound) and, like CouchDB, carries a 404
+// status.
+func notFound(format string, args ...interface{}) error {
+	return &store.StatusError{Status: http.StatusNotFound, Msg: fmt.Sprintf(format, args...), Err: database.ErrDBNotFound}
+}
+
+// document is a row holding a JSON document along with its ID and revision.
+type document struct {
+	ID       string `db:"id"`
+	Revision int    `db:"revision"`
+	Doc      string `db:"doc"`
+}
+
+// decode unmarshals the document into v.
+func (d document) decode(v interface{}) error {
+	if err := json.Unmarshal([]byte(d.Doc), v); err != nil {
+		return fmt.Errorf("decoding document %s: %w", d.ID, err)
+	}
+	return nil
+}
+
+// rev returns the revision of the document the way the cores report it.
+func (d document) rev() string {
+	return strconv.Itoa(d.Revision)
+}
+
+// revision is the revision a row got when it was written.
+type revision struct {
+	Revision int `db:"revision"`
+}
+
+// count is the result of a query counting rows.
+type count struct {
+	Count int64 `db:"count"`
+}
+
+// encode marshals a document to store in a JSONB column. It is passed as a
+// string since lib/pq would send a byte slice as bytea.
+func encode(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("encoding document: %w", err)
+	}
+	return string(data), nil
+}
+
+// queryStruct runs a query returning a single row into dest. A missing row is
+// reported with the error returned by missing.
+func (s Store) queryStruct(ctx context.Context, q string, data interface{}, dest interface{}, missing func() error) error {
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, dest); err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return missing()
+		}
+		return err
+	}
+	return nil
+}
+
+// direction returns the SQL sort direction of order, descending for "desc"
+// and ascending otherwise.
+func direction(order string) string {
+	if order == "desc" {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// readingOrder returns the direction the rows of a page read from cur are
+// selected in, for a list in the given order, and the operator comparing their
+// keys to the key of the cursor.
+func readingOrder(cur *cursor.Cursor, order string) (dir string, op string) {
+	if cur.Descending(order) {
+		return "DESC", "<"
+	}
+	return "ASC", ">"
+}
+
+// pageFrom trims the documents selected for a page read from cur, one more
+// than limit of them, to the page and puts them in the order of the list. The
+// boolean returned reports whether documents are left past the page in the
+// direction it was read in.
+func pageFrom(docs []document, cur *cursor.Cursor, limit int64) ([]document, bool) {
+	more := int64(len(docs)) > limit
+	if more {
+		docs = docs[:limit]
+	}
+	if cur != nil && cur.Before {
+		for i, j := 0, len(docs)-1; i < j; i, j = i+1, j-1 {
+			docs[i], docs[j] = docs[j], docs[i]
+		}
+	}
+	return docs, more
+}
+
+// parseID parses the ID of an asset or application, which are stored under
+// their index, as the signed integer their columns hold.
+func parseID(id string) (int64, error) {
+	index, err := store.ParseID(id)
+	if err != nil {
+		return 0, err
+	}
+	if index > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid ID %q: out of range", id)
+	}
+	return int64(index), nil
+}
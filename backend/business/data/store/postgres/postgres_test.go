@@ -0,0 +1,171 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+	"github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/business/core/block"
+	blockdb "github.com/kevguy/algosearch/backend/business/core/block/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction"
+	"github.com/kevguy/algosearch/backend/business/data/dbtest"
+	"github.com/kevguy/algosearch/backend/business/data/store/postgres"
+	"github.com/kevguy/algosearch/backend/foundation/docker"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestBlocks(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testblocks")
+	t.Cleanup(teardown)
+
+	core := block.NewCoreWithStore(postgres.NewStore(log, db))
+
+	t.Log("Given the need to page through blocks stored in PostgreSQL.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen rounds 10 to 14 are stored and round 14 is not committed.", testID)
+		{
+			ctx := context.Background()
+
+			for round := uint64(10); round <= 14; round++ {
+				docID, _, err := core.AddBlock(ctx, blockdb.NewBlock{
+					Block:     models.Block{Round: round, Timestamp: 1000 + 4*round},
+					BlockHash: fmt.Sprintf("HASH%d", round),
+				})
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to add block %d : %v.", dbtest.Failed, testID, round, err)
+				}
+				if round == 14 {
+					continue
+				}
+				if err := core.CommitBlock(ctx, docID); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to commit block %d : %v.", dbtest.Failed, testID, round, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to add and commit blocks.", dbtest.Success, testID)
+
+			last, found, err := core.GetLastSyncedRoundNumber(ctx)
+			if err != nil || !found || last != 13 {
+				t.Fatalf("\t%s\tTest %d:\tShould leave the pending round out : got %d, %v, %v.", dbtest.Failed, testID, last, found, err)
+			}
+			if _, err := core.GetBlockByHash(ctx, "HASH14"); !errors.Is(err, blockdb.ErrNotCommitted) {
+				t.Fatalf("\t%s\tTest %d:\tShould report the pending block as not committed : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould leave the pending round out.", dbtest.Success, testID)
+
+			if _, err := core.GetBlockByNum(ctx, 9); kivik.StatusCode(err) != http.StatusNotFound {
+				t.Fatalf("\t%s\tTest %d:\tShould report a missing block as not found : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report a missing block as not found.", dbtest.Success, testID)
+
+			blocks, numOfPages, numOfBlks, err := core.GetBlocksPagination(ctx, 13, "desc", 2, 3)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to get the second page : %v.", dbtest.Failed, testID, err)
+			}
+			if numOfPages != 2 || numOfBlks != 4 || len(blocks) != 1 || blocks[0].Round != 10 {
+				t.Fatalf("\t%s\tTest %d:\tShould get round 10 alone on the second descending page : got %d pages, %d blocks, %+v.", dbtest.Failed, testID, numOfPages, numOfBlks, blocks)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get round 10 alone on the second descending page.", dbtest.Success, testID)
+
+			speed, err := core.GetBlockTxnSpeed(ctx)
+			if err != nil || speed != 4 {
+				t.Fatalf("\t%s\tTest %d:\tShould average the time between blocks : got %v, %v.", dbtest.Failed, testID, speed, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould average the time between blocks.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testtransactions")
+	t.Cleanup(teardown)
+
+	core := transaction.NewCoreWithStore(postgres.NewStore(log, db))
+
+	t.Log("Given the need to list the transactions of an account stored in PostgreSQL.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen an account sends transactions over two rounds.", testID)
+		{
+			ctx := context.Background()
+
+			var txns []models.Transaction
+			for i, round := range []uint64{20, 20, 21} {
+				txns = append(txns, models.Transaction{
+					Id:               fmt.Sprintf("TXN%d", 3-i),
+					Type:             "pay",
+					Sender:           "SENDER",
+					ConfirmedRound:   round,
+					IntraRoundOffset: uint64(i),
+					PaymentTransaction: models.TransactionPayment{
+						Receiver: "RECEIVER",
+					},
+				})
+			}
+			if err := addCommitted(ctx, core, txns); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to add the transactions : %v.", dbtest.Failed, testID, err)
+			}
+			if err := addCommitted(ctx, core, txns); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to add the transactions again : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to add the transactions twice.", dbtest.Success, testID)
+
+			page, numOfPages, numOfTxns, err := core.GetTransactionsByAcctPagination(ctx, "RECEIVER", "desc", 1, 2)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to page through the transactions of the receiver : %v.", dbtest.Failed, testID, err)
+			}
+			if numOfPages != 2 || numOfTxns != 3 || len(page) != 2 || page[0].ID != "TXN1" || page[1].ID != "TXN2" {
+				t.Fatalf("\t%s\tTest %d:\tShould order the transactions by round and position : got %d pages, %d transactions, %+v.", dbtest.Failed, testID, numOfPages, numOfTxns, page)
+			}
+			t.Logf("\t%s\tTest %d:\tShould order the transactions by round and position.", dbtest.Success, testID)
+
+			count, err := core.GetTransactionCountByAcct(ctx, "SENDER", "TXN2", "TXN1")
+			if err != nil || count != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould count the transactions between two of them : got %d, %v.", dbtest.Failed, testID, count, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould count the transactions between two of them.", dbtest.Success, testID)
+
+			deleted, err := core.DeleteTransactionsBefore(ctx, 21)
+			if err != nil || deleted != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould prune the transactions of round 20 : got %d, %v.", dbtest.Failed, testID, deleted, err)
+			}
+			acctTxns, err := core.GetTransactionsByAcct(ctx, "RECEIVER", "asc")
+			if err != nil || len(acctTxns) != 1 || acctTxns[0].ID != "TXN1" {
+				t.Fatalf("\t%s\tTest %d:\tShould keep the transaction of round 21 alone : got %+v, %v.", dbtest.Failed, testID, acctTxns, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould prune the transactions of round 20.", dbtest.Success, testID)
+		}
+	}
+}
+
+// addCommitted adds transactions and commits their rounds, the way the ingester
+// does once everything else of a round is saved.
+func addCommitted(ctx context.Context, core transaction.Core, txns []models.Transaction) error {
+	if _, err := core.AddTransactions(ctx, txns, nil, types.Block{}); err != nil {
+		return err
+	}
+	for _, txn := range txns {
+		if err := core.CommitTransactions(ctx, txn.ConfirmedRound); err != nil {
+			return err
+		}
+	}
+	return nil
+}
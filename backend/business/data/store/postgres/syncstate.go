@@ -0,0 +1,360 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	syncdb "github.com/kevguy/algosearch/backend/business/core/syncstate/db"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
+)
+
+// syncStateRow is the row of the sync state.
+type syncStateRow struct {
+	StateID         string    `db:"state_id"`
+	LastSyncedRound uint64    `db:"last_synced_round"`
+	NothingSynced   bool      `db:"nothing_synced"`
+	Revision        int       `db:"revision"`
+	DateUpdated     time.Time `db:"date_updated"`
+}
+
+// failedRoundRow is the row of a failed round.
+type failedRoundRow struct {
+	Round         uint64    `db:"round"`
+	Error         *string   `db:"error"`
+	Attempts      int       `db:"attempts"`
+	Revision      int       `db:"revision"`
+	FirstFailedAt time.Time `db:"first_failed_at"`
+	LastFailedAt  time.Time `db:"last_failed_at"`
+}
+
+// leaseRow is the row of the sync lease.
+type leaseRow struct {
+	LeaseID    string    `db:"lease_id"`
+	Holder     string    `db:"holder"`
+	Revision   int       `db:"revision"`
+	AcquiredAt time.Time `db:"acquired_at"`
+	RenewedAt  time.Time `db:"renewed_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+}
+
+// GetSyncState retrieves the sync state. The boolean returned is false when no
+// sync state has been recorded yet.
+func (s Store) GetSyncState(ctx context.Context) (syncdb.SyncState, bool, error) {
+	data := struct {
+		StateID string `db:"state_id"`
+	}{
+		StateID: syncdb.SyncStateDocID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		sync_state
+	WHERE
+		state_id = :state_id`
+
+	var row syncStateRow
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &row); err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return syncdb.SyncState{}, false, nil
+		}
+		return syncdb.SyncState{}, false, fmt.Errorf("selecting sync state: %w", err)
+	}
+
+	state := syncdb.SyncState{
+		NewSyncState: syncdb.NewSyncState{
+			DocType:         syncdb.DocType,
+			LastSyncedRound: row.LastSyncedRound,
+			NothingSynced:   row.NothingSynced,
+			UpdatedAt:       row.DateUpdated,
+		},
+		ID:  row.StateID,
+		Rev: strconv.Itoa(row.Revision),
+	}
+
+	return state, true, nil
+}
+
+// SetLastSyncedRound records round as the highest round for which it and every
+// round before it have been synced.
+func (s Store) SetLastSyncedRound(ctx context.Context, round uint64) error {
+	return s.putSyncState(ctx, round, false)
+}
+
+// ResetSyncState records that no round has been synced, so syncing starts over
+// from the first round.
+func (s Store) ResetSyncState(ctx context.Context) error {
+	return s.putSyncState(ctx, 0, true)
+}
+
+// putSyncState upserts the sync state.
+func (s Store) putSyncState(ctx context.Context, round uint64, nothingSynced bool) error {
+	data := syncStateRow{
+		StateID:         syncdb.SyncStateDocID,
+		LastSyncedRound: round,
+		NothingSynced:   nothingSynced,
+		DateUpdated:     time.Now().UTC(),
+	}
+
+	const q = `
+	INSERT INTO sync_state
+		(state_id, last_synced_round, nothing_synced, revision, date_updated)
+	VALUES
+		(:state_id, :last_synced_round, :nothing_synced, 1, :date_updated)
+	ON CONFLICT (state_id) DO UPDATE SET
+		last_synced_round = EXCLUDED.last_synced_round,
+		nothing_synced = EXCLUDED.nothing_synced,
+		revision = sync_state.revision + 1,
+		date_updated = EXCLUDED.date_updated`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("updating sync state: %w", err)
+	}
+
+	return nil
+}
+
+// RecordFailedRound adds or updates the record of a round that could not be
+// ingested. Attempts are added to the ones already recorded for the round.
+func (s Store) RecordFailedRound(ctx context.Context, round uint64, attempts int, failure error) error {
+	now := time.Now().UTC()
+	data := failedRoundRow{
+		Round:         round,
+		Attempts:      attempts,
+		FirstFailedAt: now,
+		LastFailedAt:  now,
+	}
+	if failure != nil {
+		msg := failure.Error()
+		data.Error = &msg
+	}
+
+	const q = `
+	INSERT INTO failed_rounds
+		(round, error, attempts, revision, first_failed_at, last_failed_at)
+	VALUES
+		(:round, COALESCE(:error, ''), :attempts, 1, :first_failed_at, :last_failed_at)
+	ON CONFLICT (round) DO UPDATE SET
+		error = COALESCE(:error, failed_rounds.error),
+		attempts = failed_rounds.attempts + EXCLUDED.attempts,
+		revision = failed_rounds.revision + 1,
+		last_failed_at = EXCLUDED.last_failed_at`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("recording failed round %d: %w", round, err)
+	}
+
+	return nil
+}
+
+// GetFailedRound retrieves the record of a failed round. The boolean returned is
+// false when the round is not recorded as failed.
+func (s Store) GetFailedRound(ctx context.Context, round uint64) (syncdb.FailedRound, bool, error) {
+	data := struct {
+		Round uint64 `db:"round"`
+	}{
+		Round: round,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		failed_rounds
+	WHERE
+		round = :round`
+
+	var row failedRoundRow
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &row); err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return syncdb.FailedRound{}, false, nil
+		}
+		return syncdb.FailedRound{}, false, fmt.Errorf("selecting failed round %d: %w", round, err)
+	}
+
+	return toFailedRound(row), true, nil
+}
+
+// GetFailedRounds retrieves every round recorded as failed, in ascending round
+// order.
+func (s Store) GetFailedRounds(ctx context.Context) ([]syncdb.FailedRound, error) {
+	const q = `
+	SELECT
+		*
+	FROM
+		failed_rounds
+	ORDER BY
+		round`
+
+	var rows []failedRoundRow
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, struct{}{}, &rows); err != nil {
+		return nil, fmt.Errorf("selecting failed rounds: %w", err)
+	}
+
+	var fetchedRounds = []syncdb.FailedRound{}
+	for _, row := range rows {
+		fetchedRounds = append(fetchedRounds, toFailedRound(row))
+	}
+
+	return fetchedRounds, nil
+}
+
+// DeleteFailedRound removes the record of a failed round. It is not an error if
+// the round is not recorded.
+func (s Store) DeleteFailedRound(ctx context.Context, round uint64) error {
+	data := struct {
+		Round uint64 `db:"round"`
+	}{
+		Round: round,
+	}
+
+	const q = `
+	DELETE FROM
+		failed_rounds
+	WHERE
+		round = :round`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting failed round %d: %w", round, err)
+	}
+
+	return nil
+}
+
+// GetLease retrieves the sync lease. The boolean returned is false when no
+// instance has ever taken the lease.
+func (s Store) GetLease(ctx context.Context) (syncdb.SyncLease, bool, error) {
+	data := struct {
+		LeaseID string `db:"lease_id"`
+	}{
+		LeaseID: syncdb.SyncLeaseDocID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		sync_lease
+	WHERE
+		lease_id = :lease_id`
+
+	var row leaseRow
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &row); err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return syncdb.SyncLease{}, false, nil
+		}
+		return syncdb.SyncLease{}, false, fmt.Errorf("selecting sync lease: %w", err)
+	}
+
+	return toLease(row), true, nil
+}
+
+// AcquireLease takes or renews the sync lease for holder, for ttl from now. The
+// lease can only be taken when it is free or has expired. The boolean returned
+// reports whether holder holds the lease, along with the lease as last seen.
+func (s Store) AcquireLease(ctx context.Context, holder string, ttl time.Duration) (syncdb.SyncLease, bool, error) {
+	now := time.Now().UTC()
+	data := leaseRow{
+		LeaseID:    syncdb.SyncLeaseDocID,
+		Holder:     holder,
+		AcquiredAt: now,
+		RenewedAt:  now,
+		ExpiresAt:  now.Add(ttl),
+	}
+
+	// The update only happens when holder already holds the lease or it has
+	// expired, in which case no row is returned.
+	const q = `
+	INSERT INTO sync_lease
+		(lease_id, holder, revision, acquired_at, renewed_at, expires_at)
+	VALUES
+		(:lease_id, :holder, 1, :acquired_at, :renewed_at, :expires_at)
+	ON CONFLICT (lease_id) DO UPDATE SET
+		holder = EXCLUDED.holder,
+		revision = sync_lease.revision + 1,
+		acquired_at = CASE
+			WHEN sync_lease.holder = EXCLUDED.holder THEN sync_lease.acquired_at
+			ELSE EXCLUDED.acquired_at
+		END,
+		renewed_at = EXCLUDED.renewed_at,
+		expires_at = EXCLUDED.expires_at
+	WHERE
+		sync_lease.holder = EXCLUDED.holder OR sync_lease.expires_at <= EXCLUDED.renewed_at
+	RETURNING
+		*`
+
+	var row leaseRow
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &row); err != nil {
+		if !errors.Is(err, database.ErrDBNotFound) {
+			return syncdb.SyncLease{}, false, fmt.Errorf("acquiring sync lease: %w", err)
+		}
+
+		lease, _, err := s.GetLease(ctx)
+		return lease, false, err
+	}
+
+	return toLease(row), true, nil
+}
+
+// ReleaseLease gives up the sync lease if holder holds it.
+func (s Store) ReleaseLease(ctx context.Context, holder string) error {
+	data := struct {
+		LeaseID string    `db:"lease_id"`
+		Holder  string    `db:"holder"`
+		Now     time.Time `db:"now"`
+	}{
+		LeaseID: syncdb.SyncLeaseDocID,
+		Holder:  holder,
+		Now:     time.Now().UTC(),
+	}
+
+	const q = `
+	UPDATE
+		sync_lease
+	SET
+		revision = revision + 1,
+		expires_at = :now
+	WHERE
+		lease_id = :lease_id AND holder = :holder AND expires_at > :now`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("releasing sync lease: %w", err)
+	}
+
+	return nil
+}
+
+// toFailedRound converts the row of a failed round to its document.
+func toFailedRound(row failedRoundRow) syncdb.FailedRound {
+	failedRound := syncdb.FailedRound{
+		ID:            fmt.Sprintf("%s.%d", syncdb.FailedRoundDocType, row.Round),
+		Rev:           strconv.Itoa(row.Revision),
+		DocType:       syncdb.FailedRoundDocType,
+		Round:         row.Round,
+		Attempts:      row.Attempts,
+		FirstFailedAt: row.FirstFailedAt,
+		LastFailedAt:  row.LastFailedAt,
+	}
+	if row.Error != nil {
+		failedRound.Error = *row.Error
+	}
+
+	return failedRound
+}
+
+// toLease converts the row of the sync lease to its document.
+func toLease(row leaseRow) syncdb.SyncLease {
+	return syncdb.SyncLease{
+		ID:         row.LeaseID,
+		Rev:        strconv.Itoa(row.Revision),
+		DocType:    syncdb.LeaseDocType,
+		Holder:     row.Holder,
+		AcquiredAt: row.AcquiredAt,
+		RenewedAt:  row.RenewedAt,
+		ExpiresAt:  row.ExpiresAt,
+	}
+}
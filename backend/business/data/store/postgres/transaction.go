@@ -0,0 +1,820 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/types"
+	"github.com/jmoiron/sqlx"
+	txndb "github.com/kevguy/algosearch/backend/business/core/transaction/db"
+	"github.com/kevguy/algosearch/backend/business/core/transaction/txnfields"
+	"github.com/kevguy/algosearch/backend/business/data/cursor"
+	"github.com/kevguy/algosearch/backend/business/data/store"
+	"github.com/kevguy/algosearch/backend/business/sys/database"
+	"github.com/lib/pq"
+)
+
+// txnScope describes a set of transactions: the table they are listed in,
+// which condition on the :key parameter selects them and which columns order
+// them. Linked scopes list transactions in a table of their own, aliased l,
+// which is joined to the transactions, aliased t. Pending transactions are left
+// out of every scope.
+type txnScope struct {
+	table  string
+	linked bool
+	where  string
+	keys   []string
+}
+
+// Sets of transactions the transaction queries run on. Every set is ordered like
+// all transactions are, by round, position within the round and ID, like the
+// CouchDB views.
+var (
+	allTxns = txnScope{
+		table: "transactions AS t",
+		where: "TRUE",
+		keys:  []string{"t.round", "t.intra_round_offset", "t.transaction_id"},
+	}
+	typeTxns = txnScope{
+		table: "transactions AS t",
+		where: "t.tx_type = :key",
+		keys:  []string{"t.round", "t.intra_round_offset", "t.transaction_id"},
+	}
+	roundTxns = txnScope{
+		table: "transactions AS t",
+		where: "t.round = :key",
+		keys:  []string{"t.round", "t.intra_round_offset", "t.transaction_id"},
+	}
+	groupTxns = txnScope{
+		table: "transactions AS t",
+		where: "t.doc->>'group' = :key",
+		keys:  []string{"t.round", "t.intra_round_offset", "t.transaction_id"},
+	}
+	acctTxns = txnScope{
+		table:  "transaction_accounts AS l",
+		linked: true,
+		where:  "l.address = :key",
+		keys:   []string{"l.round", "l.intra_round_offset", "l.transaction_id"},
+	}
+	assetTxns = txnScope{
+		table:  "transaction_assets AS l",
+		linked: true,
+		where:  "l.asset_id = :key",
+		keys:   []string{"l.round", "l.intra_round_offset", "l.transaction_id"},
+	}
+	appTxns = txnScope{
+		table:  "transaction_applications AS l",
+		linked: true,
+		where:  "l.app_id = :key",
+		keys:   []string{"l.round", "l.intra_round_offset", "l.transaction_id"},
+	}
+)
+
+// selectQuery returns the query selecting the transaction documents of the
+// scope in the given order, :offset transactions in and up to :limit of them.
+func (sc txnScope) selectQuery(order string) string {
+	dir := direction(order)
+	orderBy := make([]string, len(sc.keys))
+	for i, key := range sc.keys {
+		orderBy[i] = key + " " + dir
+	}
+
+	return fmt.Sprintf(`
+	SELECT
+		t.transaction_id AS id, t.revision, t.doc
+	FROM
+		%s
+	WHERE
+		%s
+	ORDER BY
+		%s
+	OFFSET :offset LIMIT :limit`, sc.from(), sc.committed(), strings.Join(orderBy, ", "))
+}
+
+// from returns the tables the transactions of the scope are selected from.
+func (sc txnScope) from() string {
+	if sc.linked {
+		return sc.table + " JOIN transactions AS t ON t.transaction_id = l.transaction_id"
+	}
+	return sc.table
+}
+
+// committed returns the condition selecting the committed transactions of the
+// scope.
+func (sc txnScope) committed() string {
+	return sc.where + " AND NOT t.pending"
+}
+
+// fromQuery returns the query selecting up to :limit transaction documents of
+// the scope in the direction dir. When after is set, only those whose key
+// compares to (:round, :offset, :id) with op are selected. The scope has to be
+// ordered by round.
+func (sc txnScope) fromQuery(dir, op string, after bool) string {
+	orderBy := make([]string, len(sc.keys))
+	for i, key := range sc.keys {
+		orderBy[i] = key + " " + dir
+	}
+
+	where := sc.committed()
+	if after {
+		where += fmt.Sprintf(" AND (%s) %s (:round, :offset, :id)", strings.Join(sc.keys, ", "), op)
+	}
+
+	return fmt.Sprintf(`
+	SELECT
+		t.transaction_id AS id, t.revision, t.doc
+	FROM
+		%s
+	WHERE
+		%s
+	ORDER BY
+		%s
+	LIMIT :limit`, sc.from(), where, strings.Join(orderBy, ", "))
+}
+
+// countQuery returns the query counting the transactions of the scope. When
+// between is set, only those from the transaction with the :first_* key to the
+// one with the :last_* key are counted.
+func (sc txnScope) countQuery(between bool) string {
+	where := sc.committed()
+	if between {
+		where += fmt.Sprintf(`
+		AND (%s) BETWEEN (:first_round, :first_offset, :first_id) AND (:last_round, :last_offset, :last_id)`, strings.Join(sc.keys, ", "))
+	}
+
+	return fmt.Sprintf(`
+	SELECT
+		count(*) AS count
+	FROM
+		%s
+	WHERE
+		%s`, sc.from(), where)
+}
+
+// =============================================================================
+
+// AddTransaction adds or replaces a transaction.
+func (s Store) AddTransaction(ctx context.Context, transaction models.Transaction, blockInfo types.Block) (string, string, error) {
+	var rev string
+	tran := func(tx sqlx.ExtContext) error {
+		var err error
+		rev, err = s.Tran(tx).putTransaction(ctx, txndb.NewTransactionDoc(transaction, txnfields.TransactionExtras{}, blockInfo))
+		return err
+	}
+
+	if err := s.WithinTran(ctx, tran); err != nil {
+		return "", "", fmt.Errorf("inserting transaction %s: %w", transaction.Id, err)
+	}
+
+	return transaction.Id, rev, nil
+}
+
+// AddTransactions adds or replaces the transactions of a block, along with the
+// fields of each the SDK's model has no room for.
+func (s Store) AddTransactions(ctx context.Context, transactions []models.Transaction, extras []txnfields.TransactionExtras, blockInfo types.Block) (bool, error) {
+	tran := func(tx sqlx.ExtContext) error {
+		st := s.Tran(tx)
+		for i := range transactions {
+			var txnExtras txnfields.TransactionExtras
+			if i < len(extras) {
+				txnExtras = extras[i]
+			}
+			if _, err := st.putTransaction(ctx, txndb.NewTransactionDoc(transactions[i], txnExtras, blockInfo)); err != nil {
+				return fmt.Errorf("inserting transaction %s: %w", transactions[i].Id, err)
+			}
+		}
+		return nil
+	}
+
+	if err := s.WithinTran(ctx, tran); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// putTransaction upserts a transaction document under the transaction ID and
+// lists it under the accounts, assets and applications it involves. The
+// transaction is pending until CommitTransactions is called for its round,
+// unless the block of the round is already committed. It returns the new
+// revision of the document and has to run within a transaction.
+func (s Store) putTransaction(ctx context.Context, newDoc txndb.NewTransaction) (string, error) {
+	committed, err := s.roundCommitted(ctx, newDoc.ConfirmedRound)
+	if err != nil {
+		return "", err
+	}
+	newDoc.Pending = !committed
+
+	doc, err := encode(newDoc)
+	if err != nil {
+		return "", err
+	}
+
+	assets := make(pq.Int64Array, len(newDoc.AssociatedAssets))
+	for i, id := range newDoc.AssociatedAssets {
+		assets[i] = int64(id)
+	}
+	apps := make(pq.Int64Array, len(newDoc.AssociatedApplications))
+	for i, id := range newDoc.AssociatedApplications {
+		apps[i] = int64(id)
+	}
+
+	data := struct {
+		TransactionID    string         `db:"transaction_id"`
+		Round            uint64         `db:"round"`
+		IntraRoundOffset uint64         `db:"intra_round_offset"`
+		RoundTime        uint64         `db:"round_time"`
+		TxType           string         `db:"tx_type"`
+		Pending          bool           `db:"pending"`
+		Doc              string         `db:"doc"`
+		Accounts         pq.StringArray `db:"accounts"`
+		Assets           pq.Int64Array  `db:"assets"`
+		Apps             pq.Int64Array  `db:"apps"`
+	}{
+		TransactionID:    newDoc.Id,
+		Round:            newDoc.ConfirmedRound,
+		IntraRoundOffset: newDoc.IntraRoundOffset,
+		RoundTime:        newDoc.RoundTime,
+		TxType:           newDoc.Type,
+		Pending:          newDoc.Pending,
+		Doc:              doc,
+		Accounts:         pq.StringArray(newDoc.AssociatedAccounts),
+		Assets:           assets,
+		Apps:             apps,
+	}
+
+	const qTxn = `
+	INSERT INTO transactions
+		(transaction_id, round, intra_round_offset, round_time, tx_type, pending, revision, doc)
+	VALUES
+		(:transaction_id, :round, :intra_round_offset, :round_time, :tx_type, :pending, 1, :doc)
+	ON CONFLICT (transaction_id) DO UPDATE SET
+		round = EXCLUDED.round,
+		intra_round_offset = EXCLUDED.intra_round_offset,
+		round_time = EXCLUDED.round_time,
+		tx_type = EXCLUDED.tx_type,
+		pending = EXCLUDED.pending,
+		revision = transactions.revision + 1,
+		doc = EXCLUDED.doc
+	RETURNING
+		revision`
+
+	var rev revision
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, qTxn, data, &rev); err != nil {
+		return "", err
+	}
+
+	// The transaction is listed again from scratch, in case it is stored over
+	// one involving other accounts, assets or applications.
+	const qUnlink = `
+	WITH accounts AS (
+		DELETE FROM transaction_accounts WHERE transaction_id = :transaction_id
+	), assets AS (
+		DELETE FROM transaction_assets WHERE transaction_id = :transaction_id
+	)
+	DELETE FROM
+		transaction_applications
+	WHERE
+		transaction_id = :transaction_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, qUnlink, data); err != nil {
+		return "", fmt.Errorf("unlinking transaction: %w", err)
+	}
+
+	const qAccounts = `
+	INSERT INTO transaction_accounts
+		(address, transaction_id, round, intra_round_offset)
+	SELECT
+		address, :transaction_id, CAST(:round AS BIGINT), CAST(:intra_round_offset AS BIGINT)
+	FROM
+		unnest(CAST(:accounts AS TEXT[])) AS address
+	ON CONFLICT DO NOTHING`
+
+	const qAssets = `
+	INSERT INTO transaction_assets
+		(asset_id, transaction_id, round, intra_round_offset)
+	SELECT
+		asset_id, :transaction_id, CAST(:round AS BIGINT), CAST(:intra_round_offset AS BIGINT)
+	FROM
+		unnest(CAST(:assets AS BIGINT[])) AS asset_id
+	ON CONFLICT DO NOTHING`
+
+	const qApps = `
+	INSERT INTO transaction_applications
+		(app_id, transaction_id, round, intra_round_offset)
+	SELECT
+		app_id, :transaction_id, CAST(:round AS BIGINT), CAST(:intra_round_offset AS BIGINT)
+	FROM
+		unnest(CAST(:apps AS BIGINT[])) AS app_id
+	ON CONFLICT DO NOTHING`
+
+	for _, q := range []string{qAccounts, qAssets, qApps} {
+		if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+			return "", fmt.Errorf("linking transaction: %w", err)
+		}
+	}
+
+	return strconv.Itoa(rev.Revision), nil
+}
+
+// GetTransaction retrieves a transaction based upon its ID.
+func (s Store) GetTransaction(ctx context.Context, transactionID string) (txndb.Transaction, error) {
+	data := struct {
+		TransactionID string `db:"transaction_id"`
+	}{
+		TransactionID: transactionID,
+	}
+
+	const q = `
+	SELECT
+		transaction_id AS id, revision, doc
+	FROM
+		transactions
+	WHERE
+		transaction_id = :transaction_id`
+
+	var doc document
+	missing := func() error { return notFound("transaction %s not found", transactionID) }
+	if err := s.queryStruct(ctx, q, data, &doc, missing); err != nil {
+		return txndb.Transaction{}, fmt.Errorf("selecting transaction %s: %w", transactionID, err)
+	}
+
+	txn, err := toTransaction(doc)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	if txn.Pending {
+		return txndb.Transaction{}, txndb.ErrNotCommitted
+	}
+
+	return txn, nil
+}
+
+// GetInnerTransaction retrieves an inner transaction based upon the ID of the
+// top-level transaction it belongs to and its path.
+func (s Store) GetInnerTransaction(ctx context.Context, parentID string, path []int) (models.Transaction, error) {
+	parent, err := s.GetTransaction(ctx, parentID)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	txn, ok := txndb.InnerTxnAt(parent.Transaction, path)
+	if !ok {
+		return models.Transaction{}, fmt.Errorf("transaction %s, path %v: %w", parentID, path, txndb.ErrInnerTxnNotFound)
+	}
+	return txn, nil
+}
+
+// GetTransactionCountBtnKeys retrieves the number of transactions from the one
+// with ID startKey to the one with ID endKey, both included.
+func (s Store) GetTransactionCountBtnKeys(ctx context.Context, startKey, endKey string) (int64, error) {
+	return s.countBetween(ctx, allTxns, nil, startKey, endKey)
+}
+
+// GetEarliestTransaction retrieves the first transaction of the lowest round.
+func (s Store) GetEarliestTransaction(ctx context.Context) (txndb.Transaction, error) {
+	return s.firstOf(ctx, allTxns, nil, "asc")
+}
+
+// GetLatestTransaction retrieves the last transaction of the highest round.
+func (s Store) GetLatestTransaction(ctx context.Context) (txndb.Transaction, error) {
+	return s.firstOf(ctx, allTxns, nil, "desc")
+}
+
+// GetTransactionsPagination retrieves a page of transactions. Like the CouchDB
+// store, pages are counted from the latest transaction whatever the starting
+// transaction given.
+func (s Store) GetTransactionsPagination(ctx context.Context, startTransactionID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	return s.pageOf(ctx, allTxns, nil, order, pageNo, limit)
+}
+
+// GetTransactionsFrom retrieves up to limit transactions read from cur, or from
+// the start of the list when cur is nil, in the given order. The boolean
+// returned reports whether transactions are left past them in the direction
+// they were read in.
+func (s Store) GetTransactionsFrom(ctx context.Context, cur *cursor.Cursor, order string, limit int64) ([]txndb.Transaction, bool, error) {
+	return s.transactionsFrom(ctx, allTxns, nil, cur, order, limit)
+}
+
+// GetTransactionCountByRound retrieves the number of transactions confirmed in
+// a round.
+func (s Store) GetTransactionCountByRound(ctx context.Context, round uint64) (int64, error) {
+	return s.countOf(ctx, roundTxns, round)
+}
+
+// GetTransactionsByRoundPagination retrieves a page of the transactions
+// confirmed in a round.
+func (s Store) GetTransactionsByRoundPagination(ctx context.Context, round uint64, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	return s.pageOf(ctx, roundTxns, round, order, pageNo, limit)
+}
+
+// GetEarliestAcctTransaction retrieves the earliest transaction of an account.
+func (s Store) GetEarliestAcctTransaction(ctx context.Context, acctID string) (txndb.Transaction, error) {
+	return s.firstOf(ctx, acctTxns, acctID, "asc")
+}
+
+// GetLatestAcctTransaction retrieves the latest transaction of an account.
+func (s Store) GetLatestAcctTransaction(ctx context.Context, acctID string) (txndb.Transaction, error) {
+	return s.firstOf(ctx, acctTxns, acctID, "desc")
+}
+
+// GetTransactionCountByAcct retrieves the number of transactions of an account
+// from the one with ID startKey to the one with ID endKey, both included.
+func (s Store) GetTransactionCountByAcct(ctx context.Context, acctID, startKey, endKey string) (int64, error) {
+	return s.countBetween(ctx, acctTxns, acctID, startKey, endKey)
+}
+
+// GetTransactionsByAcctPagination retrieves a page of the transactions of an
+// account.
+func (s Store) GetTransactionsByAcctPagination(ctx context.Context, acctID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	return s.pageOf(ctx, acctTxns, acctID, order, pageNo, limit)
+}
+
+// GetTransactionsByAcctFrom retrieves up to limit transactions of an account
+// read from cur, or from the start of the list when cur is nil, in the given
+// order. The boolean returned reports whether transactions are left past them
+// in the direction they were read in.
+func (s Store) GetTransactionsByAcctFrom(ctx context.Context, acctID string, cur *cursor.Cursor, order string, limit int64) ([]txndb.Transaction, bool, error) {
+	return s.transactionsFrom(ctx, acctTxns, acctID, cur, order, limit)
+}
+
+// GetTransactionsByAcct retrieves every transaction of an account, in ascending
+// order when order is "asc" and descending order otherwise.
+func (s Store) GetTransactionsByAcct(ctx context.Context, acctID string, order string) ([]txndb.Transaction, error) {
+	return s.transactionsOf(ctx, acctTxns, acctID, descUnlessAsc(order), 0, nil)
+}
+
+// GetEarliestAppTransaction retrieves the earliest transaction of an application.
+func (s Store) GetEarliestAppTransaction(ctx context.Context, appID string) (txndb.Transaction, error) {
+	id, err := parseID(appID)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	return s.firstOf(ctx, appTxns, id, "asc")
+}
+
+// GetLatestAppTransaction retrieves the latest transaction of an application.
+func (s Store) GetLatestAppTransaction(ctx context.Context, appID string) (txndb.Transaction, error) {
+	id, err := parseID(appID)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	return s.firstOf(ctx, appTxns, id, "desc")
+}
+
+// GetTransactionsByApp retrieves every transaction of an application, in
+// ascending order when order is "asc" and descending order otherwise.
+func (s Store) GetTransactionsByApp(ctx context.Context, appID string, order string) ([]txndb.Transaction, error) {
+	id, err := parseID(appID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transactionsOf(ctx, appTxns, id, descUnlessAsc(order), 0, nil)
+}
+
+// GetTransactionCountByApp retrieves the number of transactions of an
+// application.
+func (s Store) GetTransactionCountByApp(ctx context.Context, appID string) (int64, error) {
+	id, err := parseID(appID)
+	if err != nil {
+		return 0, err
+	}
+	return s.countOf(ctx, appTxns, id)
+}
+
+// GetTransactionsByAppPagination retrieves a page of the transactions of an
+// application.
+func (s Store) GetTransactionsByAppPagination(ctx context.Context, appID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	id, err := parseID(appID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return s.pageOf(ctx, appTxns, id, order, pageNo, limit)
+}
+
+// GetEarliestAssetTransaction retrieves the earliest transaction of an asset.
+func (s Store) GetEarliestAssetTransaction(ctx context.Context, assetID string) (txndb.Transaction, error) {
+	id, err := parseID(assetID)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	return s.firstOf(ctx, assetTxns, id, "asc")
+}
+
+// GetLatestAssetTransaction retrieves the latest transaction of an asset.
+func (s Store) GetLatestAssetTransaction(ctx context.Context, assetID string) (txndb.Transaction, error) {
+	id, err := parseID(assetID)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	return s.firstOf(ctx, assetTxns, id, "desc")
+}
+
+// GetTransactionsByAsset retrieves every transaction of an asset, in ascending
+// order when order is "asc" and descending order otherwise.
+func (s Store) GetTransactionsByAsset(ctx context.Context, assetID string, order string) ([]txndb.Transaction, error) {
+	id, err := parseID(assetID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transactionsOf(ctx, assetTxns, id, descUnlessAsc(order), 0, nil)
+}
+
+// GetTransactionCountByAsset retrieves the number of transactions of an asset.
+func (s Store) GetTransactionCountByAsset(ctx context.Context, assetID string) (int64, error) {
+	id, err := parseID(assetID)
+	if err != nil {
+		return 0, err
+	}
+	return s.countOf(ctx, assetTxns, id)
+}
+
+// GetTransactionsByAssetPagination retrieves a page of the transactions of an
+// asset.
+func (s Store) GetTransactionsByAssetPagination(ctx context.Context, assetID, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	id, err := parseID(assetID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return s.pageOf(ctx, assetTxns, id, order, pageNo, limit)
+}
+
+// GetTransactionsByType retrieves up to limit transactions of a type, in
+// ascending order when order is "asc" and descending order otherwise.
+func (s Store) GetTransactionsByType(ctx context.Context, txnType string, order string, limit int64) ([]txndb.Transaction, error) {
+	var upTo *int64
+	if limit >= 0 {
+		upTo = &limit
+	}
+	return s.transactionsOf(ctx, typeTxns, txnType, descUnlessAsc(order), 0, upTo)
+}
+
+// GetTransactionsByGroup retrieves the transactions of an atomic group, given
+// the base64 of its ID, ordered by round and by position within the round.
+func (s Store) GetTransactionsByGroup(ctx context.Context, groupID string) ([]txndb.Transaction, error) {
+	return s.transactionsOf(ctx, groupTxns, groupID, "asc", 0, nil)
+}
+
+// DeleteTransactionsBefore deletes every transaction confirmed before round and
+// returns how many were deleted.
+func (s Store) DeleteTransactionsBefore(ctx context.Context, round uint64) (int, error) {
+	data := struct {
+		Round uint64 `db:"round"`
+	}{
+		Round: round,
+	}
+
+	const q = `
+	WITH deleted AS (
+		DELETE FROM
+			transactions
+		WHERE
+			round < :round
+		RETURNING
+			transaction_id
+	)
+	SELECT
+		count(*) AS count
+	FROM
+		deleted`
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &c); err != nil {
+		return 0, fmt.Errorf("deleting transactions before round %d: %w", round, err)
+	}
+
+	return int(c.Count), nil
+}
+
+// CommitTransactions marks the pending transactions of round as committed.
+func (s Store) CommitTransactions(ctx context.Context, round uint64) error {
+	data := struct {
+		Round uint64 `db:"round"`
+	}{
+		Round: round,
+	}
+
+	const q = `
+	UPDATE
+		transactions
+	SET
+		pending = false,
+		revision = revision + 1,
+		doc = doc - 'pending'
+	WHERE
+		round = :round AND pending`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("committing transactions of round %d: %w", round, err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// transactionsOf retrieves the transactions of a scope selected by key, in
+// the given order, skipping offset of them and returning up to limit of them
+// when limit is set.
+func (s Store) transactionsOf(ctx context.Context, sc txnScope, key interface{}, order string, offset int64, limit *int64) ([]txndb.Transaction, error) {
+	data := map[string]interface{}{
+		"key":    key,
+		"offset": offset,
+		"limit":  limit,
+	}
+
+	var docs []document
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, sc.selectQuery(order), data, &docs); err != nil {
+		return nil, fmt.Errorf("selecting transactions: %w", err)
+	}
+
+	var fetchedTransactions = []txndb.Transaction{}
+	for _, doc := range docs {
+		txn, err := toTransaction(doc)
+		if err != nil {
+			return nil, err
+		}
+		fetchedTransactions = append(fetchedTransactions, txn)
+	}
+
+	return fetchedTransactions, nil
+}
+
+// transactionsFrom retrieves the page of the transactions of a scope selected
+// by key read from cur, along with whether transactions are left past it in
+// the direction it was read in. The scope has to be ordered by round.
+func (s Store) transactionsFrom(ctx context.Context, sc txnScope, key interface{}, cur *cursor.Cursor, order string, limit int64) ([]txndb.Transaction, bool, error) {
+	if limit < 1 {
+		return nil, false, cursor.ErrLimit
+	}
+
+	data := map[string]interface{}{
+		"key":   key,
+		"limit": limit + 1,
+	}
+	if cur != nil {
+		data["round"] = cur.Round
+		data["offset"] = cur.Offset
+		data["id"] = cur.ID
+	}
+
+	dir, op := readingOrder(cur, order)
+
+	var docs []document
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, sc.fromQuery(dir, op, cur != nil), data, &docs); err != nil {
+		return nil, false, fmt.Errorf("selecting transactions: %w", err)
+	}
+	docs, more := pageFrom(docs, cur, limit)
+
+	var fetchedTransactions = []txndb.Transaction{}
+	for _, doc := range docs {
+		txn, err := toTransaction(doc)
+		if err != nil {
+			return nil, false, err
+		}
+		fetchedTransactions = append(fetchedTransactions, txn)
+	}
+
+	return fetchedTransactions, more, nil
+}
+
+// firstOf retrieves the first transaction of a scope selected by key in the
+// given order.
+func (s Store) firstOf(ctx context.Context, sc txnScope, key interface{}, order string) (txndb.Transaction, error) {
+	one := int64(1)
+	txns, err := s.transactionsOf(ctx, sc, key, order, 0, &one)
+	if err != nil {
+		return txndb.Transaction{}, err
+	}
+	if len(txns) == 0 {
+		return txndb.Transaction{}, notFound("no transaction found")
+	}
+
+	return txns[0], nil
+}
+
+// pageOf retrieves a page of the transactions of a scope selected by key,
+// counted from the latest one when order is "desc", along with the number of
+// pages and of transactions.
+func (s Store) pageOf(ctx context.Context, sc txnScope, key interface{}, order string, pageNo, limit int64) ([]txndb.Transaction, int64, int64, error) {
+	numOfTransactions, err := s.countOf(ctx, sc, key)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if numOfTransactions == 0 {
+		return nil, 0, 0, notFound("no transaction found")
+	}
+
+	offset, _, numOfPages, err := store.Page(numOfTransactions, pageNo, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	txns, err := s.transactionsOf(ctx, sc, key, order, offset, &limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return txns, numOfPages, numOfTransactions, nil
+}
+
+// countOf counts the transactions of a scope selected by key.
+func (s Store) countOf(ctx context.Context, sc txnScope, key interface{}) (int64, error) {
+	data := map[string]interface{}{
+		"key": key,
+	}
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, sc.countQuery(false), data, &c); err != nil {
+		return 0, fmt.Errorf("counting transactions: %w", err)
+	}
+
+	return c.Count, nil
+}
+
+// countBetween counts the transactions of a scope selected by key, from the one
+// with ID startKey to the one with ID endKey, both included. Both have to
+// exist. The scope has to be ordered by round.
+func (s Store) countBetween(ctx context.Context, sc txnScope, key interface{}, startKey, endKey string) (int64, error) {
+	first, err := s.txnPosition(ctx, startKey)
+	if err != nil {
+		return 0, fmt.Errorf("fetch earliest transaction error: %w", err)
+	}
+	last, err := s.txnPosition(ctx, endKey)
+	if err != nil {
+		return 0, fmt.Errorf("fetch latest transaction error: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"key":          key,
+		"first_round":  first.Round,
+		"first_offset": first.IntraRoundOffset,
+		"first_id":     first.TransactionID,
+		"last_round":   last.Round,
+		"last_offset":  last.IntraRoundOffset,
+		"last_id":      last.TransactionID,
+	}
+
+	var c count
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, sc.countQuery(true), data, &c); err != nil {
+		return 0, fmt.Errorf("counting transactions: %w", err)
+	}
+
+	return c.Count, nil
+}
+
+// position is where a transaction stands in the order of transactions.
+type position struct {
+	TransactionID    string `db:"transaction_id"`
+	Round            uint64 `db:"round"`
+	IntraRoundOffset uint64 `db:"intra_round_offset"`
+}
+
+// txnPosition retrieves where the transaction with the given ID stands.
+func (s Store) txnPosition(ctx context.Context, transactionID string) (position, error) {
+	data := struct {
+		TransactionID string `db:"transaction_id"`
+	}{
+		TransactionID: transactionID,
+	}
+
+	const q = `
+	SELECT
+		transaction_id, round, intra_round_offset
+	FROM
+		transactions
+	WHERE
+		transaction_id = :transaction_id`
+
+	var pos position
+	missing := func() error { return notFound("transaction %s not found", transactionID) }
+	if err := s.queryStruct(ctx, q, data, &pos, missing); err != nil {
+		return position{}, err
+	}
+
+	return pos, nil
+}
+
+// descUnlessAsc returns the order to list transactions in for the methods
+// listing them in ascending order for "asc" and descending order otherwise.
+func descUnlessAsc(order string) string {
+	if order == "asc" {
+		return "asc"
+	}
+	return "desc"
+}
+
+// toTransaction decodes a transaction document.
+func toTransaction(doc document) (txndb.Transaction, error) {
+	var txn txndb.Transaction
+	if err := doc.decode(&txn); err != nil {
+		return txndb.Transaction{}, err
+	}
+	txn.ID = doc.ID
+	txn.Rev = doc.rev()
+
+	return txn, nil
+}
@@ -0,0 +1,76 @@
+// Package store provides what the storage backends other than CouchDB have in
+// common: the errors they report the way CouchDB does, and the paging and ID
+// parsing every one of them needs.
+package store
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// StatusError is an error carrying the HTTP status CouchDB answers with in the
+// same situation, so callers checking kivik.StatusCode treat every store like
+// the CouchDB one.
+type StatusError struct {
+	Status int
+	Msg    string
+
+	// Err is the error the store reports underneath, if any.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return e.Msg
+}
+
+// StatusCode returns the HTTP status of the error.
+func (e *StatusError) StatusCode() int {
+	return e.Status
+}
+
+// Unwrap returns the error the store reports underneath.
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// NotFound returns the error reported when a document doesn't exist.
+func NotFound(format string, args ...interface{}) error {
+	return &StatusError{Status: http.StatusNotFound, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Page works out which of n ordered entries make up page pageNo, limit entries
+// per page, and how many pages there are. Like the CouchDB stores, it fails
+// for pages out of range.
+func Page(n, pageNo, limit int64) (from, to, numOfPages int64, err error) {
+	if limit < 1 {
+		return 0, 0, 0, errors.New("limit is less than 1")
+	}
+
+	numOfPages = n / limit
+	if n%limit > 0 {
+		numOfPages += 1
+	}
+	if pageNo < 1 || pageNo > numOfPages {
+		return 0, 0, 0, fmt.Errorf("page number is less than 1 or exceeds page limit: %d", numOfPages)
+	}
+
+	from = (pageNo - 1) * limit
+	to = from + limit
+	if to > n {
+		to = n
+	}
+	return from, to, numOfPages, nil
+}
+
+// ParseID parses the ID of an asset or application, which are stored under
+// their index.
+func ParseID(id string) (uint64, error) {
+	index, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ID %q: %w", id, err)
+	}
+	return index, nil
+}
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kevguy/algosearch/backend/foundation/web"
+)
+
+// PageQueries parses the limit, order and page query parameters of a page of
+// a list. order defaults to "desc". Missing or invalid parameters are reported
+// as request errors.
+func PageQueries(r *http.Request) (limit int64, order string, page int64, err error) {
+
+	// limit
+	limitQueries := web.Query(r, "limit")
+	if len(limitQueries) == 0 {
+		return 0, "", 0, NewRequestError(fmt.Errorf("missing query parameter: limit"), http.StatusBadRequest)
+	}
+	limit, err = strconv.ParseInt(limitQueries[0], 10, 64)
+	if err != nil || limit < 1 {
+		return 0, "", 0, NewRequestError(fmt.Errorf("invalid 'limit' format: %s", limitQueries[0]), http.StatusBadRequest)
+	}
+
+	// order
+	order = "desc"
+	orderQueries := web.Query(r, "order")
+	if len(orderQueries) > 0 {
+		order = orderQueries[0]
+	}
+	if order != "asc" && order != "desc" {
+		return 0, "", 0, NewRequestError(fmt.Errorf("invalid 'order' format: %s", order), http.StatusBadRequest)
+	}
+
+	// page
+	pageQueries := web.Query(r, "page")
+	if len(pageQueries) == 0 {
+		return 0, "", 0, NewRequestError(fmt.Errorf("missing query parameter: page"), http.StatusBadRequest)
+	}
+	page, err = strconv.ParseInt(pageQueries[0], 10, 64)
+	if err != nil {
+		return 0, "", 0, NewRequestError(fmt.Errorf("invalid 'page' format: %s", pageQueries[0]), http.StatusBadRequest)
+	}
+
+	return limit, order, page, nil
+}
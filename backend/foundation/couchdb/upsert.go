@@ -0,0 +1,163 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	kivik "github.com/go-kivik/kivik/v4"
+)
+
+// upsertAttempts is how many times a document is written when its revision
+// keeps changing under a concurrent writer.
+const upsertAttempts = 3
+
+// BulkError reports the documents a bulk write could not save, keyed by ID.
+type BulkError struct {
+	Failed map[string]error
+}
+
+// Error implements the error interface. It names the first few documents that
+// failed.
+func (e *BulkError) Error() string {
+	ids := make([]string, 0, len(e.Failed))
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	const shown = 3
+	var msgs []string
+	for i, id := range ids {
+		if i == shown {
+			msgs = append(msgs, fmt.Sprintf("and %d more", len(ids)-shown))
+			break
+		}
+		msgs = append(msgs, fmt.Sprintf("%s: %v", id, e.Failed[id]))
+	}
+	return fmt.Sprintf("%d document(s) not saved: %s", len(ids), strings.Join(msgs, "; "))
+}
+
+// Revisions returns the current revision of each of the documents with the
+// given IDs. Documents that don't exist, or were deleted, are left out.
+func Revisions(ctx context.Context, db *kivik.DB, ids []string) (map[string]string, error) {
+	revs := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return revs, nil
+	}
+
+	rows, err := db.AllDocs(ctx, kivik.Options{"keys": ids})
+	if err != nil {
+		return nil, fmt.Errorf("fetching revisions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		// Rows of missing documents carry an error and no ID.
+		if rows.ID() == "" {
+			continue
+		}
+		var value struct {
+			Rev     string `json:"rev"`
+			Deleted bool   `json:"deleted"`
+		}
+		if err := rows.ScanValue(&value); err != nil {
+			return nil, fmt.Errorf("unpacking revision of %s: %w", rows.ID(), err)
+		}
+		if !value.Deleted {
+			revs[rows.ID()] = value.Rev
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("fetching revisions: %w", err)
+	}
+
+	return revs, nil
+}
+
+// Upsert writes doc under docID, replacing the current revision if the document
+// already exists. It returns the new revision.
+func Upsert(ctx context.Context, db *kivik.DB, docID string, doc interface{}) (string, error) {
+	for attempt := 1; ; attempt++ {
+		revs, err := Revisions(ctx, db, []string{docID})
+		if err != nil {
+			return "", err
+		}
+
+		var opts kivik.Options
+		if rev, ok := revs[docID]; ok {
+			opts = kivik.Options{"rev": rev}
+		}
+
+		rev, err := db.Put(ctx, docID, doc, opts)
+		if kivik.StatusCode(err) == http.StatusConflict && attempt < upsertAttempts {
+			continue
+		}
+		return rev, err
+	}
+}
+
+// BulkUpsert writes a batch of documents, replacing the current revision of
+// those that already exist. ids holds the ID of each document, and doc builds
+// the document at index i carrying revision rev, which is empty for new
+// documents. The result of every document is checked, those that could not be
+// saved are reported in a *BulkError.
+func BulkUpsert(ctx context.Context, db *kivik.DB, ids []string, doc func(i int, rev string) interface{}) error {
+	pending := make([]int, len(ids))
+	for i := range ids {
+		pending[i] = i
+	}
+
+	failed := make(map[string]error)
+	for attempt := 1; len(pending) > 0; attempt++ {
+		pendingIDs := make([]string, len(pending))
+		index := make(map[string]int, len(pending))
+		for j, i := range pending {
+			pendingIDs[j] = ids[i]
+			index[ids[i]] = i
+		}
+
+		revs, err := Revisions(ctx, db, pendingIDs)
+		if err != nil {
+			return err
+		}
+
+		docs := make([]interface{}, len(pending))
+		for j, i := range pending {
+			docs[j] = doc(i, revs[ids[i]])
+		}
+
+		results, err := db.BulkDocs(ctx, docs)
+		if err != nil {
+			return fmt.Errorf("bulk writing documents: %w", err)
+		}
+
+		var conflicted []int
+		for results.Next() {
+			err := results.UpdateErr()
+			if err == nil {
+				continue
+			}
+			if i, ok := index[results.ID()]; ok && kivik.StatusCode(err) == http.StatusConflict && attempt < upsertAttempts {
+				conflicted = append(conflicted, i)
+				continue
+			}
+			failed[results.ID()] = err
+		}
+		// Close the response before the next attempt or returning.
+		err = results.Err()
+		results.Close()
+		if err != nil {
+			return fmt.Errorf("reading bulk write results: %w", err)
+		}
+
+		pending = conflicted
+	}
+
+	if len(failed) > 0 {
+		return &BulkError{Failed: failed}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package couchdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	kivik "github.com/go-kivik/kivik/v4"
+	"github.com/kevguy/algosearch/backend/foundation/couchdb"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+// fakeCouch is a CouchDB server holding the revisions of the documents of a
+// single database, answering the requests the upsert helpers make.
+type fakeCouch struct {
+	mu      sync.Mutex
+	revs    map[string]int
+	deleted map[string]bool
+
+	// conflicts is how many more writes of a document a concurrent writer
+	// gets in first, and forbidden holds the documents no write is allowed to.
+	conflicts map[string]int
+	forbidden map[string]bool
+}
+
+func newFakeCouch() *fakeCouch {
+	return &fakeCouch{
+		revs:      make(map[string]int),
+		deleted:   make(map[string]bool),
+		conflicts: make(map[string]int),
+		forbidden: make(map[string]bool),
+	}
+}
+
+// open starts serving the fake and returns its database.
+func (f *fakeCouch) open(t *testing.T) *kivik.DB {
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	client, err := kivik.New("couch", srv.URL)
+	if err != nil {
+		t.Fatalf("connecting to the fake CouchDB: %v", err)
+	}
+	return client.DB("test")
+}
+
+// rev returns the revision a document gets on its gen-th write.
+func rev(gen int) string {
+	return fmt.Sprintf("%d-abc", gen)
+}
+
+// write saves a new revision of id over the revision at, reporting the status
+// and reason when it can't.
+func (f *fakeCouch) write(id, at string) (string, int, string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.forbidden[id] {
+		return "", http.StatusForbidden, "forbidden"
+	}
+	if f.conflicts[id] > 0 {
+		f.conflicts[id]--
+		f.revs[id]++
+		return "", http.StatusConflict, "conflict"
+	}
+	current := ""
+	if gen, ok := f.revs[id]; ok && !f.deleted[id] {
+		current = rev(gen)
+	}
+	if at != current {
+		return "", http.StatusConflict, "conflict"
+	}
+
+	f.revs[id]++
+	delete(f.deleted, id)
+	return rev(f.revs[id]), http.StatusCreated, ""
+}
+
+func (f *fakeCouch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	path := strings.TrimPrefix(r.URL.Path, "/test/")
+
+	switch {
+	case path == "_all_docs":
+		var body struct {
+			Keys []string `json:"keys"`
+		}
+		if r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&body)
+		} else {
+			json.Unmarshal([]byte(r.URL.Query().Get("keys")), &body.Keys)
+		}
+
+		f.mu.Lock()
+		rows := make([]interface{}, 0, len(body.Keys))
+		for _, id := range body.Keys {
+			gen, ok := f.revs[id]
+			if !ok {
+				rows = append(rows, map[string]interface{}{"key": id, "error": "not_found"})
+				continue
+			}
+			value := map[string]interface{}{"rev": rev(gen)}
+			if f.deleted[id] {
+				value["deleted"] = true
+			}
+			rows = append(rows, map[string]interface{}{"id": id, "key": id, "value": value})
+		}
+		f.mu.Unlock()
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"total_rows": len(rows), "offset": 0, "rows": rows})
+
+	case path == "_bulk_docs":
+		var body struct {
+			Docs []struct {
+				ID  string `json:"_id"`
+				Rev string `json:"_rev"`
+			} `json:"docs"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		results := make([]interface{}, 0, len(body.Docs))
+		for _, doc := range body.Docs {
+			newRev, _, reason := f.write(doc.ID, doc.Rev)
+			if reason != "" {
+				results = append(results, map[string]interface{}{"id": doc.ID, "error": reason, "reason": reason})
+				continue
+			}
+			results = append(results, map[string]interface{}{"ok": true, "id": doc.ID, "rev": newRev})
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(results)
+
+	case r.Method == http.MethodPut:
+		newRev, status, reason := f.write(path, r.URL.Query().Get("rev"))
+		w.WriteHeader(status)
+		if reason != "" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": reason, "reason": reason})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "id": path, "rev": newRev})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": "not_found", "reason": "missing"})
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	t.Log("Given the need to know the current revision of documents.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen some of the documents are missing or deleted.", testID)
+		{
+			f := newFakeCouch()
+			f.revs["A"], f.revs["B"], f.revs["GONE"] = 1, 3, 2
+			f.deleted["GONE"] = true
+			db := f.open(t)
+
+			revs, err := couchdb.Revisions(context.Background(), db, []string{"A", "MISSING", "B", "GONE"})
+			if err != nil {
+				t.Fatalf("\t\t%s\tTest %d:\tShould be able to fetch the revisions : %v.", failed, testID, err)
+			}
+			if want := map[string]string{"A": rev(1), "B": rev(3)}; !reflect.DeepEqual(revs, want) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould leave missing and deleted documents out : got %v, want %v.", failed, testID, revs, want)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould leave missing and deleted documents out.", success, testID)
+		}
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	t.Log("Given the need to write documents whether or not they exist.")
+	{
+		ctx := context.Background()
+		doc := map[string]string{"doc_type": "test"}
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the document is new or already exists.", testID)
+		{
+			f := newFakeCouch()
+			f.revs["OLD"] = 4
+			db := f.open(t)
+
+			if got, err := couchdb.Upsert(ctx, db, "NEW", doc); err != nil || got != rev(1) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould create the new document : got %q, %v.", failed, testID, got, err)
+			}
+			if got, err := couchdb.Upsert(ctx, db, "OLD", doc); err != nil || got != rev(5) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould replace the existing document : got %q, %v.", failed, testID, got, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould create or replace the document.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen another writer changes the document in between.", testID)
+		{
+			f := newFakeCouch()
+			f.revs["DOC"] = 1
+			f.conflicts["DOC"] = 2
+			db := f.open(t)
+
+			if got, err := couchdb.Upsert(ctx, db, "DOC", doc); err != nil || got != rev(4) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould retry over the new revision : got %q, %v.", failed, testID, got, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould retry over the new revision.", success, testID)
+
+			f.conflicts["DOC"] = 3
+			if _, err := couchdb.Upsert(ctx, db, "DOC", doc); kivik.StatusCode(err) != http.StatusConflict {
+				t.Fatalf("\t\t%s\tTest %d:\tShould give up on a document that keeps changing : got %v.", failed, testID, err)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould give up on a document that keeps changing.", success, testID)
+		}
+	}
+}
+
+func TestBulkUpsert(t *testing.T) {
+	t.Log("Given the need to write batches of documents whether or not they exist.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen some documents conflict and one can't be saved.", testID)
+		{
+			f := newFakeCouch()
+			f.revs["OLD"], f.revs["BUSY"] = 2, 1
+			f.conflicts["BUSY"] = 1
+			f.forbidden["DENIED"] = true
+			db := f.open(t)
+
+			ids := []string{"NEW", "OLD", "BUSY", "DENIED"}
+			err := couchdb.BulkUpsert(context.Background(), db, ids, func(i int, rev string) interface{} {
+				doc := map[string]string{"_id": ids[i], "doc_type": "test"}
+				if rev != "" {
+					doc["_rev"] = rev
+				}
+				return doc
+			})
+
+			var bulkErr *couchdb.BulkError
+			if !errors.As(err, &bulkErr) {
+				t.Fatalf("\t\t%s\tTest %d:\tShould report the documents not saved : got %v.", failed, testID, err)
+			}
+			if _, ok := bulkErr.Failed["DENIED"]; !ok || len(bulkErr.Failed) != 1 {
+				t.Fatalf("\t\t%s\tTest %d:\tShould report only the document not saved : got %v.", failed, testID, bulkErr)
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould report only the document not saved.", success, testID)
+
+			want := map[string]int{"NEW": 1, "OLD": 3, "BUSY": 3}
+			for id, gen := range want {
+				if f.revs[id] != gen {
+					t.Fatalf("\t\t%s\tTest %d:\tShould save %s : got revision %d, want %d.", failed, testID, id, f.revs[id], gen)
+				}
+			}
+			t.Logf("\t\t%s\tTest %d:\tShould save the other documents, retrying the conflicting one.", success, testID)
+		}
+	}
+}
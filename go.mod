@@ -4,6 +4,7 @@ go 1.17
 
 require (
 	github.com/algorand/go-algorand-sdk v1.15.0
+	github.com/algorand/go-codec/codec v1.1.8
 	github.com/ardanlabs/conf/v2 v2.2.0
 	github.com/ardanlabs/darwin v1.3.0
 	github.com/dimfeld/httptreemux/v5 v5.4.0
@@ -20,6 +21,7 @@ require (
 	github.com/lib/pq v1.10.3
 	github.com/nsf/jsondiff v0.0.0-20200515183724-f29ed568f4ce
 	github.com/pkg/errors v0.9.1
+	go.etcd.io/bbolt v1.3.6
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.28.0
 	go.opentelemetry.io/otel v1.3.0
 	go.opentelemetry.io/otel/exporters/zipkin v1.3.0
@@ -28,11 +30,9 @@ require (
 	go.uber.org/automaxprocs v1.4.0
 	go.uber.org/zap v1.20.0
 	golang.org/x/crypto v0.0.0-20211215153901-e495a2d5b3d3
-
 )
 
 require (
-	github.com/algorand/go-codec/codec v1.1.8 // indirect
 	github.com/felixge/httpsnoop v1.0.2 // indirect
 	github.com/go-logr/logr v1.2.1 // indirect
 	github.com/go-logr/stdr v1.2.0 // indirect